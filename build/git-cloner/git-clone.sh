@@ -3,24 +3,160 @@
 # 从环境变量中获取仓库URL和本地路径
 repo_url="$REPO_URL"
 local_path="$LOCAL_PATH"
+# 分支、标签或者commit
+git_ref="$GIT_REF"
+# 浅克隆的深度,0表示完整克隆
+git_depth="${GIT_DEPTH:-0}"
+# git凭证的挂载路径
+credential_path="${CREDENTIAL_PATH:-/etc/git-credential}"
 
 echo "$repo_url"
 echo "$local_path"
 
+# 将devcontainer.json复制到存储卷中, 由控制器读取并应用到工作空间
+# 仓库中没有devcontainer.json时写入空文件, 避免读取到之前的配置
+write_devcontainer() {
+	if [ -z "$DEVCONTAINER_PATH" ]; then
+		return
+	fi
+	mkdir -p "$(dirname "$DEVCONTAINER_PATH")"
+	: > "$DEVCONTAINER_PATH"
+	for f in "$local_path/.devcontainer/devcontainer.json" "$local_path/.devcontainer.json"; do
+		if [ -f "$f" ]; then
+			cat "$f" > "$DEVCONTAINER_PATH"
+			return
+		fi
+	done
+}
+
+# 配置私有仓库的凭证, 凭证只在克隆时使用, 不会写入到仓库的配置中
+git_opts=()
+if [ -f "$credential_path/.git-credentials" ]; then
+	git_opts+=(-c "credential.helper=store --file=$credential_path/.git-credentials")
+fi
+# ssh私钥和主机公钥按照主机名保存, 文件名为ssh-privatekey-<host>和known_hosts-<host>
+# 严格校验主机的身份, 主机公钥不在凭证中也不在镜像的/etc/ssh/ssh_known_hosts中时拒绝连接
+ssh_config=/tmp/ssh_config
+: > "$ssh_config"
+for key in "$credential_path"/ssh-privatekey-*; do
+	[ -f "$key" ] || continue
+	host="${key##*/ssh-privatekey-}"
+	cat >> "$ssh_config" <<CONFIG
+Host $host
+  IdentityFile $key
+  IdentitiesOnly yes
+CONFIG
+	if [ -f "$credential_path/known_hosts-$host" ]; then
+		echo "  UserKnownHostsFile $credential_path/known_hosts-$host" >> "$ssh_config"
+	fi
+done
+cat >> "$ssh_config" <<CONFIG
+Host *
+  StrictHostKeyChecking yes
+  UserKnownHostsFile /dev/null
+CONFIG
+export GIT_SSH_COMMAND="ssh -F $ssh_config"
+
+# dotfiles安装失败不影响工作空间的启动, 将错误信息写入终止消息中由控制器上报
+dotfiles_fail() {
+	echo "$1"
+	echo "failed: $1" > /dev/termination-log
+	exit 0
+}
+
+# 安装dotfiles, 优先执行仓库中的安装脚本, 没有安装脚本时将dotfiles链接到用户目录
+install_dotfiles() {
+	local home_dir="${HOME_DIR:-/root}"
+	for script in install.sh install bootstrap.sh bootstrap script/bootstrap setup.sh setup; do
+		if [ -f "$local_path/$script" ]; then
+			(cd "$local_path" && HOME="$home_dir" bash "./$script") || dotfiles_fail "Failed to run $script."
+			return
+		fi
+	done
+
+	for f in "$local_path"/.[!.]*; do
+		[ -e "$f" ] || continue
+		name="${f##*/}"
+		if [ "$name" = ".git" ] || [ "$name" = ".github" ]; then
+			continue
+		fi
+		ln -sfn "$f" "$home_dir/$name"
+	done
+}
+
+# dotfiles在每次启动时更新
+if [ "$DOTFILES" = "true" ]; then
+	if [ -d "$local_path/.git" ]; then
+		git -C "$local_path" "${git_opts[@]}" pull --ff-only || dotfiles_fail "Failed to update dotfiles."
+	else
+		rm -rf "$local_path"
+		clone_opts=()
+		if [ -n "$git_ref" ]; then
+			clone_opts+=(--branch "$git_ref")
+		fi
+		git "${git_opts[@]}" clone --depth 1 "${clone_opts[@]}" -- "$repo_url" "$local_path" || dotfiles_fail "Failed to clone dotfiles."
+	fi
+	install_dotfiles
+	echo "Dotfiles installed successfully."
+	exit 0
+fi
+
 # 检查本地仓库是否存在
 if [ -d "$local_path" ]; then
     # 本地仓库不存在，执行克隆操作
     echo "Local repository already exists."
+	write_devcontainer
 	exit 0
 fi
 
+clone_opts=()
+if [ "$git_depth" -gt 0 ]; then
+	clone_opts+=(--depth "$git_depth")
+fi
+
+# 分支和标签可以直接克隆, commit需要先克隆再检出
+# 先在远程仓库中查找同名的分支或标签, 找不到时才将其作为commit, 避免十六进制命名的分支或标签被当作commit
+is_commit=0
+if [ -n "$git_ref" ]; then
+	refs=$(git "${git_opts[@]}" ls-remote --heads --tags -- "$repo_url" "refs/heads/$git_ref" "refs/tags/$git_ref")
+	if [ $? -ne 0 ]; then
+		echo "Failed to list remote refs."
+		exit 1
+	fi
+
+	if [ -n "$refs" ]; then
+		clone_opts+=(--branch "$git_ref")
+	elif [[ "$git_ref" =~ ^[0-9a-fA-F]{7,40}$ ]]; then
+		is_commit=1
+	else
+		echo "Ref $git_ref not found."
+		exit 1
+	fi
+fi
+
 # 尝试克隆仓库
-git clone "$repo_url" "$local_path"
+git "${git_opts[@]}" clone "${clone_opts[@]}" -- "$repo_url" "$local_path"
 
 if [ $? -ne 0 ]; then
 	echo "Failed to clone repository."
 	exit 1
 fi
 
+if [ $is_commit -eq 1 ]; then
+	fetch_opts=()
+	if [ "$git_depth" -gt 0 ]; then
+		fetch_opts+=(--depth "$git_depth")
+	fi
+	git -C "$local_path" "${git_opts[@]}" fetch "${fetch_opts[@]}" origin "$git_ref" && \
+		git -C "$local_path" checkout --detach "$git_ref"
+
+	if [ $? -ne 0 ]; then
+		echo "Failed to checkout $git_ref."
+		rm -rf "$local_path"
+		exit 1
+	fi
+fi
+
 echo "Repository cloned successfully."
+write_devcontainer
 exit 0
@@ -0,0 +1,90 @@
+// 从网关获取工作空间的通知, 工作空间即将停止时保存所有文件并提示用户
+// 通知的地址和token由control-plane通过环境变量CLOUD_IDE_NOTICE_URL和CLOUD_IDE_NOTICE_TOKEN传递
+const vscode = require('vscode')
+const https = require('https')
+
+// 长轮询的等待时间, 网关最多等待30秒
+const waitSeconds = 30
+// 请求失败后重试的间隔
+const retryInterval = 10 * 1000
+// 已经获取到通知后再次获取的间隔, 网关在有通知时会立即返回
+const noticeInterval = 5 * 1000
+
+function activate(context) {
+    const url = process.env.CLOUD_IDE_NOTICE_URL
+    const token = process.env.CLOUD_IDE_NOTICE_TOKEN
+    if (!url || !token) {
+        return
+    }
+
+    let disposed = false
+    let timer = null
+    let last = ''
+    context.subscriptions.push({
+        dispose() {
+            disposed = true
+            clearTimeout(timer)
+        }
+    })
+
+    const schedule = (delay) => {
+        if (!disposed) {
+            timer = setTimeout(poll, delay)
+        }
+    }
+
+    const poll = () => {
+        // 网关使用自签名证书
+        const req = https.get(`${url}?wait=${waitSeconds}`, {
+            headers: { token },
+            rejectUnauthorized: false,
+            timeout: (waitSeconds + 10) * 1000,
+        }, (res) => {
+            let body = ''
+            res.setEncoding('utf8')
+            res.on('data', (chunk) => body += chunk)
+            res.on('end', () => {
+                if (res.statusCode === 200) {
+                    if (body !== last) {
+                        last = body
+                        handle(body)
+                    }
+                    schedule(noticeInterval)
+                } else if (res.statusCode === 204) {
+                    // 通知已经被撤销, 例如取消了计划的停止
+                    last = ''
+                    schedule(0)
+                } else {
+                    schedule(retryInterval)
+                }
+            })
+        })
+        req.on('timeout', () => req.destroy())
+        req.on('error', () => schedule(retryInterval))
+    }
+
+    poll()
+}
+
+function handle(body) {
+    let notice
+    try {
+        notice = JSON.parse(body)
+    } catch (e) {
+        return
+    }
+
+    let message = notice.message || 'workspace notice'
+    if (notice.deadline) {
+        message += ` (${new Date(notice.deadline * 1000).toLocaleString()})`
+    }
+    if (notice.type === 'stop') {
+        // 工作空间即将停止, 先保存所有文件
+        vscode.workspace.saveAll(false).then(undefined, () => undefined)
+    }
+    vscode.window.showWarningMessage(message)
+}
+
+function deactivate() {}
+
+module.exports = { activate, deactivate }
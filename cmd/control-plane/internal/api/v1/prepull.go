@@ -0,0 +1,17 @@
+package v1
+
+const (
+	// PrePullName 保存需要预拉取的镜像的ConfigMap, 以及预拉取镜像的DaemonSet的名称
+	PrePullName = "cloud-ide-prepull"
+	// PrePullImagesKey 镜像列表在ConfigMap中的key, 值为PrePullImage列表的json
+	PrePullImagesKey = "images.json"
+)
+
+// PrePullImage is an image pulled to all nodes in advance
+type PrePullImage struct {
+	Image string `json:"image"`
+
+	// The name of the secret used to pull a private image
+	// +optional
+	PullSecret string `json:"pullSecret,omitempty"`
+}
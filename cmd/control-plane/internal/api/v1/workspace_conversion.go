@@ -0,0 +1,4 @@
+package v1
+
+// Hub 标记v1为转换的中心版本, 其它版本都与v1互相转换, v1同时也是存储版本
+func (*WorkSpace) Hub() {}
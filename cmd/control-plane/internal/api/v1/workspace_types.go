@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,16 +28,31 @@ type WorkspaceCommand string
 
 const (
 	WorkSpaceStart WorkspaceCommand = "Start"
-	WorkSpaceStop                   = "Stop"
+	WorkSpaceStop  WorkspaceCommand = "Stop"
 )
 
+// WorkSpacePhase is the lifecycle phase of the workspace, the transitions are validated by the lifecycle package
 type WorkSpacePhase string
 
 const (
+	// WorkspacePhasePending the workspace is created but its pod is not created yet
+	WorkspacePhasePending  WorkSpacePhase = "Pending"
+	WorkspacePhaseStarting WorkSpacePhase = "Starting"
 	WorkspacePhaseRunning  WorkSpacePhase = "Running"
-	WorkspacePhaseStaring                 = "Starting"
-	WorkspacePhaseStopping                = "Stopping"
-	WorkspacePhaseStopped                 = "Stopped"
+	WorkspacePhaseStopping WorkSpacePhase = "Stopping"
+	WorkspacePhaseStopped  WorkSpacePhase = "Stopped"
+	// WorkspacePhaseFailed the pod of the workspace failed, it's recreated by the controller
+	WorkspacePhaseFailed WorkSpacePhase = "Failed"
+	// WorkspacePhaseDeleting the workspace is being deleted, it can't be started or stopped any more
+	WorkspacePhaseDeleting WorkSpacePhase = "Deleting"
+)
+
+type RetentionPolicy string
+
+const (
+	RetentionDelete   RetentionPolicy = "Delete"
+	RetentionRetain   RetentionPolicy = "Retain"
+	RetentionSnapshot RetentionPolicy = "Snapshot"
 )
 
 // WorkSpaceSpec defines the desired state of WorkSpace
@@ -47,11 +63,13 @@ type WorkSpaceSpec struct {
 	// user id
 	// +kubebuilder:validation:MinLength=6
 	// +kubebuilder:validation:MaxLength=24
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+$`
 	UID string `json:"uid,omitempty"`
 
 	// space id
 	// +kubebuilder:validation:MinLength=6
 	// +kubebuilder:validation:MaxLength=24
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+$`
 	SID string `json:"sid,omitempty"`
 
 	// resource limit cpu
@@ -75,26 +93,358 @@ type WorkSpaceSpec struct {
 	Port int32 `json:"port,omitempty"`
 
 	// Volume mount path
-	// +kubebuilder:validation:Pattern=""
+	// +kubebuilder:validation:Pattern=`^\/(?:[\w-]+\/)*(?:[\w-]+\.[\w-]+|[\w-]+\/?)$`
 	MountPath string `json:"mountPath"`
 
-	// git repository to clone
+	// git repository to clone, https and ssh urls are supported
 	GitRepository string `json:"gitRepository,omitempty"`
 
+	// The branch, tag or commit to checkout
+	// +optional
+	GitRef string `json:"gitRef,omitempty"`
+
+	// Create a shallow clone with the specified depth, 0 means a full clone
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GitDepth int32 `json:"gitDepth,omitempty"`
+
+	// Additional git repositories cloned into the workspace, each one is cloned by its own init container
+	// +optional
+	Repositories []GitRepository `json:"repositories,omitempty"`
+
+	// The dotfiles repository of the user, it's cloned or updated and installed at every start
+	// +optional
+	Dotfiles *GitRepository `json:"dotfiles,omitempty"`
+
+	// The name of the secret used to pull a private image
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+
+	// The commands executed at the lifecycle of the workspace, the template hooks are overridden by the workspace hooks
+	// +optional
+	Hooks *LifecycleHooks `json:"hooks,omitempty"`
+
+	// The time when the running workspace is stopped, the stop can be canceled before this time
+	// +optional
+	ScheduledStop *metav1.Time `json:"scheduledStop,omitempty"`
+
+	// The schedule to start and stop the workspace automatically
+	// +optional
+	Schedule *WorkspaceSchedule `json:"schedule,omitempty"`
+
+	// The scheduling constraints of the hardware spec, configured by admins
+	// +optional
+	Scheduling *WorkspaceScheduling `json:"scheduling,omitempty"`
+
+	// The egress allowlist of the template, the default egress policy of the control plane is used when it's nil
+	// +optional
+	Egress *WorkspaceEgress `json:"egress,omitempty"`
+
+	// What to do with the data of the workspace when it's deleted, the data is deleted immediately when it's nil
+	// +optional
+	Retention *WorkspaceRetention `json:"retention,omitempty"`
+
 	// The command can be "Start", "Stop" or ""
 	Command WorkspaceCommand `json:"operation,omitempty"`
 }
 
+// GitRepository describes a git repository cloned into the workspace
+type GitRepository struct {
+	// The url of the repository, https and ssh urls are supported
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// The path relative to the workspace directory, defaults to the name of the repository
+	// +kubebuilder:validation:Pattern=`^[\w.-]+(/[\w.-]+)*$`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// The branch, tag or commit to checkout
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// Create a shallow clone with the specified depth, 0 means a full clone
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Depth int32 `json:"depth,omitempty"`
+}
+
+// LifecycleHooks are shell commands executed in the workspace container, the output is saved in
+// .cloud-ide/hooks of the workspace volume and reported in the status
+type LifecycleHooks struct {
+	// Executed once in an init container after the repositories are cloned for the first time
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	PostCreate string `json:"postCreate,omitempty"`
+
+	// Executed every time the workspace container is started
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	PostStart string `json:"postStart,omitempty"`
+
+	// Executed before the workspace container is stopped
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	PreStop string `json:"preStop,omitempty"`
+}
+
+// WorkspaceSchedule starts and stops the workspace at the times described by the cron expressions
+type WorkspaceSchedule struct {
+	// The cron expression to start the workspace, e.g. "0 9 * * 1-5"
+	// +optional
+	Start string `json:"start,omitempty"`
+
+	// The cron expression to stop the workspace, e.g. "0 20 * * 1-5"
+	// +optional
+	Stop string `json:"stop,omitempty"`
+
+	// The IANA timezone of the cron expressions, defaults to UTC
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// WorkspaceScheduling constrains the nodes the workspace pod can be scheduled to
+type WorkspaceScheduling struct {
+	// The labels the node must have, e.g. the label of a node pool
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// The taints the workspace pod tolerates
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// The priority class of the workspace pod
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Spread the workspace pods across the topology domains
+	// +optional
+	TopologySpread []TopologySpread `json:"topologySpread,omitempty"`
+}
+
+// TopologySpread spreads the workspace pods evenly across the domains of the topology key
+type TopologySpread struct {
+	// The node label key of the topology domain, e.g. topology.kubernetes.io/zone
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey"`
+
+	// The maximum difference of the number of workspace pods between domains
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew"`
+
+	// DoNotSchedule or ScheduleAnyway, defaults to DoNotSchedule
+	// +kubebuilder:validation:Enum=DoNotSchedule;ScheduleAnyway
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// WorkspaceRetention keeps the volume or a snapshot of the volume of a deleted workspace until RetainUntil,
+// a workspace created with the same name before that time restores the data
+type WorkspaceRetention struct {
+	// Delete, Retain or Snapshot
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	Policy RetentionPolicy `json:"policy"`
+
+	// The time after which the retained data is purged
+	// +optional
+	RetainUntil *metav1.Time `json:"retainUntil,omitempty"`
+}
+
+// WorkspaceEgress only allows the workspace to access the addresses in the rules and DNS,
+// an empty rule list only allows DNS
+type WorkspaceEgress struct {
+	// +optional
+	Rules []EgressRule `json:"rules,omitempty"`
+}
+
+// EgressRule allows the workspace to access an address range, e.g. the address of a package registry
+type EgressRule struct {
+	// The address range in CIDR notation, e.g. 203.0.113.0/24
+	// +kubebuilder:validation:MinLength=1
+	CIDR string `json:"cidr"`
+
+	// The TCP ports allowed, all ports are allowed when it's empty
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+type HookName string
+
+const (
+	HookPostCreate HookName = "postCreate"
+	HookPostStart  HookName = "postStart"
+	HookPreStop    HookName = "preStop"
+)
+
+type HookState string
+
+const (
+	HookSucceeded HookState = "Succeeded"
+	HookFailed    HookState = "Failed"
+)
+
+// HookStatus is the result of the last execution of a lifecycle hook
+type HookStatus struct {
+	Name HookName `json:"name"`
+
+	State HookState `json:"state"`
+
+	// The exit code of the command
+	ExitCode int32 `json:"exitCode"`
+
+	// The tail of the command output
+	// +optional
+	Output string `json:"output,omitempty"`
+
+	// The time when the container executing the hook terminated
+	// +optional
+	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
+}
+
+type RepositoryState string
+
+const (
+	RepositoryPending RepositoryState = "Pending"
+	RepositoryCloning RepositoryState = "Cloning"
+	RepositoryCloned  RepositoryState = "Cloned"
+	RepositoryFailed  RepositoryState = "Failed"
+)
+
+// RepositoryStatus is the clone status of a repository, it's reported by the init container
+type RepositoryStatus struct {
+	// The name of the init container
+	Name string `json:"name"`
+
+	// The url of the repository
+	URL string `json:"url"`
+
+	// The local path of the repository
+	Path string `json:"path,omitempty"`
+
+	State RepositoryState `json:"state"`
+
+	// The reason of the failure
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DevContainerConfig is the subset of .devcontainer/devcontainer.json applied to the workspace pod
+type DevContainerConfig struct {
+	// The hash of the raw devcontainer.json, it's also recorded in the pod annotations
+	Hash string `json:"hash"`
+
+	// The image overrides the template image, only images allowed by the administrator are accepted
+	Image string `json:"image,omitempty"`
+
+	// Environment variables set in the workspace container
+	ContainerEnv map[string]string `json:"containerEnv,omitempty"`
+
+	// Environment variables for the processes started by code-server
+	RemoteEnv map[string]string `json:"remoteEnv,omitempty"`
+
+	// Extra ports exposed by the workspace container
+	ForwardPorts []int32 `json:"forwardPorts,omitempty"`
+
+	// The command executed once after the workspace is created
+	PostCreateCommand []string `json:"postCreateCommand,omitempty"`
+
+	// The features requested by devcontainer.json, only the allowed features are accepted
+	Features []string `json:"features,omitempty"`
+}
+
+// DevContainerSource identifies the repository commit the devcontainer.json was read from
+type DevContainerSource struct {
+	// The url of the repository
+	URL string `json:"url"`
+
+	// The ref of the repository
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// The commit checked out in the workspace volume
+	Commit string `json:"commit"`
+}
+
+// StartupStatus records how long it took to start the workspace pod
+type StartupStatus struct {
+	// The uid of the pod
+	PodUID string `json:"podUID"`
+
+	// The duration from the creation of the pod to the start of the workspace container
+	Duration metav1.Duration `json:"duration"`
+
+	// Whether the pod was scheduled to the node of a claimed warm pod, where the image is already cached
+	// +optional
+	Warm bool `json:"warm,omitempty"`
+}
+
 // WorkSpaceStatus defines the observed state of WorkSpace
 type WorkSpaceStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	// +kubebuilder:default="Created"
+
+	// The lifecycle phase, defaults to Pending. Older versions defaulted it to Created,
+	// which is converted to Pending by the controller
+	// +kubebuilder:default="Pending"
 	Phase WorkSpacePhase `json:"phase,omitempty"`
+
+	// The clone status of the repositories and the dotfiles
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Repositories []RepositoryStatus `json:"repositories,omitempty"`
+
+	// The results of the lifecycle hooks
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Hooks []HookStatus `json:"hooks,omitempty"`
+
+	// The devcontainer configuration read from the git repository
+	// +optional
+	DevContainer *DevContainerConfig `json:"devContainer,omitempty"`
+
+	// The source of the devcontainer configuration, the configuration is read again only when it changes
+	// +optional
+	DevContainerSource *DevContainerSource `json:"devContainerSource,omitempty"`
+
+	// The startup latency of the last started pod
+	// +optional
+	Startup *StartupStatus `json:"startup,omitempty"`
+
+	// Conditions of the workspace
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// EnvSecretName 保存工作空间环境变量的Secret名称
+func EnvSecretName(workspace string) string {
+	return workspace + "-env"
+}
+
+// GitSecretName 保存克隆git仓库使用的凭证的Secret名称
+func GitSecretName(workspace string) string {
+	return workspace + "-git"
 }
 
+const (
+	// GitCredentialsKey https凭证在Secret中的key, 格式与git-credential-store相同
+	GitCredentialsKey = ".git-credentials"
+	// GitSshKeyPrefix ssh私钥在Secret中的key的前缀, 后缀为git主机名
+	GitSshKeyPrefix = "ssh-privatekey-"
+	// GitKnownHostsPrefix ssh主机公钥在Secret中的key的前缀, 后缀为git主机名
+	GitKnownHostsPrefix = "known_hosts-"
+)
+
+const (
+	// ConditionDevContainer 表示devcontainer.json是否被成功应用
+	ConditionDevContainer = "DevContainerApplied"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
 // +kubebuilder:printcolumn:name="Hardware",type=string,JSONPath=`.spec.hardware`
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var workspacelog = logf.Log.WithName("workspace-resource")
+
+// MaxEgressRules 出站白名单中规则的最大数量, 由webhook负责最终的校验
+const MaxEgressRules = 32
+
+var (
+	// uid和sid为bson的ObjectId, 同时作为标签的值和资源名称的一部分
+	idRegexp        = regexp.MustCompile(`^[a-z0-9]{6,24}$`)
+	mountPathRegexp = regexp.MustCompile(`^\/(?:[\w-]+\/)*(?:[\w-]+\.[\w-]+|[\w-]+\/?)$`)
+)
+
+// HardwareDescription 生成硬件资源描述, 例如 2C4G10G
+func HardwareDescription(cpu, memory, storage string) string {
+	return fmt.Sprintf("%sC%s%s", cpu, strings.Split(memory, "i")[0], strings.Split(storage, "i")[0])
+}
+
+func (r *WorkSpace) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-cloud-ide-mangohow-com-v1-workspace,mutating=true,failurePolicy=fail,sideEffects=None,groups=cloud-ide.mangohow.com,resources=workspaces,verbs=create;update,versions=v1,name=mworkspace.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &WorkSpace{}
+
+// Default 补全硬件资源描述, 通过kubectl创建的工作空间可以不指定
+func (r *WorkSpace) Default() {
+	workspacelog.V(1).Info("default", "name", r.Name)
+
+	if r.Spec.Hardware == "" && r.Spec.Cpu != "" && r.Spec.Memory != "" && r.Spec.Storage != "" {
+		r.Spec.Hardware = HardwareDescription(r.Spec.Cpu, r.Spec.Memory, r.Spec.Storage)
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-cloud-ide-mangohow-com-v1-workspace,mutating=false,failurePolicy=fail,sideEffects=None,groups=cloud-ide.mangohow.com,resources=workspaces,verbs=create;update,versions=v1,name=vworkspace.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &WorkSpace{}
+
+// ValidateCreate 校验通过任意方式创建的工作空间, 与grpc接口中的校验保持一致
+func (r *WorkSpace) ValidateCreate() error {
+	workspacelog.V(1).Info("validate create", "name", r.Name)
+
+	return r.toError(r.validateSpec())
+}
+
+// ValidateUpdate 除了校验spec之外, 还不允许修改工作空间的所属以及存储卷相关的字段
+func (r *WorkSpace) ValidateUpdate(old runtime.Object) error {
+	workspacelog.V(1).Info("validate update", "name", r.Name)
+
+	// 正在删除的工作空间只需要移除finalizer, 不能阻止删除
+	if r.DeletionTimestamp != nil {
+		return nil
+	}
+
+	oldSpace, ok := old.(*WorkSpace)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a WorkSpace but got a %T", old))
+	}
+
+	errs := r.validateSpec()
+	errs = append(errs, r.validateImmutable(oldSpace)...)
+	return r.toError(errs)
+}
+
+// ValidateDelete 删除不需要校验
+func (r *WorkSpace) ValidateDelete() error {
+	return nil
+}
+
+func (r *WorkSpace) toError(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("WorkSpace").GroupKind(), r.Name, errs)
+}
+
+func (r *WorkSpace) validateSpec() field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	if !idRegexp.MatchString(r.Spec.UID) {
+		errs = append(errs, field.Invalid(spec.Child("uid"), r.Spec.UID, "must be 6-24 lowercase letters or digits"))
+	}
+	if !idRegexp.MatchString(r.Spec.SID) {
+		errs = append(errs, field.Invalid(spec.Child("sid"), r.Spec.SID, "must be 6-24 lowercase letters or digits"))
+	}
+	if r.Spec.Port < 1024 || r.Spec.Port > 65535 {
+		errs = append(errs, field.Invalid(spec.Child("port"), r.Spec.Port, "must be in [1024,65535]"))
+	}
+	if !mountPathRegexp.MatchString(r.Spec.MountPath) {
+		errs = append(errs, field.Invalid(spec.Child("mountPath"), r.Spec.MountPath, "must be an absolute path"))
+	}
+	if r.Spec.Image != "" && !utils.VerifyImageReference(r.Spec.Image) {
+		errs = append(errs, field.Invalid(spec.Child("image"), r.Spec.Image, "invalid image reference"))
+	}
+
+	errs = append(errs, validateQuantity(spec.Child("cpu"), r.Spec.Cpu)...)
+	errs = append(errs, validateQuantity(spec.Child("memory"), r.Spec.Memory)...)
+	errs = append(errs, validateQuantity(spec.Child("storage"), r.Spec.Storage)...)
+
+	if r.Spec.GitRepository != "" {
+		errs = append(errs, validateGitRepository(spec, GitRepository{
+			URL: r.Spec.GitRepository,
+			Ref: r.Spec.GitRef,
+		}, "gitRepository", "gitRef")...)
+	}
+	for i, repo := range r.Spec.Repositories {
+		errs = append(errs, validateGitRepository(spec.Child("repositories").Index(i), repo, "url", "ref")...)
+		if repo.Path != "" && !utils.VerifyRepositoryPath(repo.Path) {
+			errs = append(errs, field.Invalid(spec.Child("repositories").Index(i).Child("path"), repo.Path, "must be a relative path without . or .. segments"))
+		}
+	}
+	if r.Spec.Dotfiles != nil {
+		errs = append(errs, validateGitRepository(spec.Child("dotfiles"), *r.Spec.Dotfiles, "url", "ref")...)
+	}
+	if r.Spec.Egress != nil {
+		errs = append(errs, validateEgress(spec.Child("egress"), r.Spec.Egress)...)
+	}
+
+	return errs
+}
+
+// uid、sid决定了工作空间的所属, 存储卷创建之后挂载路径和容量也无法修改
+func (r *WorkSpace) validateImmutable(old *WorkSpace) field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+
+	immutable := []struct {
+		name     string
+		new, old string
+	}{
+		{"uid", r.Spec.UID, old.Spec.UID},
+		{"sid", r.Spec.SID, old.Spec.SID},
+		{"mountPath", r.Spec.MountPath, old.Spec.MountPath},
+		{"storage", r.Spec.Storage, old.Spec.Storage},
+	}
+	for _, f := range immutable {
+		if f.new != f.old {
+			errs = append(errs, field.Forbidden(spec.Child(f.name), "field is immutable"))
+		}
+	}
+
+	return errs
+}
+
+// 资源必须能够被解析并且大于0
+func validateQuantity(path *field.Path, value string) field.ErrorList {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, value, err.Error())}
+	}
+	if q.Sign() <= 0 {
+		return field.ErrorList{field.Invalid(path, value, "must be greater than 0")}
+	}
+
+	return nil
+}
+
+func validateEgress(path *field.Path, egress *WorkspaceEgress) field.ErrorList {
+	var errs field.ErrorList
+	if len(egress.Rules) > MaxEgressRules {
+		errs = append(errs, field.TooMany(path.Child("rules"), len(egress.Rules), MaxEgressRules))
+	}
+	for i, rule := range egress.Rules {
+		if _, _, err := net.ParseCIDR(rule.CIDR); err != nil {
+			errs = append(errs, field.Invalid(path.Child("rules").Index(i).Child("cidr"), rule.CIDR, "invalid cidr"))
+		}
+		for j, port := range rule.Ports {
+			if port < 1 || port > 65535 {
+				errs = append(errs, field.Invalid(path.Child("rules").Index(i).Child("ports").Index(j), port, "must be in [1,65535]"))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateGitRepository(path *field.Path, repo GitRepository, urlField, refField string) field.ErrorList {
+	var errs field.ErrorList
+	if !utils.VerifyGitRepository(repo.URL) {
+		errs = append(errs, field.Invalid(path.Child(urlField), repo.URL, "invalid git repository, https and ssh urls are supported"))
+	}
+	if repo.Ref != "" && !utils.VerifyGitRef(repo.Ref) {
+		errs = append(errs, field.Invalid(path.Child(refField), repo.Ref, "invalid branch, tag or commit"))
+	}
+
+	return errs
+}
@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func validWorkspace() *WorkSpace {
+	return &WorkSpace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-user01-space01", Namespace: "cloud-ide-ws"},
+		Spec: WorkSpaceSpec{
+			UID:       "user01",
+			SID:       "space01",
+			Cpu:       "2",
+			Memory:    "4Gi",
+			Storage:   "8Gi",
+			Image:     "code-server:go",
+			Port:      9999,
+			MountPath: "/root/",
+			Command:   WorkSpaceStart,
+		},
+	}
+}
+
+func TestWorkspaceDefault(t *testing.T) {
+	space := validWorkspace()
+	space.Default()
+	if space.Spec.Hardware != "2C4G8G" {
+		t.Errorf("hardware = %q, want 2C4G8G", space.Spec.Hardware)
+	}
+
+	space.Spec.Hardware = "custom"
+	space.Default()
+	if space.Spec.Hardware != "custom" {
+		t.Errorf("hardware overridden: %q", space.Spec.Hardware)
+	}
+}
+
+func TestWorkspaceValidateCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(space *WorkSpace)
+		valid  bool
+	}{
+		{name: "valid", modify: func(space *WorkSpace) {}, valid: true},
+		{name: "uppercase uid", modify: func(space *WorkSpace) { space.Spec.UID = "User01" }},
+		{name: "short sid", modify: func(space *WorkSpace) { space.Spec.SID = "s1" }},
+		{name: "port too small", modify: func(space *WorkSpace) { space.Spec.Port = 80 }},
+		{name: "relative mount path", modify: func(space *WorkSpace) { space.Spec.MountPath = "root" }},
+		{name: "mount path traversal", modify: func(space *WorkSpace) { space.Spec.MountPath = "/root/../etc" }},
+		{name: "invalid cpu", modify: func(space *WorkSpace) { space.Spec.Cpu = "two" }},
+		{name: "zero memory", modify: func(space *WorkSpace) { space.Spec.Memory = "0" }},
+		{name: "missing storage", modify: func(space *WorkSpace) { space.Spec.Storage = "" }},
+		{name: "invalid image", modify: func(space *WorkSpace) { space.Spec.Image = "Code Server" }},
+		{name: "git repository", modify: func(space *WorkSpace) {
+			space.Spec.GitRepository = "https://github.com/mangohow/cloud-ide.git"
+			space.Spec.GitRef = "main"
+		}, valid: true},
+		{name: "invalid git repository", modify: func(space *WorkSpace) { space.Spec.GitRepository = "file:///etc" }},
+		{name: "invalid git ref", modify: func(space *WorkSpace) {
+			space.Spec.GitRepository = "git@github.com:mangohow/cloud-ide.git"
+			space.Spec.GitRef = "../main"
+		}},
+		{name: "invalid repository", modify: func(space *WorkSpace) {
+			space.Spec.Repositories = []GitRepository{{URL: "https://github.com/mangohow/cloud-ide"}, {URL: "ftp://host/repo"}}
+		}},
+		{name: "repository path traversal", modify: func(space *WorkSpace) {
+			space.Spec.Repositories = []GitRepository{{URL: "https://github.com/mangohow/cloud-ide", Path: "../x"}}
+		}},
+		{name: "absolute repository path", modify: func(space *WorkSpace) {
+			space.Spec.Repositories = []GitRepository{{URL: "https://github.com/mangohow/cloud-ide", Path: "/etc"}}
+		}},
+		{name: "invalid dotfiles", modify: func(space *WorkSpace) { space.Spec.Dotfiles = &GitRepository{URL: "dotfiles"} }},
+		{name: "egress", modify: func(space *WorkSpace) {
+			space.Spec.Egress = &WorkspaceEgress{Rules: []EgressRule{{CIDR: "203.0.113.0/24", Ports: []int32{443}}}}
+		}, valid: true},
+		{name: "invalid egress cidr", modify: func(space *WorkSpace) {
+			space.Spec.Egress = &WorkspaceEgress{Rules: []EgressRule{{CIDR: "203.0.113.0"}}}
+		}},
+		{name: "too many egress rules", modify: func(space *WorkSpace) {
+			space.Spec.Egress = &WorkspaceEgress{Rules: make([]EgressRule, MaxEgressRules+1)}
+			for i := range space.Spec.Egress.Rules {
+				space.Spec.Egress.Rules[i].CIDR = "203.0.113.0/24"
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			space := validWorkspace()
+			tt.modify(space)
+			err := space.ValidateCreate()
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateUpdate(t *testing.T) {
+	old := validWorkspace()
+
+	space := validWorkspace()
+	space.Spec.Command = WorkSpaceStop
+	space.Spec.Image = "code-server:go-v2"
+	if err := space.ValidateUpdate(old); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, modify := range []func(space *WorkSpace){
+		func(space *WorkSpace) { space.Spec.UID = "user02" },
+		func(space *WorkSpace) { space.Spec.SID = "space02" },
+		func(space *WorkSpace) { space.Spec.MountPath = "/home/" },
+		func(space *WorkSpace) { space.Spec.Storage = "16Gi" },
+	} {
+		space := validWorkspace()
+		modify(space)
+		if err := space.ValidateUpdate(old); err == nil {
+			t.Errorf("expected an error for immutable field, spec: %+v", space.Spec)
+		}
+	}
+
+	// 正在删除的工作空间可以移除finalizer
+	space = validWorkspace()
+	space.Spec.Storage = "16Gi"
+	now := metav1.Now()
+	space.DeletionTimestamp = &now
+	if err := space.ValidateUpdate(old); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
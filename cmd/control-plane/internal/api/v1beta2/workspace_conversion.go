@@ -0,0 +1,285 @@
+package v1beta2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// 两个版本无法互相表示的字段保存在注解中, 保证转换之后再转换回来不会丢失
+const (
+	// AnnotationHardware v1中自定义的硬件资源描述, 与根据资源生成的描述相同时不保存
+	AnnotationHardware = "cloud-ide.mangohow.com/v1-hardware"
+	// AnnotationSpec v1beta2中v1无法表示的字段, 值为convertedFields的json
+	AnnotationSpec = "cloud-ide.mangohow.com/v1beta2-spec"
+	// AnnotationQuantities v1中不是规范格式的资源, 值为v1Quantities的json
+	// resource.Quantity会将资源转换为规范的格式, 例如0.5转换为500m, 保存原始的字符串避免转换回v1之后规格的hash改变
+	AnnotationQuantities = "cloud-ide.mangohow.com/v1-quantities"
+)
+
+type v1Quantities struct {
+	Cpu     string `json:"cpu,omitempty"`
+	Memory  string `json:"memory,omitempty"`
+	Storage string `json:"storage,omitempty"`
+}
+
+// v1只有一个端口和一个主仓库, 无法表示端口列表、主仓库的路径以及主仓库在列表中的位置
+type convertedFields struct {
+	Ports        []WorkspacePort `json:"ports,omitempty"`
+	PrimaryPath  string          `json:"primaryPath,omitempty"`
+	PrimaryIndex int             `json:"primaryIndex,omitempty"`
+}
+
+var _ conversion.Convertible = &WorkSpace{}
+
+// ConvertTo 转换为v1
+func (r *WorkSpace) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1.WorkSpace)
+	if !ok {
+		return fmt.Errorf("expected a v1 WorkSpace but got a %T", dstRaw)
+	}
+
+	src := r.DeepCopy()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+	dst.Spec = v1.WorkSpaceSpec{
+		UID:             src.Spec.UID,
+		SID:             src.Spec.SID,
+		Cpu:             quantityString(src.Spec.Resources.Cpu),
+		Memory:          quantityString(src.Spec.Resources.Memory),
+		Storage:         quantityString(src.Spec.Resources.Storage),
+		Image:           src.Spec.Image,
+		MountPath:       src.Spec.MountPath,
+		Dotfiles:        src.Spec.Dotfiles,
+		ImagePullSecret: src.Spec.ImagePullSecret,
+		Hooks:           src.Spec.Lifecycle.Hooks,
+		ScheduledStop:   src.Spec.Lifecycle.ScheduledStop,
+		Schedule:        src.Spec.Lifecycle.Schedule,
+		Scheduling:      src.Spec.Scheduling,
+		Egress:          src.Spec.Egress,
+		Retention:       src.Spec.Lifecycle.Retention,
+		Command:         src.Spec.Lifecycle.Command,
+	}
+
+	delete(dst.Annotations, AnnotationSpec)
+	var fields convertedFields
+	if ide := idePortIndex(src.Spec.Ports); ide >= 0 {
+		dst.Spec.Port = src.Spec.Ports[ide].Port
+		if len(src.Spec.Ports) > 1 || src.Spec.Ports[ide].Name != PortNameIDE {
+			fields.Ports = src.Spec.Ports
+		}
+	}
+
+	for i, repo := range src.Spec.Repositories {
+		if !repo.Primary || dst.Spec.GitRepository != "" {
+			dst.Spec.Repositories = append(dst.Spec.Repositories, repo.GitRepository)
+			continue
+		}
+		dst.Spec.GitRepository = repo.URL
+		dst.Spec.GitRef = repo.Ref
+		dst.Spec.GitDepth = repo.Depth
+		fields.PrimaryPath = repo.Path
+		fields.PrimaryIndex = i
+	}
+
+	if data, ok := dst.Annotations[AnnotationQuantities]; ok {
+		var quantities v1Quantities
+		_ = json.Unmarshal([]byte(data), &quantities)
+		dst.Spec.Cpu = originalQuantity(quantities.Cpu, src.Spec.Resources.Cpu)
+		dst.Spec.Memory = originalQuantity(quantities.Memory, src.Spec.Resources.Memory)
+		dst.Spec.Storage = originalQuantity(quantities.Storage, src.Spec.Resources.Storage)
+		delete(dst.Annotations, AnnotationQuantities)
+	}
+
+	if hardware, ok := dst.Annotations[AnnotationHardware]; ok {
+		dst.Spec.Hardware = hardware
+		delete(dst.Annotations, AnnotationHardware)
+	} else {
+		dst.Spec.Hardware = hardwareDescription(dst.Spec.Cpu, dst.Spec.Memory, dst.Spec.Storage)
+	}
+
+	if fields.Ports != nil || fields.PrimaryPath != "" || fields.PrimaryIndex != 0 {
+		data, err := json.Marshal(&fields)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = make(map[string]string)
+		}
+		dst.Annotations[AnnotationSpec] = string(data)
+	}
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	return nil
+}
+
+// ConvertFrom 从v1转换
+func (r *WorkSpace) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1.WorkSpace)
+	if !ok {
+		return fmt.Errorf("expected a v1 WorkSpace but got a %T", srcRaw)
+	}
+	src = src.DeepCopy()
+
+	cpu, err := parseQuantity(src.Spec.Cpu)
+	if err != nil {
+		return fmt.Errorf("invalid cpu %q: %v", src.Spec.Cpu, err)
+	}
+	memory, err := parseQuantity(src.Spec.Memory)
+	if err != nil {
+		return fmt.Errorf("invalid memory %q: %v", src.Spec.Memory, err)
+	}
+	storage, err := parseQuantity(src.Spec.Storage)
+	if err != nil {
+		return fmt.Errorf("invalid storage %q: %v", src.Spec.Storage, err)
+	}
+
+	// 注解不合法时忽略, 不影响读取工作空间
+	var fields convertedFields
+	if data, ok := src.Annotations[AnnotationSpec]; ok {
+		_ = json.Unmarshal([]byte(data), &fields)
+		delete(src.Annotations, AnnotationSpec)
+	}
+	delete(src.Annotations, AnnotationHardware)
+	delete(src.Annotations, AnnotationQuantities)
+
+	r.ObjectMeta = src.ObjectMeta
+	r.Status = src.Status
+	r.Spec = WorkSpaceSpec{
+		UID:             src.Spec.UID,
+		SID:             src.Spec.SID,
+		Image:           src.Spec.Image,
+		ImagePullSecret: src.Spec.ImagePullSecret,
+		Resources:       WorkspaceResources{Cpu: cpu, Memory: memory, Storage: storage},
+		MountPath:       src.Spec.MountPath,
+		Dotfiles:        src.Spec.Dotfiles,
+		Lifecycle: WorkspaceLifecycle{
+			Command:       src.Spec.Command,
+			Hooks:         src.Spec.Hooks,
+			ScheduledStop: src.Spec.ScheduledStop,
+			Schedule:      src.Spec.Schedule,
+			Retention:     src.Spec.Retention,
+		},
+		Scheduling: src.Spec.Scheduling,
+		Egress:     src.Spec.Egress,
+	}
+
+	// IDE的端口以v1中的端口为准
+	r.Spec.Ports = []WorkspacePort{{Name: PortNameIDE, Port: src.Spec.Port}}
+	if ide := idePortIndex(fields.Ports); ide >= 0 {
+		r.Spec.Ports = fields.Ports
+		r.Spec.Ports[ide].Port = src.Spec.Port
+	}
+
+	for _, repo := range src.Spec.Repositories {
+		r.Spec.Repositories = append(r.Spec.Repositories, GitRepository{GitRepository: repo})
+	}
+	if src.Spec.GitRepository != "" {
+		primary := GitRepository{
+			GitRepository: v1.GitRepository{
+				URL:   src.Spec.GitRepository,
+				Path:  fields.PrimaryPath,
+				Ref:   src.Spec.GitRef,
+				Depth: src.Spec.GitDepth,
+			},
+			Primary: true,
+		}
+		i := fields.PrimaryIndex
+		if i < 0 || i > len(r.Spec.Repositories) {
+			i = 0
+		}
+		r.Spec.Repositories = append(r.Spec.Repositories[:i], append([]GitRepository{primary}, r.Spec.Repositories[i:]...)...)
+	}
+
+	if src.Spec.Hardware != hardwareDescription(src.Spec.Cpu, src.Spec.Memory, src.Spec.Storage) {
+		if r.Annotations == nil {
+			r.Annotations = make(map[string]string)
+		}
+		r.Annotations[AnnotationHardware] = src.Spec.Hardware
+	}
+	quantities := v1Quantities{
+		Cpu:     nonCanonicalQuantity(src.Spec.Cpu, cpu),
+		Memory:  nonCanonicalQuantity(src.Spec.Memory, memory),
+		Storage: nonCanonicalQuantity(src.Spec.Storage, storage),
+	}
+	if quantities != (v1Quantities{}) {
+		data, err := json.Marshal(&quantities)
+		if err != nil {
+			return err
+		}
+		if r.Annotations == nil {
+			r.Annotations = make(map[string]string)
+		}
+		r.Annotations[AnnotationQuantities] = string(data)
+	}
+	if len(r.Annotations) == 0 {
+		r.Annotations = nil
+	}
+
+	return nil
+}
+
+// 名称为ide的端口, 没有时为第一个端口
+func idePortIndex(ports []WorkspacePort) int {
+	for i, port := range ports {
+		if port.Name == PortNameIDE {
+			return i
+		}
+	}
+	if len(ports) > 0 {
+		return 0
+	}
+
+	return -1
+}
+
+// 没有指定资源时硬件资源描述为空
+func hardwareDescription(cpu, memory, storage string) string {
+	if cpu == "" || memory == "" || storage == "" {
+		return ""
+	}
+
+	return v1.HardwareDescription(cpu, memory, storage)
+}
+
+// v1中的空字符串转换为0
+func parseQuantity(s string) (resource.Quantity, error) {
+	if s == "" {
+		return resource.Quantity{}, nil
+	}
+
+	return resource.ParseQuantity(s)
+}
+
+// v1中的资源与规范的格式不同时返回原始的字符串
+func nonCanonicalQuantity(s string, q resource.Quantity) string {
+	if s == quantityString(q) {
+		return ""
+	}
+
+	return s
+}
+
+// v1beta2中的资源没有被修改时使用v1中原始的字符串
+func originalQuantity(s string, q resource.Quantity) string {
+	if s != "" {
+		if orig, err := resource.ParseQuantity(s); err == nil && orig.Cmp(q) == 0 {
+			return s
+		}
+	}
+
+	return quantityString(q)
+}
+
+func quantityString(q resource.Quantity) string {
+	if q.IsZero() {
+		return ""
+	}
+
+	return q.String()
+}
@@ -0,0 +1,227 @@
+package v1beta2
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+func v1Workspace() *v1.WorkSpace {
+	stop := metav1.NewTime(time.Date(2023, 5, 1, 20, 0, 0, 0, time.UTC))
+	return &v1.WorkSpace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws-user01-space01",
+			Namespace:   "cloud-ide-ws",
+			Labels:      map[string]string{"uid": "user01", "sid": "space01"},
+			Annotations: map[string]string{"note": "test"},
+		},
+		Spec: v1.WorkSpaceSpec{
+			UID:           "user01",
+			SID:           "space01",
+			Cpu:           "2",
+			Memory:        "4Gi",
+			Storage:       "8Gi",
+			Hardware:      "2C4G8G",
+			Image:         "code-server:go",
+			Port:          9999,
+			MountPath:     "/root/",
+			GitRepository: "https://github.com/mangohow/cloud-ide.git",
+			GitRef:        "main",
+			GitDepth:      1,
+			Repositories: []v1.GitRepository{
+				{URL: "git@github.com:mangohow/docs.git", Path: "docs"},
+			},
+			Dotfiles:        &v1.GitRepository{URL: "https://github.com/user01/dotfiles"},
+			ImagePullSecret: "registry",
+			Hooks:           &v1.LifecycleHooks{PostStart: "make deps"},
+			ScheduledStop:   &stop,
+			Schedule:        &v1.WorkspaceSchedule{Start: "0 9 * * 1-5", Stop: "0 20 * * 1-5"},
+			Scheduling:      &v1.WorkspaceScheduling{NodeSelector: map[string]string{"pool": "large"}},
+			Egress:          &v1.WorkspaceEgress{Rules: []v1.EgressRule{{CIDR: "203.0.113.0/24", Ports: []int32{443}}}},
+			Retention:       &v1.WorkspaceRetention{Policy: v1.RetentionSnapshot},
+			Command:         v1.WorkSpaceStart,
+		},
+		Status: v1.WorkSpaceStatus{Phase: v1.WorkspacePhaseRunning},
+	}
+}
+
+func TestConvertFromV1(t *testing.T) {
+	src := v1Workspace()
+
+	var dst WorkSpace
+	if err := dst.ConvertFrom(src); err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Spec.Resources.Memory.Cmp(resource.MustParse("4Gi")) != 0 {
+		t.Errorf("memory = %s, want 4Gi", dst.Spec.Resources.Memory.String())
+	}
+	if len(dst.Spec.Ports) != 1 || dst.Spec.Ports[0] != (WorkspacePort{Name: PortNameIDE, Port: 9999}) {
+		t.Errorf("ports = %v", dst.Spec.Ports)
+	}
+	if len(dst.Spec.Repositories) != 2 || !dst.Spec.Repositories[0].Primary || dst.Spec.Repositories[0].Ref != "main" {
+		t.Errorf("repositories = %+v", dst.Spec.Repositories)
+	}
+	if dst.Spec.Lifecycle.Command != v1.WorkSpaceStart {
+		t.Errorf("command = %q", dst.Spec.Lifecycle.Command)
+	}
+	if _, ok := dst.Annotations[AnnotationHardware]; ok {
+		t.Errorf("unexpected hardware annotation for the derived hardware")
+	}
+}
+
+func TestV1RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(space *v1.WorkSpace)
+	}{
+		{name: "full", modify: func(space *v1.WorkSpace) {}},
+		{name: "custom hardware", modify: func(space *v1.WorkSpace) { space.Spec.Hardware = "2C4G8G-gpu" }},
+		{name: "empty hardware", modify: func(space *v1.WorkSpace) { space.Spec.Hardware = "" }},
+		{name: "no primary repository", modify: func(space *v1.WorkSpace) {
+			space.Spec.GitRepository, space.Spec.GitRef, space.Spec.GitDepth = "", "", 0
+		}},
+		{name: "empty resources", modify: func(space *v1.WorkSpace) {
+			space.Spec.Cpu, space.Spec.Memory, space.Spec.Storage, space.Spec.Hardware = "", "", "", ""
+		}},
+		{name: "no annotations", modify: func(space *v1.WorkSpace) { space.Annotations = nil }},
+		{name: "non-canonical resources", modify: func(space *v1.WorkSpace) {
+			space.Spec.Cpu, space.Spec.Memory, space.Spec.Hardware = "0.5", "1024Mi", "0.5C1024M8G"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := v1Workspace()
+			tt.modify(src)
+			orig := src.DeepCopy()
+
+			var mid WorkSpace
+			if err := mid.ConvertFrom(src); err != nil {
+				t.Fatal(err)
+			}
+			var dst v1.WorkSpace
+			if err := mid.ConvertTo(&dst); err != nil {
+				t.Fatal(err)
+			}
+
+			if !equality.Semantic.DeepEqual(orig, &dst) {
+				t.Errorf("round trip changed the workspace\nwant: %+v\ngot:  %+v", orig, &dst)
+			}
+			if !equality.Semantic.DeepEqual(orig, src) {
+				t.Errorf("conversion modified the source")
+			}
+		})
+	}
+}
+
+func TestV1beta2RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(space *WorkSpace)
+	}{
+		{name: "converted", modify: func(space *WorkSpace) {}},
+		{name: "extra ports", modify: func(space *WorkSpace) {
+			space.Spec.Ports = []WorkspacePort{{Name: "web", Port: 8080}, {Name: PortNameIDE, Port: 9999}}
+		}},
+		{name: "unnamed ide port", modify: func(space *WorkSpace) {
+			space.Spec.Ports = []WorkspacePort{{Name: "code", Port: 9999}}
+		}},
+		{name: "primary path and index", modify: func(space *WorkSpace) {
+			primary := space.Spec.Repositories[0]
+			primary.Path = "src/cloud-ide"
+			space.Spec.Repositories = []GitRepository{space.Spec.Repositories[1], primary}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var src WorkSpace
+			if err := src.ConvertFrom(v1Workspace()); err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(&src)
+			orig := src.DeepCopy()
+
+			var mid v1.WorkSpace
+			if err := src.ConvertTo(&mid); err != nil {
+				t.Fatal(err)
+			}
+			if mid.Spec.Port != 9999 {
+				t.Errorf("port = %d, want 9999", mid.Spec.Port)
+			}
+			var dst WorkSpace
+			if err := dst.ConvertFrom(&mid); err != nil {
+				t.Fatal(err)
+			}
+
+			if !equality.Semantic.DeepEqual(orig, &dst) {
+				t.Errorf("round trip changed the workspace\nwant: %+v\ngot:  %+v", orig.Spec, dst.Spec)
+			}
+		})
+	}
+}
+
+func TestConvertNonCanonicalQuantity(t *testing.T) {
+	src := v1Workspace()
+	src.Spec.Cpu = "1.5"
+	src.Spec.Hardware = "1.5C4G8G"
+
+	var mid WorkSpace
+	if err := mid.ConvertFrom(src); err != nil {
+		t.Fatal(err)
+	}
+	var dst v1.WorkSpace
+	if err := mid.ConvertTo(&dst); err != nil {
+		t.Fatal(err)
+	}
+
+	// 资源没有被修改时保留v1中原始的字符串, 硬件描述保持不变
+	if dst.Spec.Cpu != "1.5" {
+		t.Errorf("cpu = %q, want 1.5", dst.Spec.Cpu)
+	}
+	if dst.Spec.Hardware != "1.5C4G8G" {
+		t.Errorf("hardware = %q, want 1.5C4G8G", dst.Spec.Hardware)
+	}
+
+	// 在v1beta2中修改之后使用规范的格式
+	mid.Spec.Resources.Cpu = resource.MustParse("2500m")
+	if err := mid.ConvertTo(&dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Spec.Cpu != "2500m" {
+		t.Errorf("cpu = %q, want 2500m", dst.Spec.Cpu)
+	}
+}
+
+func TestConvertInvalidQuantity(t *testing.T) {
+	src := v1Workspace()
+	src.Spec.Memory = "4G-"
+
+	var dst WorkSpace
+	if err := dst.ConvertFrom(src); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+// webhook builder只有在所有版本都可以与hub转换时才会注册/convert
+func TestIsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := conversion.IsConvertible(scheme, &v1.WorkSpace{})
+	if err != nil || !ok {
+		t.Errorf("WorkSpace is not convertible: %v", err)
+	}
+}
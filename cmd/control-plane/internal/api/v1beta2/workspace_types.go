@@ -0,0 +1,169 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	v1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 与v1相同的类型直接使用v1中的定义, 状态也与v1相同
+
+const (
+	// PortNameIDE IDE使用的端口的名称
+	PortNameIDE = "ide"
+)
+
+// WorkSpaceSpec defines the desired state of WorkSpace
+type WorkSpaceSpec struct {
+	// user id
+	// +kubebuilder:validation:MinLength=6
+	// +kubebuilder:validation:MaxLength=24
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+$`
+	UID string `json:"uid"`
+
+	// space id
+	// +kubebuilder:validation:MinLength=6
+	// +kubebuilder:validation:MaxLength=24
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+$`
+	SID string `json:"sid"`
+
+	// The image
+	Image string `json:"image"`
+
+	// The name of the secret used to pull a private image
+	// +optional
+	ImagePullSecret string `json:"imagePullSecret,omitempty"`
+
+	// The resource limits of the workspace
+	Resources WorkspaceResources `json:"resources"`
+
+	// Volume mount path
+	// +kubebuilder:validation:Pattern=`^\/(?:[\w-]+\/)*(?:[\w-]+\.[\w-]+|[\w-]+\/?)$`
+	MountPath string `json:"mountPath"`
+
+	// The ports exposed by the workspace, the port named "ide" or the first port is the port of the IDE
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Ports []WorkspacePort `json:"ports"`
+
+	// The git repositories cloned into the workspace, each one is cloned by its own init container
+	// +optional
+	Repositories []GitRepository `json:"repositories,omitempty"`
+
+	// The dotfiles repository of the user, it's cloned or updated and installed at every start
+	// +optional
+	Dotfiles *v1.GitRepository `json:"dotfiles,omitempty"`
+
+	// The desired state, hooks and schedule of the workspace
+	// +optional
+	Lifecycle WorkspaceLifecycle `json:"lifecycle,omitempty"`
+
+	// The scheduling constraints of the hardware spec, configured by admins
+	// +optional
+	Scheduling *v1.WorkspaceScheduling `json:"scheduling,omitempty"`
+
+	// The egress allowlist of the template, the default egress policy of the control plane is used when it's nil
+	// +optional
+	Egress *v1.WorkspaceEgress `json:"egress,omitempty"`
+}
+
+// WorkspaceResources is the resource limits of the workspace
+type WorkspaceResources struct {
+	// +optional
+	Cpu resource.Quantity `json:"cpu,omitempty"`
+
+	// +optional
+	Memory resource.Quantity `json:"memory,omitempty"`
+
+	// The size of the workspace volume
+	// +optional
+	Storage resource.Quantity `json:"storage,omitempty"`
+}
+
+// WorkspacePort is a port exposed by the workspace container
+type WorkspacePort struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:validation:Minimum=1024
+	Port int32 `json:"port"`
+}
+
+// GitRepository describes a git repository cloned into the workspace
+type GitRepository struct {
+	v1.GitRepository `json:",inline"`
+
+	// The devcontainer.json of the primary repository is applied to the workspace, at most one repository is primary
+	// +optional
+	Primary bool `json:"primary,omitempty"`
+}
+
+// WorkspaceLifecycle describes how the workspace is started and stopped
+type WorkspaceLifecycle struct {
+	// The command can be "Start", "Stop" or ""
+	// +optional
+	Command v1.WorkspaceCommand `json:"command,omitempty"`
+
+	// The commands executed at the lifecycle of the workspace, the template hooks are overridden by the workspace hooks
+	// +optional
+	Hooks *v1.LifecycleHooks `json:"hooks,omitempty"`
+
+	// The time when the running workspace is stopped, the stop can be canceled before this time
+	// +optional
+	ScheduledStop *metav1.Time `json:"scheduledStop,omitempty"`
+
+	// The schedule to start and stop the workspace automatically
+	// +optional
+	Schedule *v1.WorkspaceSchedule `json:"schedule,omitempty"`
+
+	// What to do with the data of the workspace when it's deleted, the data is deleted immediately when it's nil
+	// +optional
+	Retention *v1.WorkspaceRetention `json:"retention,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="CPU",type=string,JSONPath=`.spec.resources.cpu`
+// +kubebuilder:printcolumn:name="Memory",type=string,JSONPath=`.spec.resources.memory`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// WorkSpace is the Schema for the workspaces API
+type WorkSpace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkSpaceSpec      `json:"spec,omitempty"`
+	Status v1.WorkSpaceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkSpaceList contains a list of WorkSpace
+type WorkSpaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkSpace `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkSpace{}, &WorkSpaceList{})
+}
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/devcontainer"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 读取devcontainer.json的临时Pod的名称
+func devContainerPodName(name string) string {
+	return name + "-devcontainer"
+}
+
+// git-cloner将主仓库中的devcontainer.json复制到存储卷中的该路径, 仓库中没有devcontainer.json时为空文件
+func devContainerPath(space *mv1.WorkSpace) string {
+	return filepath.Join(space.Spec.MountPath, ".cloud-ide", "devcontainer.json")
+}
+
+// 在创建工作空间Pod之前读取主仓库中的devcontainer.json, Pod创建时就应用了devcontainer配置, 不需要再重建Pod
+// 由临时Pod克隆仓库, 克隆完成后从存储卷中读取devcontainer.json, 读取之后删除临时Pod
+// 每次修改工作空间的配置(例如启动)之后检查一次, 主仓库的url和ref没有变化时使用上一次读取的结果
+// 返回true表示已经读取完成, 可以创建工作空间Pod
+func (r *WorkSpaceReconciler) resolveDevContainer(ctx context.Context, space *mv1.WorkSpace) (bool, error) {
+	repositories := workspaceRepositories(space)
+	if len(repositories) == 0 || r.executor == nil {
+		return true, nil
+	}
+	if c := meta.FindStatusCondition(space.Status.Conditions, mv1.ConditionDevContainer); c != nil {
+		if c.ObservedGeneration == space.Generation {
+			return true, r.deleteDevContainerPod(ctx, space)
+		}
+		// git-cloner不会更新已经存在的仓库, url和ref不变时检出的commit也不变, 不需要再克隆一次
+		if devContainerSourceUnchanged(space.Status.DevContainerSource, &repositories[0]) {
+			condition := *c
+			condition.ObservedGeneration = space.Generation
+			meta.SetStatusCondition(&space.Status.Conditions, condition)
+			if err := r.Status().Update(ctx, space); err != nil {
+				return false, err
+			}
+			return true, r.deleteDevContainerPod(ctx, space)
+		}
+	}
+
+	pod := &v1.Pod{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: devContainerPodName(space.Name), Namespace: space.Namespace}, pod)
+	if errors.IsNotFound(err) {
+		pod = r.constructDevContainerPod(space, &repositories[0])
+		if err := controllerutil.SetControllerReference(space, pod, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Client.Create(ctx, pod); err != nil && !errors.IsAlreadyExists(err) {
+			return false, err
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// 上一次读取使用的Pod还没有删除完成
+	if pod.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               mv1.ConditionDevContainer,
+		ObservedGeneration: space.Generation,
+	}
+	var config *mv1.DevContainerConfig
+	var source *mv1.DevContainerSource
+	switch {
+	case pod.Status.Phase == v1.PodRunning:
+		content, err := r.executor.Exec(ctx, pod, devContainerReaderName, []string{"cat", devContainerPath(space)})
+		if err != nil {
+			// 读取失败时使用模板的默认配置启动, 不阻塞工作空间的启动
+			log.FromContext(ctx).Error(err, "read devcontainer.json", "name", space.Name)
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "ReadFailed"
+			condition.Message = fmt.Sprintf("read devcontainer.json: %v", err)
+			break
+		}
+		config = parseDevContainer(content, &condition)
+		source = r.devContainerSource(ctx, space, pod, &repositories[0])
+	case podFinished(pod):
+		// 克隆失败, 工作空间Pod中的git-cloner会再次克隆并上报失败的原因
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CloneFailed"
+		condition.Message = "failed to clone repository, devcontainer.json is not applied"
+	default:
+		return false, nil
+	}
+
+	space.Status.DevContainer = config
+	space.Status.DevContainerSource = source
+	meta.SetStatusCondition(&space.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, space); err != nil {
+		return false, err
+	}
+
+	return true, r.deleteDevContainerPod(ctx, space)
+}
+
+// 读取临时Pod中检出的commit, 读取失败时返回nil, 下一次启动时重新读取devcontainer.json
+func (r *WorkSpaceReconciler) devContainerSource(ctx context.Context, space *mv1.WorkSpace, pod *v1.Pod, repo *mv1.GitRepository) *mv1.DevContainerSource {
+	dir := repositoryPath(filepath.Join(space.Spec.MountPath, "/workspace"), repo)
+	// 存储卷中的仓库由git-cloner创建, 所有者可能与读取的用户不同
+	out, err := r.executor.Exec(ctx, pod, devContainerReaderName, []string{"git", "-c", "safe.directory=*", "-C", dir, "rev-parse", "HEAD"})
+	commit := strings.TrimSpace(string(out))
+	if err != nil || commit == "" {
+		log.FromContext(ctx).Error(err, "read repository commit", "name", space.Name)
+		return nil
+	}
+
+	return &mv1.DevContainerSource{URL: repo.URL, Ref: repo.Ref, Commit: commit}
+}
+
+func devContainerSourceUnchanged(source *mv1.DevContainerSource, repo *mv1.GitRepository) bool {
+	return source != nil && source.Commit != "" && source.URL == repo.URL && source.Ref == repo.Ref
+}
+
+// 解析devcontainer.json并设置状态条件, 配置有误时返回nil, 使用模板的默认配置启动
+func parseDevContainer(content []byte, condition *metav1.Condition) *mv1.DevContainerConfig {
+	if len(strings.TrimSpace(string(content))) == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "NotFound"
+		condition.Message = "devcontainer.json not found in repository"
+		return nil
+	}
+
+	config, err := devcontainer.Parse(content, DevContainerFeatures, DevContainerImages)
+	if err != nil {
+		var unsupported *devcontainer.UnsupportedKeyError
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidConfig"
+		if stderrors.As(err, &unsupported) {
+			condition.Reason = "UnsupportedKeys"
+		}
+		condition.Message = err.Error()
+		return nil
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "Applied"
+	condition.Message = "devcontainer.json applied"
+	return config
+}
+
+// 构造读取devcontainer.json的临时Pod, 使用init容器克隆主仓库, 然后等待控制器读取
+func (r *WorkSpaceReconciler) constructDevContainerPod(space *mv1.WorkSpace, repo *mv1.GitRepository) *v1.Pod {
+	workspaceDir := filepath.Join(space.Spec.MountPath, "/workspace")
+	cloner := constructGitCloner(space, workspaceVolumeName, gitClonerName(0), repo, repositoryPath(workspaceDir, repo))
+	cloner.Env = append(cloner.Env, v1.EnvVar{Name: "DEVCONTAINER_PATH", Value: devContainerPath(space)})
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      devContainerPodName(space.Name),
+			Namespace: space.Namespace,
+			// 使用工作空间的标签, 与工作空间Pod使用相同的网络策略
+			Labels: map[string]string{
+				"app":          devContainerAppLabel,
+				LabelWorkspace: space.Name,
+			},
+		},
+		Spec: v1.PodSpec{
+			RestartPolicy:                 v1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: pointer.Int64(0),
+			ActiveDeadlineSeconds:         pointer.Int64(int64(devContainerPodTimeout.Seconds())),
+			Volumes: []v1.Volume{
+				workspaceVolume(space),
+				gitCredentialVolumeSource(space),
+			},
+			InitContainers: []v1.Container{cloner},
+			Containers: []v1.Container{
+				{
+					Name:            devContainerReaderName,
+					Image:           GitClonerName,
+					ImagePullPolicy: v1.PullIfNotPresent,
+					Command:         []string{"sleep", fmt.Sprint(int64(devContainerPodTimeout.Seconds()))},
+					VolumeMounts: []v1.VolumeMount{
+						{
+							Name:      workspaceVolumeName,
+							ReadOnly:  true,
+							MountPath: space.Spec.MountPath,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	applyScheduling(pod, space.Spec.Scheduling)
+	applySecurity(pod)
+
+	return pod
+}
+
+func (r *WorkSpaceReconciler) deleteDevContainerPod(ctx context.Context, space *mv1.WorkSpace) error {
+	pod := &v1.Pod{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: devContainerPodName(space.Name), Namespace: space.Namespace}, pod)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if pod.DeletionTimestamp != nil {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Client.Delete(ctx, pod, client.GracePeriodSeconds(0)))
+}
+
+// 将devcontainer配置应用到Pod中, image在解析时已经检查过是否在管理员配置的白名单中
+// postCreateCommand与工作空间的postCreate钩子一起执行, 见applyHooks
+func applyDevContainer(pod *v1.Pod, config *mv1.DevContainerConfig) {
+	pod.Annotations[AnnotationDevContainer] = config.Hash
+
+	container := &pod.Spec.Containers[0]
+	if config.Image != "" {
+		container.Image = config.Image
+	}
+
+	// remoteEnv与containerEnv同时设置时, 以remoteEnv为准
+	container.Env = append(container.Env, sortedEnv(config.ContainerEnv)...)
+	container.Env = append(container.Env, sortedEnv(config.RemoteEnv)...)
+
+	for _, port := range config.ForwardPorts {
+		if port == container.Ports[0].ContainerPort {
+			continue
+		}
+		container.Ports = append(container.Ports, v1.ContainerPort{ContainerPort: port})
+	}
+}
+
+func sortedEnv(env map[string]string) []v1.EnvVar {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	envs := make([]v1.EnvVar, 0, len(keys))
+	for _, k := range keys {
+		envs = append(envs, v1.EnvVar{Name: k, Value: env[k]})
+	}
+
+	return envs
+}
+
+// 将命令的参数用单引号转义后拼接
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// 工作空间运行时可能改变并且影响Pod的配置, 例如升级镜像或者修改规格
+// 环境变量、钩子等配置只在启动时更新, 不需要重建Pod
+type podSpec struct {
+	Image      string                   `json:"image"`
+	Cpu        string                   `json:"cpu"`
+	Memory     string                   `json:"memory"`
+	Port       int32                    `json:"port"`
+	Scheduling *mv1.WorkspaceScheduling `json:"scheduling,omitempty"`
+}
+
+// 计算影响Pod的工作空间配置的hash值, 记录在Pod的注解中
+func specHash(space *mv1.WorkSpace) string {
+	data, _ := json.Marshal(podSpec{
+		Image:      space.Spec.Image,
+		Cpu:        space.Spec.Cpu,
+		Memory:     space.Spec.Memory,
+		Port:       space.Spec.Port,
+		Scheduling: space.Spec.Scheduling,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// 返回Pod需要重建的原因, 不需要重建时返回空字符串
+func podDrift(space *mv1.WorkSpace, pod *v1.Pod) string {
+	// Pod被驱逐或者容器退出后不会再恢复运行
+	if podFinished(pod) {
+		return "pod " + string(pod.Status.Phase)
+	}
+
+	expected := ""
+	if space.Status.DevContainer != nil {
+		expected = space.Status.DevContainer.Hash
+	}
+	if pod.Annotations[AnnotationDevContainer] != expected {
+		return "devcontainer changed"
+	}
+
+	// 之前的版本创建的Pod没有记录hash值, 不重建这些Pod
+	if hash, ok := pod.Annotations[AnnotationSpecHash]; ok && hash != specHash(space) {
+		return "spec changed"
+	}
+
+	return ""
+}
+
+func podFinished(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodFailed || pod.Status.Phase == v1.PodSucceeded
+}
@@ -0,0 +1,20 @@
+package controllers
+
+// 工作空间事件的原因, 可以通过kubectl describe workspace或者grpc接口GetWorkspaceEvents查看
+// 状态转换的事件由lifecycle记录, 原因为新的状态
+const (
+	EventPVCCreated         = "PVCCreated"
+	EventPVCRestored        = "PVCRestored"
+	EventPVCCreateFailed    = "PVCCreateFailed"
+	EventPodCreated         = "PodCreated"
+	EventPodCreateFailed    = "PodCreateFailed"
+	EventPodRecreating      = "PodRecreating"
+	EventPodDeleted         = "PodDeleted"
+	EventRepositoryCloned   = "RepositoryCloned"
+	EventCloneFailed        = "RepositoryCloneFailed"
+	EventEndpointRegistered = "EndpointRegistered"
+	EventVolumeRetained     = "VolumeRetained"
+	EventSnapshotCreated    = "VolumeSnapshotCreated"
+	EventSnapshotFailed     = "VolumeSnapshotFailed"
+	EventVolumeDeleted      = "VolumeDeleted"
+)
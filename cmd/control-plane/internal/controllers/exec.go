@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PodExecutor 在Pod的容器中执行命令, 返回命令的标准输出
+type PodExecutor interface {
+	Exec(ctx context.Context, pod *v1.Pod, container string, command []string) ([]byte, error)
+}
+
+// 命令标准输出的最大字节数
+const execOutputLimit = 64 * 1024
+
+var errOutputTooLarge = errors.New("exec output too large")
+
+type remoteExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor 通过kubernetes的pods/exec接口执行命令
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remoteExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *remoteExecutor) Exec(ctx context.Context, pod *v1.Pod, container string, command []string) ([]byte, error) {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	transport, upgrader, err := spdy.RoundTripperFor(e.config)
+	if err != nil {
+		return nil, err
+	}
+	// 当前版本的Stream不支持context, 建立连接的请求使用ctx, 超时后关闭连接使Stream返回
+	executor, err := remotecommand.NewSPDYExecutorForTransports(
+		&contextRoundTripper{ctx: ctx, next: transport},
+		&contextUpgrader{ctx: ctx, next: upgrader},
+		http.MethodPost, req.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &limitedBuffer{limit: execOutputLimit}
+	var stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{Stdout: stdout, Stderr: &stderr})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	if stdout.exceeded {
+		return nil, errOutputTooLarge
+	}
+
+	return stdout.Bytes(), nil
+}
+
+type contextRoundTripper struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (t *contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// ctx结束时关闭升级后的连接, 连接关闭后Stream中读写流的goroutine随之退出
+type contextUpgrader struct {
+	ctx  context.Context
+	next spdy.Upgrader
+}
+
+func (u *contextUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.next.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			conn.Close()
+		case <-conn.CloseChan():
+		}
+	}()
+
+	return conn, nil
+}
+
+// 超过限制的输出被丢弃
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remain := b.limit - b.Len(); len(p) > remain {
+		b.exceeded = true
+		if remain > 0 {
+			b.Buffer.Write(p[:remain])
+		}
+		return len(p), nil
+	}
+
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// 工作空间需要克隆的仓库, GitRepository作为主仓库排在第一位
+func workspaceRepositories(space *mv1.WorkSpace) []mv1.GitRepository {
+	repositories := make([]mv1.GitRepository, 0, len(space.Spec.Repositories)+1)
+	if space.Spec.GitRepository != "" {
+		repositories = append(repositories, mv1.GitRepository{
+			URL:   space.Spec.GitRepository,
+			Ref:   space.Spec.GitRef,
+			Depth: space.Spec.GitDepth,
+		})
+	}
+
+	return append(repositories, space.Spec.Repositories...)
+}
+
+// 仓库在工作空间中的路径, 默认使用仓库的名称
+func repositoryPath(workspaceDir string, repo *mv1.GitRepository) string {
+	if repo.Path != "" {
+		return filepath.Join(workspaceDir, repo.Path)
+	}
+
+	return filepath.Join(workspaceDir, utils.GitRepositoryName(repo.URL))
+}
+
+func gitClonerName(i int) string {
+	return GitClonerContainerName + "-" + strconv.Itoa(i)
+}
+
+func isGitCloner(name string) bool {
+	return name == DotfilesContainerName || strings.HasPrefix(name, GitClonerContainerName+"-")
+}
+
+// 每个仓库使用一个init容器克隆, dotfiles在所有仓库克隆完成后安装
+func applyGitCloners(pod *v1.Pod, space *mv1.WorkSpace, volumeName, workspaceDir string, repositories []mv1.GitRepository) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, gitCredentialVolumeSource(space))
+	for i, repo := range repositories {
+		localPath := repositoryPath(workspaceDir, &repo)
+		container := constructGitCloner(space, volumeName, gitClonerName(i), &repo, localPath)
+		if i == 0 {
+			// 设置环境变量，code-server打开时使用该路径
+			pod.Spec.Containers[0].Env[0].Value = localPath
+		}
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, container)
+	}
+
+	// dotfiles在每次启动时更新并安装, 安装失败不影响工作空间的启动
+	if space.Spec.Dotfiles != nil {
+		container := constructGitCloner(space, volumeName, DotfilesContainerName, space.Spec.Dotfiles,
+			filepath.Join(space.Spec.MountPath, ".dotfiles"))
+		container.Env = append(container.Env,
+			v1.EnvVar{Name: "DOTFILES", Value: "true"},
+			v1.EnvVar{Name: "HOME_DIR", Value: space.Spec.MountPath},
+		)
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, container)
+	}
+}
+
+// 克隆私有仓库使用的凭证, 没有凭证时Secret不存在
+func gitCredentialVolumeSource(space *mv1.WorkSpace) v1.Volume {
+	return v1.Volume{
+		Name: gitCredentialVolume,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName:  mv1.GitSecretName(space.Name),
+				DefaultMode: pointer.Int32(0400),
+				Optional:    pointer.Bool(true),
+			},
+		},
+	}
+}
+
+// 构造克隆git仓库的init容器
+func constructGitCloner(space *mv1.WorkSpace, volumeName, name string, repo *mv1.GitRepository, localPath string) v1.Container {
+	return v1.Container{
+		Name:            name,
+		Image:           GitClonerName,
+		WorkingDir:      space.Spec.MountPath,
+		ImagePullPolicy: v1.PullIfNotPresent,
+		// 容器挂载存储卷
+		VolumeMounts: []v1.VolumeMount{
+			{
+				Name:      volumeName,
+				ReadOnly:  false,
+				MountPath: space.Spec.MountPath,
+			},
+			{
+				Name:      gitCredentialVolume,
+				ReadOnly:  true,
+				MountPath: GitCredentialMountPath,
+			},
+		},
+		Env: []v1.EnvVar{
+			{
+				Name:  "REPO_URL",
+				Value: repo.URL,
+			},
+			{
+				Name:  "LOCAL_PATH",
+				Value: localPath,
+			},
+			{
+				Name:  "GIT_REF",
+				Value: repo.Ref,
+			},
+			{
+				Name:  "GIT_DEPTH",
+				Value: strconv.Itoa(int(repo.Depth)),
+			},
+			{
+				Name:  "CREDENTIAL_PATH",
+				Value: GitCredentialMountPath,
+			},
+		},
+		// git-cloner将错误信息写入到终止消息中
+		TerminationMessagePath:   v1.TerminationMessagePathDefault,
+		TerminationMessagePolicy: v1.TerminationMessageReadFile,
+	}
+}
+
+// 根据init容器的状态生成仓库的克隆状态
+func repositoryStatuses(pod *v1.Pod) []mv1.RepositoryStatus {
+	states := make(map[string]v1.ContainerState, len(pod.Status.InitContainerStatuses))
+	for _, status := range pod.Status.InitContainerStatuses {
+		states[status.Name] = status.State
+	}
+
+	var res []mv1.RepositoryStatus
+	for _, container := range pod.Spec.InitContainers {
+		if !isGitCloner(container.Name) {
+			continue
+		}
+
+		status := mv1.RepositoryStatus{Name: container.Name, State: mv1.RepositoryPending}
+		for _, env := range container.Env {
+			switch env.Name {
+			case "REPO_URL":
+				status.URL = env.Value
+			case "LOCAL_PATH":
+				status.Path = env.Value
+			}
+		}
+
+		state := states[container.Name]
+		switch {
+		case state.Running != nil:
+			status.State = mv1.RepositoryCloning
+		case state.Terminated != nil && state.Terminated.ExitCode == 0:
+			status.State = mv1.RepositoryCloned
+			// dotfiles安装失败时不会导致init容器失败, 而是将错误信息写入终止消息中
+			if msg := state.Terminated.Message; strings.HasPrefix(msg, dotfilesFailedPrefix) {
+				status.State = mv1.RepositoryFailed
+				status.Message = strings.TrimSpace(strings.TrimPrefix(msg, dotfilesFailedPrefix))
+			}
+		case state.Terminated != nil:
+			status.State = mv1.RepositoryFailed
+			status.Message = state.Terminated.Reason
+		case state.Waiting != nil && state.Waiting.Reason == "CrashLoopBackOff":
+			status.State = mv1.RepositoryFailed
+			status.Message = state.Waiting.Message
+		}
+
+		res = append(res, status)
+	}
+
+	return res
+}
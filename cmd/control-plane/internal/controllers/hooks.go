@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/devcontainer"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 将生命周期钩子应用到Pod中
+// postCreate在init容器中执行, postStart和preStop使用容器的生命周期钩子执行
+// 钩子执行失败不会影响工作空间的启动和停止, 执行结果通过终止消息上报
+func applyHooks(pod *v1.Pod, space *mv1.WorkSpace) {
+	var hooks mv1.LifecycleHooks
+	if space.Spec.Hooks != nil {
+		hooks = *space.Spec.Hooks
+	}
+
+	// devcontainer.json中的postCreateCommand在模板的postCreate之后执行
+	postCreate := hooks.PostCreate
+	if dc := space.Status.DevContainer; dc != nil && len(dc.PostCreateCommand) > 0 {
+		if postCreate != "" {
+			postCreate += " && "
+		}
+		postCreate += shellJoin(dc.PostCreateCommand)
+	}
+
+	container := &pod.Spec.Containers[0]
+	hooksDir := filepath.Join(space.Spec.MountPath, ".cloud-ide", "hooks")
+	workDir := container.Env[0].Value
+
+	if postCreate != "" {
+		// postCreate只在第一次执行成功后写入标记文件, 命令改变后会重新执行
+		marker := filepath.Join(hooksDir, "post-create-"+devcontainer.Hash([]byte(postCreate)))
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, v1.Container{
+			Name:                     HookContainerName,
+			Image:                    container.Image,
+			ImagePullPolicy:          container.ImagePullPolicy,
+			Command:                  []string{"/bin/sh", "-c", hookScript(mv1.HookPostCreate, postCreate, hooksDir, workDir, marker, false)},
+			Env:                      append([]v1.EnvVar(nil), container.Env...),
+			EnvFrom:                  append([]v1.EnvFromSource(nil), container.EnvFrom...),
+			VolumeMounts:             append([]v1.VolumeMount(nil), container.VolumeMounts...),
+			TerminationMessagePath:   v1.TerminationMessagePathDefault,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+		})
+	}
+
+	if hooks.PostStart == "" && hooks.PreStop == "" {
+		return
+	}
+
+	// postStart和preStop的结果写入工作空间容器的终止消息中, 在容器停止后上报
+	container.TerminationMessagePath = v1.TerminationMessagePathDefault
+	container.TerminationMessagePolicy = v1.TerminationMessageReadFile
+	container.Lifecycle = &v1.Lifecycle{}
+	if hooks.PostStart != "" {
+		container.Lifecycle.PostStart = &v1.LifecycleHandler{
+			Exec: &v1.ExecAction{
+				Command: []string{"/bin/sh", "-c", hookScript(mv1.HookPostStart, hooks.PostStart, hooksDir, workDir, "", false)},
+			},
+		}
+	}
+	if hooks.PreStop != "" {
+		container.Lifecycle.PreStop = &v1.LifecycleHandler{
+			Exec: &v1.ExecAction{
+				Command: []string{"/bin/sh", "-c", hookScript(mv1.HookPreStop, hooks.PreStop, hooksDir, workDir, "", true)},
+			},
+		}
+	}
+}
+
+// 生成执行钩子的脚本, 完整的输出保存在存储卷中, 退出码和输出的末尾写入终止消息
+// marker不为空时, 标记文件存在则跳过执行, 执行成功后创建标记文件
+// appendMessage为true时追加到终止消息中, 否则覆盖之前的内容
+func hookScript(name mv1.HookName, command, hooksDir, workDir, marker string, appendMessage bool) string {
+	logFile := shellJoin([]string{filepath.Join(hooksDir, string(name)+".log")})
+	redirect := ">"
+	if appendMessage {
+		redirect = ">>"
+	}
+
+	var b strings.Builder
+	if marker != "" {
+		fmt.Fprintf(&b, "[ -f %s ] && exit 0; ", shellJoin([]string{marker}))
+	}
+	fmt.Fprintf(&b, "mkdir -p %s; cd %s 2>/dev/null; ", shellJoin([]string{hooksDir}), shellJoin([]string{workDir}))
+	fmt.Fprintf(&b, "/bin/sh -c %s > %s 2>&1; code=$?; ", shellJoin([]string{command}), logFile)
+	fmt.Fprintf(&b, "{ echo \"%s%s $code\"; tail -c %d %s; } %s %s; ",
+		hookMessagePrefix, name, hookOutputLimit, logFile, redirect, v1.TerminationMessagePathDefault)
+	if marker != "" {
+		fmt.Fprintf(&b, "[ $code -eq 0 ] && touch %s; ", shellJoin([]string{marker}))
+	}
+	b.WriteString("exit 0")
+
+	return b.String()
+}
+
+// 解析终止消息中钩子的执行结果, 每个钩子的结果以"==> <name> <exitCode>"开头
+func parseHookMessage(message string, finishedAt metav1.Time) []mv1.HookStatus {
+	var (
+		res     []mv1.HookStatus
+		current *mv1.HookStatus
+		output  []string
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Output = strings.TrimSpace(strings.Join(output, "\n"))
+		res = append(res, *current)
+	}
+
+	for _, line := range strings.Split(message, "\n") {
+		if status, ok := parseHookHeader(line); ok {
+			flush()
+			status.FinishedAt = finishedAt
+			current, output = &status, nil
+			continue
+		}
+		output = append(output, line)
+	}
+	flush()
+
+	return res
+}
+
+func parseHookHeader(line string) (mv1.HookStatus, bool) {
+	if !strings.HasPrefix(line, hookMessagePrefix) {
+		return mv1.HookStatus{}, false
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, hookMessagePrefix))
+	if len(fields) != 2 {
+		return mv1.HookStatus{}, false
+	}
+	name := mv1.HookName(fields[0])
+	if name != mv1.HookPostCreate && name != mv1.HookPostStart && name != mv1.HookPreStop {
+		return mv1.HookStatus{}, false
+	}
+	code, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return mv1.HookStatus{}, false
+	}
+
+	status := mv1.HookStatus{Name: name, State: mv1.HookSucceeded, ExitCode: int32(code)}
+	if code != 0 {
+		status.State = mv1.HookFailed
+	}
+
+	return status, true
+}
+
+// 从postCreate的init容器以及工作空间容器的终止消息中读取钩子的执行结果
+func hookStatuses(pod *v1.Pod) []mv1.HookStatus {
+	var res []mv1.HookStatus
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name == HookContainerName && status.State.Terminated != nil {
+			res = append(res, parseHookMessage(status.State.Terminated.Message, status.State.Terminated.FinishedAt)...)
+		}
+	}
+
+	if len(pod.Spec.Containers) == 0 {
+		return res
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != pod.Spec.Containers[0].Name {
+			continue
+		}
+		// 优先使用本次的终止状态, 容器重启后使用上一次的终止状态
+		terminated := status.State.Terminated
+		if terminated == nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated != nil {
+			res = append(res, parseHookMessage(terminated.Message, terminated.FinishedAt)...)
+		}
+	}
+
+	return res
+}
+
+// 将新的执行结果合并到已有的结果中, 返回是否发生了变化
+func mergeHookStatuses(current *[]mv1.HookStatus, statuses []mv1.HookStatus) bool {
+	changed := false
+	for _, status := range statuses {
+		found := false
+		for i := range *current {
+			if (*current)[i].Name != status.Name {
+				continue
+			}
+			found = true
+			if !(*current)[i].FinishedAt.Equal(&status.FinishedAt) || (*current)[i].ExitCode != status.ExitCode ||
+				(*current)[i].Output != status.Output {
+				(*current)[i] = status
+				changed = true
+			}
+			break
+		}
+		if !found {
+			*current = append(*current, status)
+			changed = true
+		}
+	}
+
+	return changed
+}
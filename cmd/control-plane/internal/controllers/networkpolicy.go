@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;delete
+
+// ParseCIDRs 解析逗号分隔的地址段
+func ParseCIDRs(s string) ([]string, error) {
+	var cidrs []string
+	for _, cidr := range strings.Split(s, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid cidr %q", cidr)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	return cidrs, nil
+}
+
+// 为工作空间创建网络策略, 只允许gateway所在的命名空间访问IDE的端口, 其它工作空间的Pod无法访问
+// 出站只允许访问DNS、gateway以及模板白名单中的地址, 模板没有配置白名单时使用默认的出站策略
+// 网络策略的OwnerReference为工作空间, 工作空间删除后会被级联删除
+func (r *WorkSpaceReconciler) createNetworkPolicy(ctx context.Context, space *mv1.WorkSpace) error {
+	if !NetworkPolicyEnabled {
+		return nil
+	}
+
+	desired := constructNetworkPolicy(space)
+	if err := controllerutil.SetControllerReference(space, desired, r.Scheme); err != nil {
+		return err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(ctx, time.Second*30)
+	defer cancelFunc()
+
+	var policy networkingv1.NetworkPolicy
+	err := r.Client.Get(ctx, client.ObjectKeyFromObject(desired), &policy)
+	if errors.IsNotFound(err) {
+		return client.IgnoreAlreadyExists(r.Client.Create(ctx, desired))
+	}
+	if err != nil {
+		return err
+	}
+
+	// 工作空间的端口或者配置改变后更新网络策略
+	if equality.Semantic.DeepEqual(policy.Spec, desired.Spec) {
+		return nil
+	}
+	policy.Spec = desired.Spec
+	return r.Client.Update(ctx, &policy)
+}
+
+// 构造工作空间的网络策略, 名称与工作空间相同, 通过工作空间标签选择Pod
+func constructNetworkPolicy(space *mv1.WorkSpace) *networkingv1.NetworkPolicy {
+	tcp := v1.ProtocolTCP
+	port := intstr.FromInt(int(space.Spec.Port))
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      space.Name,
+			Namespace: space.Namespace,
+			Labels: map[string]string{
+				"app": workspaceAppLabel,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{LabelWorkspace: space.Name},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{v1.LabelMetadataName: GatewayNamespace},
+							},
+						},
+					},
+				},
+			},
+			Egress: egressRules(space.Spec.Egress),
+		},
+	}
+}
+
+// 构造出站规则, 第一条规则允许访问所有命名空间中的DNS服务, 第二条规则允许IDE从gateway获取工作空间的通知
+func egressRules(egress *mv1.WorkspaceEgress) []networkingv1.NetworkPolicyEgressRule {
+	udp, tcp := v1.ProtocolUDP, v1.ProtocolTCP
+	dns, https := intstr.FromInt(53), intstr.FromInt(443)
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &dns}, {Protocol: &tcp, Port: &dns}},
+			To:    []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}},
+		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &https}},
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{v1.LabelMetadataName: GatewayNamespace},
+					},
+				},
+			},
+		},
+	}
+
+	if egress == nil {
+		for _, cidr := range DefaultEgressCIDRs {
+			rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: ipBlock(cidr)}},
+			})
+		}
+		return rules
+	}
+
+	for _, r := range egress.Rules {
+		rule := networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{{IPBlock: ipBlock(r.CIDR)}},
+		}
+		for _, p := range r.Ports {
+			port := intstr.FromInt(int(p))
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// 排除包含在地址段中的内部地址段, 白名单中直接指定内部地址段时仍然可以访问
+func ipBlock(cidr string) *networkingv1.IPBlock {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return &networkingv1.IPBlock{CIDR: cidr}
+	}
+
+	block := &networkingv1.IPBlock{CIDR: network.String()}
+	ones, bits := network.Mask.Size()
+	for _, except := range EgressExceptCIDRs {
+		_, e, err := net.ParseCIDR(except)
+		if err != nil {
+			continue
+		}
+		eOnes, eBits := e.Mask.Size()
+		if eBits == bits && eOnes > ones && network.Contains(e.IP) {
+			block.Except = append(block.Except, e.String())
+		}
+	}
+
+	return block
+}
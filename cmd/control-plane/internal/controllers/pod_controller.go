@@ -2,19 +2,27 @@ package controllers
 
 import (
 	"context"
+	"reflect"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/mangohow/cloud-ide/pkg/notifier"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/lifecycle"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/metrics"
 )
 
 // PodReconciler reconciles a Pod object
@@ -22,13 +30,15 @@ type PodReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	notifier notifier.Notifier
+	recorder record.EventRecorder
 }
 
-func NewPodReconciler(c client.Client, scheme *runtime.Scheme, notifier notifier.Notifier) *PodReconciler {
+func NewPodReconciler(c client.Client, scheme *runtime.Scheme, notifier notifier.Notifier, recorder record.EventRecorder) *PodReconciler {
 	return &PodReconciler{
 		Client:   c,
 		Scheme:   scheme,
 		notifier: notifier,
+		recorder: recorder,
 	}
 }
 
@@ -44,6 +54,7 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	// 1.Pod被删除了，更新Workspace状态
 	if errors.IsNotFound(err) {
 		lgr.V(5).Info("pod is terminated", "name", req.Name)
+		metrics.StopFinished(req.Name)
 
 		r.updateWorkspaceStatus(ctx, req.NamespacedName, mv1.WorkspacePhaseStopped)
 
@@ -60,21 +71,42 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	if pod.DeletionTimestamp != nil {
 		lgr.V(5).Info("pod is terminating", "name", req.Name, "phase", pod.Status.Phase)
 
+		// IDE在删除Pod之前已经收到停止通知, 直接从网关中注销
 		r.notifier.Logout(pod.Annotations["sid"])
+		metrics.StopStarted(req.Name, deletionStarted(&pod))
 
 		r.updateWorkspaceStatus(ctx, req.NamespacedName, mv1.WorkspacePhaseStopping)
 
+		// 容器停止后上报postStart和preStop的执行结果
+		if err := r.updateHookStatus(ctx, &pod, req.NamespacedName); err != nil {
+			lgr.Error(err, "update hook status")
+			return ctrl.Result{Requeue: true}, nil
+		}
+
 		return ctrl.Result{}, nil
 	}
 
-	// 3.Pod已经启动完成
+	// 3.更新仓库的克隆状态
+	if err := r.updateRepositoryStatus(ctx, &pod, req.NamespacedName); err != nil {
+		lgr.Error(err, "update repository status")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// 3.1 更新生命周期钩子的执行结果
+	if err := r.updateHookStatus(ctx, &pod, req.NamespacedName); err != nil {
+		lgr.Error(err, "update hook status")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// 4.Pod已经启动完成
 	if pod.Status.Phase == v1.PodRunning {
 		lgr.V(5).Info("pod is running", "name", req.Name, "phase", pod.Status.Phase)
 
-		// 3.1 更新Workspace状态
-		r.updateWorkspaceStatus(ctx, req.NamespacedName, mv1.WorkspacePhaseRunning)
+		// 4.1 更新Workspace状态
+		ws := r.updateWorkspaceStatus(ctx, req.NamespacedName, mv1.WorkspacePhaseRunning)
+		r.updateStartupStatus(ctx, &pod, req.NamespacedName)
 
-		// 3.2 将Workspace注册到网关中
+		// 4.2 将Workspace注册到网关中
 		endpoint := pod.Status.PodIP + ":" + strconv.Itoa(int(pod.Spec.Containers[0].Ports[0].ContainerPort))
 		sid, ok := pod.Annotations["sid"]
 		if !ok {
@@ -82,22 +114,49 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			return ctrl.Result{Requeue: true}, err
 		}
 		r.notifier.Login(sid, endpoint)
+		// 只在工作空间变为Running时记录一次, 避免每次调谐都产生事件
+		if ws != nil {
+			r.recorder.Eventf(ws, v1.EventTypeNormal, EventEndpointRegistered, "registered endpoint %s to the gateway", endpoint)
+			recordPodStartup(ctx, ws, &pod)
+		}
 
-		// 3.3 通知用户Workspace可用
+		// 4.3 通知用户Workspace可用
 		r.notifier.Notify(sid)
 
 		return ctrl.Result{}, nil
 	}
 
+	// 5.Pod运行失败或者容器退出, 由WorkSpaceReconciler重建Pod
+	if podFinished(&pod) {
+		lgr.V(5).Info("pod is finished", "name", req.Name, "phase", pod.Status.Phase)
+		phase := mv1.WorkspacePhaseStopped
+		if pod.Status.Phase == v1.PodFailed {
+			phase = mv1.WorkspacePhaseFailed
+		}
+		r.updateWorkspaceStatus(ctx, req.NamespacedName, phase)
+
+		return ctrl.Result{}, nil
+	}
+
 	lgr.V(5).Info("pod is creating", "name", req.Name, "phase", pod.Status.Phase)
-	// 4.Pod正在被创建,更新ws状态
-	r.updateWorkspaceStatus(ctx, req.NamespacedName, mv1.WorkspacePhaseStaring)
+	// 6.Pod正在被创建,更新ws状态
+	r.updateWorkspaceStatus(ctx, req.NamespacedName, mv1.WorkspacePhaseStarting)
 
 	return ctrl.Result{}, nil
 }
 
-// 更新workspace的状态
-func (r *PodReconciler) updateWorkspaceStatus(ctx context.Context, key client.ObjectKey, phase mv1.WorkSpacePhase) {
+// Pod开始删除的时间, DeletionTimestamp为删除时间加上宽限时间
+func deletionStarted(pod *v1.Pod) time.Time {
+	started := pod.DeletionTimestamp.Time
+	if pod.DeletionGracePeriodSeconds != nil {
+		started = started.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+	}
+
+	return started
+}
+
+// 更新workspace的状态, 状态发生变化时返回更新后的workspace, 否则返回nil
+func (r *PodReconciler) updateWorkspaceStatus(ctx context.Context, key client.ObjectKey, phase mv1.WorkSpacePhase) *mv1.WorkSpace {
 	lgr, _ := logr.FromContext(ctx)
 	var (
 		ws  mv1.WorkSpace
@@ -105,32 +164,177 @@ func (r *PodReconciler) updateWorkspaceStatus(ctx context.Context, key client.Ob
 	)
 	// 1.先查询本地缓存，如果不存在说明被删除了，直接返回
 	if err = r.Client.Get(ctx, key, &ws); errors.IsNotFound(err) {
-		return
+		return nil
 	}
 	if err != nil {
 		lgr.Error(err, "update workspace status")
-		return
+		return nil
 	}
 
-	// 2.如果实际状态就算期望状态，返回
-	if ws.Status.Phase == phase {
-		return
+	// 2.正在删除的工作空间的状态只能为Deleting
+	if ws.DeletionTimestamp != nil {
+		phase = mv1.WorkspacePhaseDeleting
 	}
 
-	// 3.更新状态
-	ws.Status.Phase = phase
+	// 3.通过状态机校验状态转换, 先补全错过的中间状态, 如果实际状态就是期望状态，返回
+	from := lifecycle.Phase(&ws)
+	var changed []mv1.WorkSpacePhase
+	for _, step := range append(missedPhases(from, phase), phase) {
+		ok, err := lifecycle.Transition(&ws, step)
+		if err != nil {
+			lgr.V(1).Info("ignore workspace phase transition", "name", key.Name, "reason", err.Error())
+			return nil
+		}
+		if ok {
+			changed = append(changed, step)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// 4.更新状态, 成功后为每一次状态转换记录事件
 	err = r.Status().Update(ctx, &ws)
 	if err != nil {
 		lgr.Error(err, "update status")
+		return nil
+	}
+	for _, step := range changed {
+		lifecycle.Record(r.recorder, &ws, from, step)
+		from = step
+	}
+
+	return &ws
+}
+
+// Reconciler只能观察到Pod的最新状态, 返回从from转换到观察到的状态to时错过的中间状态
+// 例如Pod创建后很快就运行了, 没有观察到Starting; Pod被删除后很快又被重建, 没有观察到Stopping和Stopped
+// 停止通知的宽限时间内Pod仍在运行, 此时Stopping -> Running不是错过了中间状态, 由状态机拒绝
+func missedPhases(from, to mv1.WorkSpacePhase) []mv1.WorkSpacePhase {
+	switch to {
+	case mv1.WorkspacePhaseRunning:
+		switch from {
+		case mv1.WorkspacePhasePending, mv1.WorkspacePhaseStopped, mv1.WorkspacePhaseFailed:
+			return []mv1.WorkSpacePhase{mv1.WorkspacePhaseStarting}
+		}
+	case mv1.WorkspacePhaseStarting:
+		if from == mv1.WorkspacePhaseRunning {
+			return []mv1.WorkSpacePhase{mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseStopped}
+		}
+	case mv1.WorkspacePhaseStopped:
+		switch from {
+		case mv1.WorkspacePhasePending, mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseFailed:
+			return []mv1.WorkSpacePhase{mv1.WorkspacePhaseStopping}
+		}
+	}
+
+	return nil
+}
+
+// 记录Pod从创建到工作空间容器启动所用的时间, 用于衡量预热Pod对启动速度的提升
+func (r *PodReconciler) updateStartupStatus(ctx context.Context, pod *v1.Pod, key client.ObjectKey) {
+	lgr := log.FromContext(ctx)
+	var startedAt *metav1.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == pod.Spec.Containers[0].Name && status.State.Running != nil {
+			startedAt = &status.State.Running.StartedAt
+		}
+	}
+	if startedAt == nil {
+		return
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var ws mv1.WorkSpace
+		if err := r.Client.Get(ctx, key, &ws); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if ws.Status.Startup != nil && ws.Status.Startup.PodUID == string(pod.UID) {
+			return nil
+		}
+
+		_, warm := pod.Annotations[AnnotationWarmNode]
+		ws.Status.Startup = &mv1.StartupStatus{
+			PodUID:   string(pod.UID),
+			Duration: metav1.Duration{Duration: startedAt.Sub(pod.CreationTimestamp.Time)},
+			Warm:     warm && pod.Spec.NodeName == pod.Annotations[AnnotationWarmNode],
+		}
+		lgr.Info("workspace started", "name", key.Name, "duration", ws.Status.Startup.Duration.Duration, "warm", ws.Status.Startup.Warm)
+
+		return r.Status().Update(ctx, &ws)
+	})
+	if err != nil {
+		lgr.Error(err, "update startup status")
+	}
+}
+
+// 根据init容器的状态更新Workspace中每个仓库的克隆状态
+func (r *PodReconciler) updateRepositoryStatus(ctx context.Context, pod *v1.Pod, key client.ObjectKey) error {
+	statuses := repositoryStatuses(pod)
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	var ws mv1.WorkSpace
+	if err := r.Client.Get(ctx, key, &ws); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if reflect.DeepEqual(ws.Status.Repositories, statuses) {
+		return nil
+	}
+
+	previous := ws.Status.Repositories
+	ws.Status.Repositories = statuses
+	if err := r.Status().Update(ctx, &ws); err != nil {
+		return err
+	}
+
+	recordRepositoryEvents(r.recorder, &ws, previous, statuses)
+	return nil
+}
+
+// 为克隆完成或者失败的仓库记录事件, 状态没有变化的仓库不重复记录
+func recordRepositoryEvents(recorder record.EventRecorder, ws *mv1.WorkSpace, previous, statuses []mv1.RepositoryStatus) {
+	states := make(map[string]mv1.RepositoryState, len(previous))
+	for _, status := range previous {
+		states[status.Name] = status.State
+	}
+
+	for _, status := range statuses {
+		if states[status.Name] == status.State {
+			continue
+		}
+		switch status.State {
+		case mv1.RepositoryCloned:
+			recorder.Eventf(ws, v1.EventTypeNormal, EventRepositoryCloned, "cloned %s into %s", status.URL, status.Path)
+		case mv1.RepositoryFailed:
+			recorder.Eventf(ws, v1.EventTypeWarning, EventCloneFailed, "failed to clone %s: %s", status.URL, status.Message)
+		}
+	}
+}
+
+// 根据终止消息更新Workspace中生命周期钩子的执行结果
+func (r *PodReconciler) updateHookStatus(ctx context.Context, pod *v1.Pod, key client.ObjectKey) error {
+	statuses := hookStatuses(pod)
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	var ws mv1.WorkSpace
+	if err := r.Client.Get(ctx, key, &ws); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !mergeHookStatuses(&ws.Status.Hooks, statuses) {
+		return nil
 	}
 
-	return
+	return r.Status().Update(ctx, &ws)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 8}).
-		For(&v1.Pod{}).
+		For(&v1.Pod{}, builder.WithPredicates(predicateWorkspacePod)).
 		Complete(r)
 }
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordRepositoryEvents(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	previous := []mv1.RepositoryStatus{
+		{Name: gitClonerName(0), URL: "https://github.com/mangohow/cloud-ide.git", Path: "cloud-ide", State: mv1.RepositoryCloning},
+		{Name: gitClonerName(1), URL: "https://github.com/mangohow/docs.git", Path: "docs", State: mv1.RepositoryCloned},
+	}
+	statuses := []mv1.RepositoryStatus{
+		{Name: gitClonerName(0), URL: "https://github.com/mangohow/cloud-ide.git", Path: "cloud-ide", State: mv1.RepositoryCloned},
+		{Name: gitClonerName(1), URL: "https://github.com/mangohow/docs.git", Path: "docs", State: mv1.RepositoryCloned},
+		{Name: gitClonerName(2), URL: "https://github.com/mangohow/private.git", Path: "private", State: mv1.RepositoryFailed, Message: "Error"},
+	}
+
+	recordRepositoryEvents(recorder, testWorkspace(), previous, statuses)
+
+	// 状态没有变化的仓库不重复记录
+	want := []string{
+		"Normal RepositoryCloned cloned https://github.com/mangohow/cloud-ide.git into cloud-ide",
+		"Warning RepositoryCloneFailed failed to clone https://github.com/mangohow/private.git: Error",
+	}
+	if len(recorder.Events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(recorder.Events), len(want))
+	}
+	for _, w := range want {
+		if got := <-recorder.Events; got != w {
+			t.Errorf("event = %q, want %q", got, w)
+		}
+	}
+}
+
+func TestRecordPodStartup(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	// 启动请求的span, 通过注解传递给控制器
+	ctx, request := provider.Tracer("test").Start(context.Background(), "StartSpace")
+	request.End()
+	ws := testWorkspace()
+	ws.Annotations = tracing.InjectAnnotations(ctx, ws.Annotations)
+
+	created := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+		Spec:       v1.PodSpec{NodeName: "node01", Containers: []v1.Container{{Image: "code-server:v1"}}},
+		Status: v1.PodStatus{Conditions: []v1.PodCondition{
+			{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(2 * time.Second))},
+			{Type: v1.PodInitialized, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(10 * time.Second))},
+			{Type: v1.ContainersReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(40 * time.Second))},
+		}},
+	}
+	recordPodStartup(context.Background(), ws, pod)
+
+	want := map[string]time.Duration{"pod.schedule": 2 * time.Second, "pod.initialize": 8 * time.Second, "pod.start": 30 * time.Second}
+	spans := exporter.GetSpans()
+	if len(spans) != 1+len(want) {
+		t.Fatalf("got %d spans, want %d", len(spans), 1+len(want))
+	}
+	for _, s := range spans[1:] {
+		if s.Parent.SpanID() != request.SpanContext().SpanID() {
+			t.Errorf("%s is not a child of the request span", s.Name)
+		}
+		if d := s.EndTime.Sub(s.StartTime); d != want[s.Name] {
+			t.Errorf("%s duration = %s, want %s", s.Name, d, want[s.Name])
+		}
+	}
+
+	// 没有启动请求的上下文时不记录
+	exporter.Reset()
+	recordPodStartup(context.Background(), testWorkspace(), pod)
+	if len(exporter.GetSpans()) != 0 {
+		t.Errorf("spans recorded without a request: %v", exporter.GetSpans())
+	}
+}
+
+func TestUpdateWorkspaceStatusMissedPhases(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to mv1.WorkSpacePhase
+		want     mv1.WorkSpacePhase
+		events   []string
+	}{
+		{name: "missed starting", from: mv1.WorkspacePhasePending, to: mv1.WorkspacePhaseRunning, want: mv1.WorkspacePhaseRunning,
+			events: []string{"Normal Starting workspace phase changed from Pending to Starting", "Normal Running workspace phase changed from Starting to Running"}},
+		{name: "pod recreated", from: mv1.WorkspacePhaseRunning, to: mv1.WorkspacePhaseStarting, want: mv1.WorkspacePhaseStarting,
+			events: []string{"Normal Stopping workspace phase changed from Running to Stopping", "Normal Stopped workspace phase changed from Stopping to Stopped",
+				"Normal Starting workspace phase changed from Stopped to Starting"}},
+		{name: "missed stopping", from: mv1.WorkspacePhaseRunning, to: mv1.WorkspacePhaseStopped, want: mv1.WorkspacePhaseStopped,
+			events: []string{"Normal Stopping workspace phase changed from Running to Stopping", "Normal Stopped workspace phase changed from Stopping to Stopped"}},
+		// 停止通知的宽限时间内Pod仍在运行
+		{name: "stop notice", from: mv1.WorkspacePhaseStopping, to: mv1.WorkspacePhaseRunning, want: mv1.WorkspacePhaseStopping},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &mv1.WorkSpace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws-user01-space01", Namespace: "cloud-ide-ws"},
+				Status:     mv1.WorkSpaceStatus{Phase: tt.from},
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := NewPodReconciler(c, scheme, nil, recorder)
+
+			r.updateWorkspaceStatus(context.Background(), client.ObjectKeyFromObject(ws), tt.to)
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(ws), ws); err != nil {
+				t.Fatal(err)
+			}
+			if ws.Status.Phase != tt.want {
+				t.Errorf("phase = %s, want %s", ws.Status.Phase, tt.want)
+			}
+			close(recorder.Events)
+			var events []string
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			if len(events) != len(tt.events) {
+				t.Fatalf("events = %v, want %v", events, tt.events)
+			}
+			for i := range events {
+				if events[i] != tt.events[i] {
+					t.Errorf("event = %q, want %q", events[i], tt.events[i])
+				}
+			}
+		})
+	}
+}
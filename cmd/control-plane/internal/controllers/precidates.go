@@ -1,15 +1,57 @@
 package controllers
 
 import (
+	"reflect"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
-// 过滤所有的PVC, 防止其触发Reconcile方法
-var predicatePVC = predicate.NewPredicateFuncs(func(object client.Object) bool {
-	return false
+// 工作空间的Pod被删除或者结束运行时触发Reconcile, 工作空间处于启动状态时重新创建Pod
+var predicatePod = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, ok1 := e.ObjectOld.(*v1.Pod)
+		newPod, ok2 := e.ObjectNew.(*v1.Pod)
+		return ok1 && ok2 && !podFinished(oldPod) && podFinished(newPod)
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// PVC被删除时触发Reconcile, 工作空间处于启动状态时重新创建PVC
+var predicatePVC = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return true },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// 只处理工作空间的Pod, 预热Pod和预拉取镜像的Pod不属于任何工作空间
+var predicateWorkspacePod = predicate.NewPredicateFuncs(func(object client.Object) bool {
+	return object.GetLabels()["app"] == workspaceAppLabel
 })
 
-var predicatePod = predicate.NewPredicateFuncs(func(object client.Object) bool {
-	return false
+var predicatePrePull = predicate.NewPredicateFuncs(func(object client.Object) bool {
+	return object.GetName() == mv1.PrePullName
 })
+
+// 工作空间被创建、删除或者容忍的污点改变时更新预拉取镜像的DaemonSet
+var predicateTolerations = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldSpace, ok1 := e.ObjectOld.(*mv1.WorkSpace)
+		newSpace, ok2 := e.ObjectNew.(*mv1.WorkSpace)
+		return ok1 && ok2 && !reflect.DeepEqual(tolerationsOf(oldSpace), tolerationsOf(newSpace))
+	},
+}
+
+func tolerationsOf(space *mv1.WorkSpace) []v1.Toleration {
+	if space.Spec.Scheduling == nil {
+		return nil
+	}
+
+	return space.Spec.Scheduling.Tolerations
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/devcontainer"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// AnnotationPrePullHash DaemonSet中记录的镜像列表的hash值, 镜像列表改变后更新DaemonSet
+const AnnotationPrePullHash = "images-hash"
+
+// ImagePrePullReconciler 根据ConfigMap中的镜像列表维护一个DaemonSet, 将模板镜像预先拉取到所有节点上
+// 镜像列表由webserver通过rpc设置, 每个镜像对应DaemonSet中的一个容器, 容器只运行sleep
+// DaemonSet容忍所有工作空间容忍的污点, 镜像也会被拉取到只运行工作空间的专用节点上
+type ImagePrePullReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func NewImagePrePullReconciler(c client.Client, scheme *runtime.Scheme) *ImagePrePullReconciler {
+	return &ImagePrePullReconciler{
+		Client: c,
+		Scheme: scheme,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;delete
+
+func (r *ImagePrePullReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	lgr := log.FromContext(ctx)
+
+	var cm v1.ConfigMap
+	if err := r.Client.Get(ctx, req.NamespacedName, &cm); err != nil {
+		// ConfigMap被删除后, DaemonSet会被级联删除
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	images, err := prePullImages(&cm)
+	if err != nil {
+		lgr.Error(err, "invalid pre-pull images")
+		return ctrl.Result{}, nil
+	}
+
+	key := client.ObjectKey{Name: mv1.PrePullName, Namespace: req.Namespace}
+	var ds appsv1.DaemonSet
+	err = r.Client.Get(ctx, key, &ds)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	exist := err == nil
+
+	// 没有需要预拉取的镜像时删除DaemonSet
+	if len(images) == 0 {
+		if exist {
+			err = r.Client.Delete(ctx, &ds)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	tolerations, err := r.workspaceTolerations(ctx, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired := constructPrePullDaemonSet(key, images, tolerations)
+	if err := controllerutil.SetControllerReference(&cm, desired, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !exist {
+		lgr.Info("create pre-pull daemonset", "images", len(images))
+		return ctrl.Result{}, client.IgnoreAlreadyExists(r.Client.Create(ctx, desired))
+	}
+	if ds.Annotations[AnnotationPrePullHash] == desired.Annotations[AnnotationPrePullHash] {
+		return ctrl.Result{}, nil
+	}
+
+	lgr.Info("update pre-pull daemonset", "images", len(images))
+	ds.Annotations = desired.Annotations
+	ds.Spec.Template = desired.Spec.Template
+	return ctrl.Result{}, r.Client.Update(ctx, &ds)
+}
+
+// 解析ConfigMap中需要预拉取的镜像列表
+func prePullImages(cm *v1.ConfigMap) ([]mv1.PrePullImage, error) {
+	var images []mv1.PrePullImage
+	if data := cm.Data[mv1.PrePullImagesKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &images); err != nil {
+			return nil, err
+		}
+	}
+
+	return images, nil
+}
+
+// 收集命名空间中所有工作空间容忍的污点, 去重后按照序列化的结果排序, 保证hash值稳定
+func (r *ImagePrePullReconciler) workspaceTolerations(ctx context.Context, namespace string) ([]v1.Toleration, error) {
+	var spaces mv1.WorkSpaceList
+	if err := r.Client.List(ctx, &spaces, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]v1.Toleration)
+	for i := range spaces.Items {
+		scheduling := spaces.Items[i].Spec.Scheduling
+		if scheduling == nil {
+			continue
+		}
+		for _, t := range scheduling.Tolerations {
+			data, _ := json.Marshal(t)
+			seen[string(data)] = t
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var tolerations []v1.Toleration
+	for _, k := range keys {
+		tolerations = append(tolerations, seen[k])
+	}
+
+	return tolerations, nil
+}
+
+// 构造预拉取镜像的DaemonSet, 每个镜像对应一个容器, 容器名称为prepull-i
+// 容器通过init容器复制的静态sleep运行, 不依赖镜像中的shell
+func constructPrePullDaemonSet(key client.ObjectKey, images []mv1.PrePullImage, tolerations []v1.Toleration) *appsv1.DaemonSet {
+	data, _ := json.Marshal(struct {
+		Images      []mv1.PrePullImage `json:"images"`
+		Tolerations []v1.Toleration    `json:"tolerations,omitempty"`
+		SleepImage  string             `json:"sleepImage"`
+	}{images, tolerations, SleepImage})
+	labels := map[string]string{"app": mv1.PrePullName}
+
+	var (
+		containers []v1.Container
+		secrets    []v1.LocalObjectReference
+		seen       = make(map[string]bool)
+	)
+	for i, image := range images {
+		containers = append(containers, v1.Container{
+			Name:            "prepull-" + strconv.Itoa(i),
+			Image:           image.Image,
+			ImagePullPolicy: v1.PullIfNotPresent,
+			// 镜像拉取完成后容器只是运行sleep, 几乎不占用资源
+			Resources: sleepResources(),
+		})
+		if image.PullSecret != "" && !seen[image.PullSecret] {
+			seen[image.PullSecret] = true
+			secrets = append(secrets, v1.LocalObjectReference{Name: image.PullSecret})
+		}
+	}
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				AnnotationPrePullHash: devcontainer.Hash(data),
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					TerminationGracePeriodSeconds: pointer.Int64(0),
+					ImagePullSecrets:              secrets,
+					Tolerations:                   tolerations,
+					Containers:                    containers,
+				},
+			},
+		},
+	}
+	applyStaticSleep(&ds.Spec.Template.Spec)
+
+	return ds
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ImagePrePullReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1.ConfigMap{}, builder.WithPredicates(predicatePrePull)).
+		Owns(&appsv1.DaemonSet{}).
+		Watches(&source.Kind{Type: &mv1.WorkSpace{}}, handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+			// 工作空间容忍的污点改变时更新DaemonSet
+			return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: mv1.PrePullName, Namespace: object.GetNamespace()}}}
+		}), builder.WithPredicates(predicateTolerations)).
+		Complete(r)
+}
@@ -0,0 +1,340 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// 清理过期数据的间隔
+	retentionCollectInterval = time.Hour
+	// 等待Pod删除或者快照完成的间隔
+	finalizeRequeueInterval = 5 * time.Second
+)
+
+// 使用unstructured操作VolumeSnapshot, 集群中没有安装快照的CRD时不影响其它功能
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;create;delete
+
+// 工作空间被删除时先删除Pod, 然后根据保留策略处理存储卷, 最后移除finalizer
+func (r *WorkSpaceReconciler) finalize(ctx context.Context, space *mv1.WorkSpace) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(space, WorkspaceFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// 1.等待Pod删除完成后再处理存储卷, 保证IDE已经将文件写入存储卷
+	key := client.ObjectKeyFromObject(space)
+	exist, err := r.checkPodExist(ctx, key)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if exist {
+		deleted, err := r.deletePod(ctx, key)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if deleted {
+			r.recorder.Eventf(space, v1.EventTypeNormal, EventPodDeleted, "deleted pod %s of the deleted workspace", key.Name)
+		}
+		return ctrl.Result{RequeueAfter: finalizeRequeueInterval}, nil
+	}
+
+	// 2.根据保留策略处理存储卷
+	done, err := r.retainVolume(ctx, space)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !done {
+		return ctrl.Result{RequeueAfter: finalizeRequeueInterval}, nil
+	}
+
+	// 3.移除finalizer, 其它资源通过OwnerReference级联删除
+	controllerutil.RemoveFinalizer(space, WorkspaceFinalizer)
+	return ctrl.Result{}, r.Client.Update(ctx, space)
+}
+
+// 处理被删除的工作空间的存储卷, 返回false时需要等待快照完成
+func (r *WorkSpaceReconciler) retainVolume(ctx context.Context, space *mv1.WorkSpace) (bool, error) {
+	retention := space.Spec.Retention
+	if retention == nil || retention.RetainUntil == nil {
+		return true, r.deleteVolume(ctx, space)
+	}
+
+	switch retention.Policy {
+	case mv1.RetentionRetain:
+		return true, r.retainPVC(ctx, space)
+	case mv1.RetentionSnapshot:
+		return r.snapshotPVC(ctx, space)
+	}
+
+	return true, r.deleteVolume(ctx, space)
+}
+
+// 删除工作空间的PVC并记录事件
+func (r *WorkSpaceReconciler) deleteVolume(ctx context.Context, space *mv1.WorkSpace) error {
+	key := client.ObjectKeyFromObject(space)
+	exist, err := r.checkPVCExist(ctx, key)
+	if err != nil || !exist {
+		return err
+	}
+	if err := r.deletePVC(ctx, key); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(space, v1.EventTypeNormal, EventVolumeDeleted, "deleted volume %s", key.Name)
+	return nil
+}
+
+// 移除PVC的OwnerReference并且记录保留时间, 在保留时间之后由RetentionCollector删除
+func (r *WorkSpaceReconciler) retainPVC(ctx context.Context, space *mv1.WorkSpace) error {
+	var pvc v1.PersistentVolumeClaim
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(space), &pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	refs := pvc.OwnerReferences[:0]
+	for _, ref := range pvc.OwnerReferences {
+		if ref.UID != space.UID {
+			refs = append(refs, ref)
+		}
+	}
+	pvc.OwnerReferences = refs
+	markRetained(&pvc, space)
+
+	log.FromContext(ctx).Info("retain pvc", "name", pvc.Name, "until", space.Spec.Retention.RetainUntil)
+	if err := r.Client.Update(ctx, &pvc); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(space, v1.EventTypeNormal, EventVolumeRetained, "retained volume %s until %s",
+		pvc.Name, space.Spec.Retention.RetainUntil.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// 为PVC创建快照, 快照可以使用后删除PVC
+// 集群不支持快照或者快照失败时保留PVC
+func (r *WorkSpaceReconciler) snapshotPVC(ctx context.Context, space *mv1.WorkSpace) (bool, error) {
+	lgr := log.FromContext(ctx)
+
+	key := client.ObjectKeyFromObject(space)
+	exist, err := r.checkPVCExist(ctx, key)
+	if err != nil || !exist {
+		return true, err
+	}
+
+	// 每次删除的工作空间的uid不同, 快照的名称不会与之前保留的快照冲突
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err = r.Client.Get(ctx, client.ObjectKey{Name: snapshotName(space), Namespace: space.Namespace}, snapshot)
+	if meta.IsNoMatchError(err) {
+		lgr.Info("volume snapshot is not supported, retain pvc", "name", space.Name)
+		return true, r.retainPVC(ctx, space)
+	}
+	if errors.IsNotFound(err) {
+		lgr.Info("create volume snapshot", "name", space.Name)
+		if err := r.Client.Create(ctx, constructVolumeSnapshot(space)); err != nil {
+			return false, err
+		}
+		r.recorder.Eventf(space, v1.EventTypeNormal, EventSnapshotCreated, "creating volume snapshot %s", snapshotName(space))
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if msg, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); msg != "" {
+		lgr.Info("volume snapshot failed, retain pvc", "name", space.Name, "error", msg)
+		r.recorder.Eventf(space, v1.EventTypeWarning, EventSnapshotFailed, "volume snapshot %s failed: %s", snapshotName(space), msg)
+		return true, r.retainPVC(ctx, space)
+	}
+	if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); !ready {
+		return false, nil
+	}
+
+	return true, r.deleteVolume(ctx, space)
+}
+
+func snapshotName(space *mv1.WorkSpace) string {
+	uid := string(space.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+
+	return space.Name + "-" + uid
+}
+
+func constructVolumeSnapshot(space *mv1.WorkSpace) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(snapshotName(space))
+	snapshot.SetNamespace(space.Namespace)
+	markRetained(snapshot, space)
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": space.Name,
+		},
+	}
+	if VolumeSnapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = VolumeSnapshotClassName
+	}
+	snapshot.Object["spec"] = spec
+
+	return snapshot
+}
+
+// 添加保留数据的标签和过期时间
+func markRetained(obj client.Object, space *mv1.WorkSpace) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[LabelRetained] = space.Name
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[AnnotationRetainUntil] = space.Spec.Retention.RetainUntil.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+// 创建同名的工作空间时恢复保留的PVC
+func (r *WorkSpaceReconciler) restorePVC(ctx context.Context, space *mv1.WorkSpace) error {
+	var pvc v1.PersistentVolumeClaim
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(space), &pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, ok := pvc.Labels[LabelRetained]; !ok || metav1.IsControlledBy(&pvc, space) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("restore retained pvc", "name", pvc.Name)
+	delete(pvc.Labels, LabelRetained)
+	delete(pvc.Annotations, AnnotationRetainUntil)
+	if err := controllerutil.SetControllerReference(space, &pvc, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Client.Update(ctx, &pvc); err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(space, v1.EventTypeNormal, EventPVCRestored, "restored retained volume %s", pvc.Name)
+	return nil
+}
+
+// 查找同名工作空间最近一次保留的可用的快照, 没有时返回空字符串
+func (r *WorkSpaceReconciler) findSnapshot(ctx context.Context, space *mv1.WorkSpace) (string, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(volumeSnapshotGVK.GroupVersion().WithKind(volumeSnapshotGVK.Kind + "List"))
+	err := r.Client.List(ctx, list, client.InNamespace(space.Namespace), client.MatchingLabels{LabelRetained: space.Name})
+	if meta.IsNoMatchError(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	snapshots := list.Items
+	sort.Slice(snapshots, func(i, j int) bool {
+		ti, tj := snapshots[i].GetCreationTimestamp(), snapshots[j].GetCreationTimestamp()
+		return tj.Before(&ti)
+	})
+	for _, snapshot := range snapshots {
+		if snapshot.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); ready {
+			return snapshot.GetName(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// RetentionCollector 定时删除超过保留时间的PVC和快照
+// 实现了manager.Runnable, 开启选主时只在leader中运行
+type RetentionCollector struct {
+	client    client.Client
+	logger    logr.Logger
+	namespace string
+}
+
+func NewRetentionCollector(c client.Client, logger logr.Logger, namespace string) *RetentionCollector {
+	return &RetentionCollector{
+		client:    c,
+		logger:    logger.WithName("retention-collector"),
+		namespace: namespace,
+	}
+}
+
+func (c *RetentionCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(retentionCollectInterval)
+	defer ticker.Stop()
+
+	for {
+		c.collect(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *RetentionCollector) collect(ctx context.Context, now time.Time) {
+	var pvcs v1.PersistentVolumeClaimList
+	if err := c.client.List(ctx, &pvcs, client.InNamespace(c.namespace), client.HasLabels{LabelRetained}); err != nil {
+		c.logger.Error(err, "list retained pvc")
+	}
+	for i := range pvcs.Items {
+		c.purge(ctx, "PersistentVolumeClaim", &pvcs.Items[i], now)
+	}
+
+	snapshots := &unstructured.UnstructuredList{}
+	snapshots.SetGroupVersionKind(volumeSnapshotGVK.GroupVersion().WithKind(volumeSnapshotGVK.Kind + "List"))
+	err := c.client.List(ctx, snapshots, client.InNamespace(c.namespace), client.HasLabels{LabelRetained})
+	if err != nil && !meta.IsNoMatchError(err) {
+		c.logger.Error(err, "list retained volume snapshot")
+	}
+	for i := range snapshots.Items {
+		c.purge(ctx, volumeSnapshotGVK.Kind, &snapshots.Items[i], now)
+	}
+}
+
+func (c *RetentionCollector) purge(ctx context.Context, kind string, obj client.Object, now time.Time) {
+	if obj.GetDeletionTimestamp() != nil || !retentionExpired(obj, now) {
+		return
+	}
+
+	c.logger.Info("purge retained data", "kind", kind, "name", obj.GetName())
+	if err := c.client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		c.logger.Error(err, "purge retained data", "kind", kind, "name", obj.GetName())
+	}
+}
+
+// 没有记录保留时间或者保留时间无法解析时认为已经过期
+func retentionExpired(obj client.Object, now time.Time) bool {
+	until, err := time.Parse(time.RFC3339, obj.GetAnnotations()[AnnotationRetainUntil])
+	if err != nil {
+		return true
+	}
+
+	return !now.Before(until)
+}
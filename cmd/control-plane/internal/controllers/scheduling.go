@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 将规格中的调度约束应用到Pod中
+func applyScheduling(pod *v1.Pod, scheduling *mv1.WorkspaceScheduling) {
+	if scheduling == nil {
+		return
+	}
+
+	if len(scheduling.NodeSelector) > 0 {
+		pod.Spec.NodeSelector = make(map[string]string, len(scheduling.NodeSelector))
+		for k, v := range scheduling.NodeSelector {
+			pod.Spec.NodeSelector[k] = v
+		}
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, scheduling.Tolerations...)
+	pod.Spec.PriorityClassName = scheduling.PriorityClassName
+
+	// 所有工作空间的Pod在拓扑域之间均匀分布
+	for _, spread := range scheduling.TopologySpread {
+		whenUnsatisfiable := spread.WhenUnsatisfiable
+		if whenUnsatisfiable == "" {
+			whenUnsatisfiable = v1.DoNotSchedule
+		}
+		pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, v1.TopologySpreadConstraint{
+			MaxSkew:           spread.MaxSkew,
+			TopologyKey:       spread.TopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": workspaceAppLabel},
+			},
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// ParseSeccompProfile 解析seccomp配置, 格式为 RuntimeDefault、Unconfined 或 Localhost/<profile>, 为空时不设置
+func ParseSeccompProfile(s string) (*v1.SeccompProfile, error) {
+	switch {
+	case s == "":
+		return nil, nil
+	case strings.EqualFold(s, string(v1.SeccompProfileTypeRuntimeDefault)):
+		return &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}, nil
+	case strings.EqualFold(s, string(v1.SeccompProfileTypeUnconfined)):
+		return &v1.SeccompProfile{Type: v1.SeccompProfileTypeUnconfined}, nil
+	}
+
+	i := strings.IndexByte(s, '/')
+	if i <= 0 || !strings.EqualFold(s[:i], string(v1.SeccompProfileTypeLocalhost)) || s[i+1:] == "" {
+		return nil, fmt.Errorf("invalid seccomp profile %q, expected RuntimeDefault, Unconfined or Localhost/<profile>", s)
+	}
+
+	return &v1.SeccompProfile{Type: v1.SeccompProfileTypeLocalhost, LocalhostProfile: pointer.String(s[i+1:])}, nil
+}
+
+// 将安全配置应用到工作空间Pod的所有容器中, 需要在添加完init容器之后调用
+func applySecurity(pod *v1.Pod) {
+	// 工作空间中不需要访问kubernetes的API
+	pod.Spec.AutomountServiceAccountToken = pointer.Bool(false)
+	if RuntimeClassName != "" {
+		pod.Spec.RuntimeClassName = pointer.String(RuntimeClassName)
+	}
+
+	podSecurity := &v1.PodSecurityContext{}
+	if RunAsNonRoot {
+		podSecurity.RunAsNonRoot = pointer.Bool(true)
+	}
+	// 使用相同的id作为fsGroup, 使得存储卷中的文件对该用户可写
+	if RunAsUser > 0 {
+		podSecurity.RunAsUser = pointer.Int64(RunAsUser)
+		podSecurity.RunAsGroup = pointer.Int64(RunAsUser)
+		podSecurity.FSGroup = pointer.Int64(RunAsUser)
+	}
+	if SeccompProfile != nil {
+		podSecurity.SeccompProfile = SeccompProfile.DeepCopy()
+	}
+	if podSecurity.RunAsNonRoot != nil || podSecurity.RunAsUser != nil || podSecurity.SeccompProfile != nil {
+		pod.Spec.SecurityContext = podSecurity
+	}
+
+	for i := range pod.Spec.InitContainers {
+		pod.Spec.InitContainers[i].SecurityContext = containerSecurity()
+	}
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].SecurityContext = containerSecurity()
+	}
+}
+
+func containerSecurity() *v1.SecurityContext {
+	if len(DropCapabilities) == 0 && !RunAsNonRoot {
+		return nil
+	}
+
+	sc := &v1.SecurityContext{}
+	if len(DropCapabilities) > 0 {
+		sc.Capabilities = &v1.Capabilities{}
+		for _, c := range DropCapabilities {
+			sc.Capabilities.Drop = append(sc.Capabilities.Drop, v1.Capability(c))
+		}
+	}
+	// 非root用户运行时禁止通过setuid等方式提升权限
+	if RunAsNonRoot {
+		sc.AllowPrivilegeEscalation = pointer.Bool(false)
+	}
+
+	return sc
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/api/core/v1"
+)
+
+var tracer = otel.Tracer("github.com/mangohow/cloud-ide/cmd/control-plane/internal/controllers")
+
+// 创建工作空间调谐的span, 父span为启动请求的span, 从工作空间的注解中恢复
+// 只追踪启动过程中的调谐, 运行中的工作空间调谐频繁, 并且不属于任何请求
+func workspaceSpan(ctx context.Context, space *mv1.WorkSpace, name string) (context.Context, trace.Span) {
+	if space.Status.Phase == mv1.WorkspacePhaseRunning {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	parent := tracing.ExtractAnnotations(ctx, space.Annotations)
+	if !trace.SpanContextFromContext(parent).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return tracer.Start(parent, name, trace.WithAttributes(attribute.String("workspace", space.Name),
+		attribute.String("sid", space.Spec.SID), attribute.String("uid", space.Spec.UID)))
+}
+
+// 创建调谐步骤的span, ctx中没有被追踪的span时不创建, 避免每次调谐都产生新的trace
+func childSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return tracer.Start(ctx, name)
+}
+
+// 根据err设置span的状态并结束span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// 根据Pod的状态条件记录Pod启动各个阶段的span, 在工作空间变为Running时记录一次
+// pod.schedule: 从Pod创建到调度到节点, pod.initialize: 执行init容器, 包括克隆仓库、安装dotfiles和postCreate钩子
+// pod.start: 拉取镜像并启动工作空间容器
+func recordPodStartup(ctx context.Context, space *mv1.WorkSpace, pod *v1.Pod) {
+	ctx = tracing.ExtractAnnotations(ctx, space.Annotations)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	scheduled := podConditionTime(pod, v1.PodScheduled)
+	initialized := podConditionTime(pod, v1.PodInitialized)
+	ready := podConditionTime(pod, v1.ContainersReady)
+	workspace := attribute.String("workspace", space.Name)
+
+	recordSpan(ctx, "pod.schedule", pod.CreationTimestamp.Time, scheduled, workspace, attribute.String("node", pod.Spec.NodeName))
+	recordSpan(ctx, "pod.initialize", scheduled, initialized, workspace, attribute.Int("initContainers", len(pod.Spec.InitContainers)))
+	recordSpan(ctx, "pod.start", initialized, ready, workspace, attribute.String("image", pod.Spec.Containers[0].Image))
+}
+
+func recordSpan(ctx context.Context, name string, start, end time.Time, attrs ...attribute.KeyValue) {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return
+	}
+	_, span := tracer.Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+	span.End(trace.WithTimestamp(end))
+}
+
+// Pod状态条件变为True的时间
+func podConditionTime(pod *v1.Pod, conditionType v1.PodConditionType) time.Time {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == conditionType && c.Status == v1.ConditionTrue {
+			return c.LastTransitionTime.Time
+		}
+	}
+
+	return time.Time{}
+}
@@ -1,8 +1,101 @@
 package controllers
 
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
 var (
-	WorkspaceNamespace    = "cloud-ide-ws"
-	StorageClassName      = "nfs-csi"
-	GitClonerName         = "git-cloner"
+	WorkspaceNamespace = "cloud-ide-ws"
+	StorageClassName   = "nfs-csi"
+	GitClonerName      = "git-cloner"
+	// 提供静态链接的busybox的镜像, 预热Pod和预拉取镜像的Pod使用其中的sleep, 不依赖模板镜像中的shell
+	SleepImage            = "busybox:1.36"
 	DynamicStorageEnabled bool
+	// 停止工作空间时Pod的宽限时间, 运行中的工作空间先通知IDE并等待宽限时间, 再删除Pod并给preStop钩子相同的宽限时间,
+	// 因此最多需要两倍的宽限时间才能停止
+	StopGracePeriod = 30 * time.Second
+	// 允许在devcontainer.json中使用的feature, 这些feature已经内置在工作空间镜像中
+	DevContainerFeatures []string
+	// 允许在devcontainer.json中指定的镜像, 以*结尾时匹配前缀, 为空时不允许覆盖模板的镜像
+	DevContainerImages []string
+	// CPU和内存的超售比例, 请求的资源为规格中的资源除以超售比例, 限制的资源与规格相同
+	CpuOvercommitRatio    = 2.0
+	MemoryOvercommitRatio = 1.0
+	// 工作空间容器可以使用的临时存储, 为空时不限制
+	EphemeralStorageLimit string
+
+	// 工作空间Pod的安全配置
+	RunAsNonRoot bool
+	// 运行容器的用户id, 为0时使用镜像中的用户
+	RunAsUser        int64
+	DropCapabilities = []string{"NET_RAW"}
+	SeccompProfile   = &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault}
+	// 使用gVisor或kata等沙箱运行时的RuntimeClass, 为空时使用默认的运行时
+	RuntimeClassName string
+
+	// 是否为工作空间创建网络策略, 只允许gateway所在的命名空间访问工作空间
+	NetworkPolicyEnabled = true
+	GatewayNamespace     = "cloud-ide"
+	// 模板没有配置出站白名单时允许访问的地址段
+	DefaultEgressCIDRs = []string{"0.0.0.0/0", "::/0"}
+	// 出站时排除的集群内部地址段, 只有在白名单中直接指定时才能访问
+	EgressExceptCIDRs = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "169.254.0.0/16", "fc00::/7", "fe80::/10"}
+
+	// 删除工作空间时为存储卷创建快照使用的VolumeSnapshotClass, 为空时使用默认的VolumeSnapshotClass
+	VolumeSnapshotClassName string
+)
+
+const (
+	// AnnotationDevContainer Pod所应用的devcontainer.json的hash值
+	AnnotationDevContainer = "devcontainer"
+	// AnnotationSpecHash Pod所应用的工作空间配置的hash值, 见specHash
+	AnnotationSpecHash = "cloud-ide.mangohow.com/spec-hash"
+	// GitClonerContainerName 克隆git仓库的init容器名称前缀, 第i个仓库的init容器名称为git-cloner-i
+	GitClonerContainerName = "git-cloner"
+	// DotfilesContainerName 安装dotfiles的init容器名称
+	DotfilesContainerName = "dotfiles"
+	// HookContainerName 执行postCreate钩子的init容器名称
+	HookContainerName = "post-create"
+	// GitCredentialMountPath git凭证在git-cloner中的挂载路径
+	GitCredentialMountPath = "/etc/git-credential"
+
+	// WorkspaceFinalizer 工作空间被删除时根据保留策略处理存储卷, 处理完成后移除
+	WorkspaceFinalizer = "cloud-ide.mangohow.com/cleanup"
+	// LabelRetained 被删除的工作空间保留的PVC和快照的标签, 值为工作空间的名称
+	LabelRetained = "cloud-ide.mangohow.com/retained"
+	// AnnotationRetainUntil 保留的PVC和快照的过期时间, 过期后由RetentionCollector删除
+	AnnotationRetainUntil = "cloud-ide.mangohow.com/retain-until"
+
+	// AnnotationStopDeadline 停止工作空间时通知IDE的截止时间, unix时间戳, 到达截止时间后删除Pod
+	AnnotationStopDeadline = "cloud-ide.mangohow.com/stop-deadline"
+	// NoticeUrlEnv NoticeTokenEnv IDE会话获取工作空间通知的地址和token的环境变量
+	NoticeUrlEnv   = "CLOUD_IDE_NOTICE_URL"
+	NoticeTokenEnv = "CLOUD_IDE_NOTICE_TOKEN"
+
+	// LabelWorkspace 工作空间Pod的标签, 值为工作空间的名称, 网络策略通过该标签选择Pod
+	LabelWorkspace = "cloud-ide.mangohow.com/workspace"
+
+	// 工作空间Pod的app标签
+	workspaceAppLabel = "cloud-ide"
+
+	gitCredentialVolume = "git-credential"
+	// 静态sleep所在的存储卷以及挂载路径
+	sleepVolumeName = "cloud-ide-sleep"
+	sleepMountPath  = "/.cloud-ide-sleep"
+	// 工作空间的存储卷在Pod中的名称
+	workspaceVolumeName = "volume-user-workspace"
+
+	// 读取devcontainer.json的临时Pod的app标签以及等待读取的容器名称
+	devContainerAppLabel   = "cloud-ide-devcontainer"
+	devContainerReaderName = "reader"
+	// 读取devcontainer.json的临时Pod的最长运行时间, 包括克隆仓库的时间
+	devContainerPodTimeout = 10 * time.Minute
+	// dotfiles安装失败时终止消息的前缀
+	dotfilesFailedPrefix = "failed:"
+	// 钩子执行结果在终止消息中的前缀
+	hookMessagePrefix = "==> "
+	// 每个钩子写入终止消息中的输出的最大字节数, 终止消息最大为4096字节
+	hookOutputLimit = 1500
 )
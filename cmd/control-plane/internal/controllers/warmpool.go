@@ -0,0 +1,403 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/devcontainer"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelWarmPod 预热Pod的标签, 值为镜像的hash
+	LabelWarmPod = "cloud-ide.mangohow.com/warm-image"
+	// LabelWarmClaimed 预热Pod被工作空间认领后添加的标签, 值为工作空间的名称
+	LabelWarmClaimed = "cloud-ide.mangohow.com/warm-claimed"
+	// AnnotationWarmNode 工作空间Pod所认领的预热Pod所在的节点
+	AnnotationWarmNode = "warm-node"
+	// AnnotationWarmClaimedAt 预热Pod被认领的时间, unix时间戳
+	AnnotationWarmClaimedAt = "warm-claimed-at"
+
+	warmPodAppLabel = "cloud-ide-warm"
+	// 没有认领时检查预热池的间隔
+	warmPoolInterval = 30 * time.Second
+	// 被认领的预热Pod在工作空间的Pod创建后删除, 超过该时间仍未删除时由预热池删除
+	warmClaimTimeout = 2 * time.Minute
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;delete
+
+// WarmPool 为常用的模板镜像维护一定数量的预热Pod
+// 预热Pod运行模板镜像, 使镜像缓存在节点上
+// Pod创建后不能修改存储卷, 因此启动工作空间时不会直接使用预热Pod, 只是让工作空间的Pod优先调度到预热Pod所在的节点上,
+// 省去拉取镜像的时间, 工作空间的Pod创建成功后再删除预热Pod, 即预热池只提供节点偏好
+// 已经由ImagePrePullReconciler预拉取到所有节点上的镜像不需要节点偏好, 这些镜像不会创建预热Pod, 也不会被认领
+// 实现了manager.Runnable, 开启选主时只在leader中运行
+type WarmPool struct {
+	client    client.Client
+	logger    logr.Logger
+	namespace string
+
+	mu    sync.Mutex
+	sizes map[string]int // 镜像 -> 预热Pod的数量
+
+	trigger chan struct{}
+}
+
+func NewWarmPool(c client.Client, logger logr.Logger, namespace string, sizes map[string]int) *WarmPool {
+	return &WarmPool{
+		client:    c,
+		logger:    logger.WithName("warm-pool"),
+		namespace: namespace,
+		sizes:     sizes,
+		trigger:   make(chan struct{}, 1),
+	}
+}
+
+// ParseWarmPool 解析预热池的配置, 格式为 image=size,image=size
+func ParseWarmPool(s string) (map[string]int, error) {
+	sizes := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		// 镜像中可能包含端口号, 因此从最后一个=分割
+		i := strings.LastIndexByte(item, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid warm pool item %q, expected image=size", item)
+		}
+		size, err := strconv.Atoi(item[i+1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid warm pool size %q", item)
+		}
+		sizes[item[:i]] = size
+	}
+
+	return sizes, nil
+}
+
+// Start 定时补充预热Pod, 有预热Pod被认领时立即补充
+func (p *WarmPool) Start(ctx context.Context) error {
+	ticker := time.NewTicker(warmPoolInterval)
+	defer ticker.Stop()
+
+	p.reconcile(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-p.trigger:
+		}
+		p.reconcile(ctx)
+	}
+}
+
+// Claim 认领一个处于Ready状态的预热Pod, 没有可用的预热Pod时返回nil
+// 工作空间的Pod创建成功后调用Release删除预热Pod, 创建失败时调用Unclaim将预热Pod放回预热池
+func (p *WarmPool) Claim(ctx context.Context, image, workspace string) *v1.Pod {
+	if p == nil || !p.pooled(image) || p.prePulled(ctx)[image] {
+		return nil
+	}
+
+	pods, err := p.listPods(ctx, image)
+	if err != nil {
+		p.logger.Error(err, "list warm pods")
+		return nil
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if !isWarmPodReady(pod) {
+			continue
+		}
+
+		// 使用乐观锁添加标签, 保证一个预热Pod只会被一个工作空间认领
+		pod.Labels[LabelWarmClaimed] = workspace
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, AnnotationWarmClaimedAt, strconv.FormatInt(time.Now().Unix(), 10))
+		if err := p.client.Update(ctx, pod); err != nil {
+			if !errors.IsConflict(err) && !errors.IsNotFound(err) {
+				p.logger.Error(err, "claim warm pod", "name", pod.Name)
+			}
+			continue
+		}
+		p.logger.Info("warm pod claimed", "name", pod.Name, "node", pod.Spec.NodeName, "workspace", workspace)
+
+		return pod
+	}
+
+	return nil
+}
+
+// Release 删除被认领的预热Pod, 由预热池重新补充
+func (p *WarmPool) Release(ctx context.Context, pod *v1.Pod) {
+	if p == nil || pod == nil {
+		return
+	}
+
+	p.deletePod(ctx, pod)
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Unclaim 移除预热Pod的认领标签, 使其可以被再次认领
+func (p *WarmPool) Unclaim(ctx context.Context, pod *v1.Pod) {
+	if p == nil || pod == nil {
+		return
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	delete(pod.Labels, LabelWarmClaimed)
+	delete(pod.Annotations, AnnotationWarmClaimedAt)
+	if err := p.client.Patch(ctx, pod, patch); err != nil && !errors.IsNotFound(err) {
+		p.logger.Error(err, "unclaim warm pod", "name", pod.Name)
+	}
+}
+
+func (p *WarmPool) pooled(image string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sizes[image] > 0
+}
+
+// 为每个镜像创建或删除预热Pod, 使得未被认领的预热Pod的数量与配置一致
+func (p *WarmPool) reconcile(ctx context.Context) {
+	p.mu.Lock()
+	sizes := make(map[string]int, len(p.sizes))
+	for image, size := range p.sizes {
+		sizes[image] = size
+	}
+	p.mu.Unlock()
+	for image := range p.prePulled(ctx) {
+		delete(sizes, image)
+	}
+
+	// 删除已经不在预热池中的镜像的预热Pod
+	var all v1.PodList
+	if err := p.client.List(ctx, &all, client.InNamespace(p.namespace), client.HasLabels{LabelWarmPod}); err != nil {
+		p.logger.Error(err, "list warm pods")
+		return
+	}
+	hashes := make(map[string]bool, len(sizes))
+	for image := range sizes {
+		hashes[warmImageHash(image)] = true
+	}
+	// 同时删除认领后长时间没有被释放的预热Pod, 例如认领后控制器重启
+	for i := range all.Items {
+		pod := &all.Items[i]
+		if pod.DeletionTimestamp == nil && (!hashes[pod.Labels[LabelWarmPod]] || claimExpired(pod)) {
+			p.deletePod(ctx, pod)
+		}
+	}
+
+	for image, size := range sizes {
+		pods, err := p.listPods(ctx, image)
+		if err != nil {
+			p.logger.Error(err, "list warm pods", "image", image)
+			continue
+		}
+
+		for i := len(pods); i < size; i++ {
+			if err := p.client.Create(ctx, p.constructPod(image)); err != nil {
+				p.logger.Error(err, "create warm pod", "image", image)
+				break
+			}
+		}
+		// 优先删除还未Ready的预热Pod
+		sort.SliceStable(pods, func(i, j int) bool {
+			return !isWarmPodReady(&pods[i]) && isWarmPodReady(&pods[j])
+		})
+		for i := 0; i < len(pods)-size; i++ {
+			p.deletePod(ctx, &pods[i])
+		}
+	}
+}
+
+// 已经预拉取到所有节点上的镜像, 读取失败时视为没有预拉取的镜像
+func (p *WarmPool) prePulled(ctx context.Context) map[string]bool {
+	var cm v1.ConfigMap
+	if err := p.client.Get(ctx, client.ObjectKey{Name: mv1.PrePullName, Namespace: p.namespace}, &cm); err != nil {
+		if !errors.IsNotFound(err) {
+			p.logger.Error(err, "get pre-pull images")
+		}
+		return nil
+	}
+	images, err := prePullImages(&cm)
+	if err != nil {
+		p.logger.Error(err, "invalid pre-pull images")
+		return nil
+	}
+
+	pulled := make(map[string]bool, len(images))
+	for _, image := range images {
+		pulled[image.Image] = true
+	}
+
+	return pulled
+}
+
+// 列出某个镜像未被认领并且没有被删除的预热Pod
+func (p *WarmPool) listPods(ctx context.Context, image string) ([]v1.Pod, error) {
+	var list v1.PodList
+	err := p.client.List(ctx, &list, client.InNamespace(p.namespace),
+		client.MatchingLabels{LabelWarmPod: warmImageHash(image)})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := list.Items[:0]
+	for _, pod := range list.Items {
+		if _, claimed := pod.Labels[LabelWarmClaimed]; claimed || pod.DeletionTimestamp != nil {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+
+	return pods, nil
+}
+
+func (p *WarmPool) deletePod(ctx context.Context, pod *v1.Pod) {
+	if err := p.client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !errors.IsNotFound(err) {
+		p.logger.Error(err, "delete warm pod", "name", pod.Name)
+	}
+}
+
+// 认领的时间超过了warmClaimTimeout
+func claimExpired(pod *v1.Pod) bool {
+	if _, claimed := pod.Labels[LabelWarmClaimed]; !claimed {
+		return false
+	}
+	at, err := strconv.ParseInt(pod.Annotations[AnnotationWarmClaimedAt], 10, 64)
+
+	return err != nil || time.Since(time.Unix(at, 0)) > warmClaimTimeout
+}
+
+// 构造预热Pod, 容器只是运行sleep, 不挂载存储卷
+// 模板镜像中不一定有shell和sleep命令, 使用init容器复制的静态sleep
+func (p *WarmPool) constructPod(image string) *v1.Pod {
+	hash := warmImageHash(image)
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "warm-" + hash + "-",
+			Namespace:    p.namespace,
+			Annotations: map[string]string{
+				"image": image,
+			},
+			Labels: map[string]string{
+				"app":        warmPodAppLabel,
+				LabelWarmPod: hash,
+			},
+		},
+		Spec: v1.PodSpec{
+			TerminationGracePeriodSeconds: pointer.Int64(0),
+			AutomountServiceAccountToken:  pointer.Bool(false),
+			Containers: []v1.Container{
+				{
+					Name:            "warm",
+					Image:           image,
+					ImagePullPolicy: v1.PullIfNotPresent,
+				},
+			},
+		},
+	}
+
+	// 工作空间请求的资源由规格决定, 预热Pod只占用少量的资源
+	pod.Spec.Containers[0].Resources = sleepResources()
+	applyStaticSleep(&pod.Spec)
+
+	// 预热Pod与工作空间使用相同的安全配置
+	applySecurity(pod)
+
+	return pod
+}
+
+// 在任意镜像中运行sleep, 不依赖镜像中的shell和sleep命令
+// init容器将静态链接的busybox复制到emptyDir中, busybox以sleep为文件名执行时运行sleep命令
+func applyStaticSleep(spec *v1.PodSpec) {
+	mount := v1.VolumeMount{Name: sleepVolumeName, MountPath: sleepMountPath}
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name:         sleepVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	spec.InitContainers = append(spec.InitContainers, v1.Container{
+		Name:            "install-sleep",
+		Image:           SleepImage,
+		ImagePullPolicy: v1.PullIfNotPresent,
+		Command:         []string{"cp", "/bin/busybox", sleepMountPath + "/sleep"},
+		VolumeMounts:    []v1.VolumeMount{mount},
+		Resources:       sleepResources(),
+	})
+	for i := range spec.Containers {
+		spec.Containers[i].Command = []string{sleepMountPath + "/sleep", "2147483647"}
+		spec.Containers[i].Args = nil
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, mount)
+	}
+}
+
+// 只运行sleep的容器请求的资源
+func sleepResources() v1.ResourceRequirements {
+	return v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("10m"),
+			v1.ResourceMemory: resource.MustParse("16Mi"),
+		},
+	}
+}
+
+// 将工作空间的Pod优先调度到预热Pod所在的节点
+func preferNode(pod *v1.Pod, node string) {
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &v1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	affinity := pod.Spec.Affinity.NodeAffinity
+	affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		v1.PreferredSchedulingTerm{
+			Weight: 100,
+			Preference: v1.NodeSelectorTerm{
+				MatchFields: []v1.NodeSelectorRequirement{
+					{
+						Key:      "metadata.name",
+						Operator: v1.NodeSelectorOpIn,
+						Values:   []string{node},
+					},
+				},
+			},
+		})
+	pod.Annotations[AnnotationWarmNode] = node
+}
+
+func isWarmPodReady(pod *v1.Pod) bool {
+	if pod.Spec.NodeName == "" || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func warmImageHash(image string) string {
+	return devcontainer.Hash([]byte(image))
+}
@@ -19,19 +19,23 @@ package controllers
 import (
 	"context"
 	"path/filepath"
-	"strings"
+	"strconv"
 	"time"
 
 	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/lifecycle"
+	"github.com/mangohow/cloud-ide/pkg/notifier"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -47,13 +51,23 @@ const (
 // WorkSpaceReconciler reconciles a WorkSpace object
 type WorkSpaceReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	warmPool *WarmPool
+	executor PodExecutor
+	notifier notifier.Notifier
+	recorder record.EventRecorder
 }
 
-func NewWorkSpaceReconciler(c client.Client, scheme *runtime.Scheme) *WorkSpaceReconciler {
+// NewWorkSpaceReconciler warmPool为nil时不使用预热Pod, executor为nil时不读取仓库中的devcontainer.json
+// notifier为nil时停止工作空间前不通知IDE
+func NewWorkSpaceReconciler(c client.Client, scheme *runtime.Scheme, warmPool *WarmPool, executor PodExecutor, ntf notifier.Notifier, recorder record.EventRecorder) *WorkSpaceReconciler {
 	return &WorkSpaceReconciler{
-		Client: c,
-		Scheme: scheme,
+		Client:   c,
+		Scheme:   scheme,
+		warmPool: warmPool,
+		executor: executor,
+		notifier: ntf,
+		recorder: recorder,
 	}
 }
 
@@ -61,7 +75,10 @@ func NewWorkSpaceReconciler(c client.Client, scheme *runtime.Scheme) *WorkSpaceR
 // +kubebuilder:rbac:groups=cloud-ide.mangohow.com,resources=workspaces/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cloud-ide.mangohow.com,resources=workspaces/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=pod,verbs=get;list;watch;create;delete
-// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -81,7 +98,7 @@ func (r *WorkSpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// case 1、没有找到Workspace,说明WorkSpace被删除了,删除对应的Pod和PVC即可
 	if err != nil {
 		if errors.IsNotFound(err) {
-			if err := r.deletePod(ctx, req.NamespacedName); err != nil {
+			if _, err := r.deletePod(ctx, req.NamespacedName); err != nil {
 				lgr.Error(err, "delete pod")
 				return ctrl.Result{Requeue: true}, err
 			}
@@ -97,31 +114,101 @@ func (r *WorkSpaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	// 工作空间正在被删除, 根据保留策略处理工作空间的数据
+	if ws.DeletionTimestamp != nil {
+		result, err := r.finalize(ctx, &ws)
+		if err != nil {
+			lgr.Error(err, "finalize workspace")
+		}
+		return result, err
+	}
+	// 添加finalizer, 保证删除工作空间时可以处理工作空间的数据
+	if !controllerutil.ContainsFinalizer(&ws, WorkspaceFinalizer) {
+		controllerutil.AddFinalizer(&ws, WorkspaceFinalizer)
+		if err := r.Client.Update(ctx, &ws); err != nil {
+			lgr.Error(err, "add finalizer")
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	// 旧版本的默认状态为Created, 转换为Pending之后由状态机校验状态转换
+	if phase := lifecycle.Phase(&ws); ws.Status.Phase != phase {
+		ws.Status.Phase = phase
+		if err := r.Status().Update(ctx, &ws); err != nil {
+			lgr.Error(err, "convert workspace phase")
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
 	// 2.找到了WorkSpace,根据WorkSpace的Operation字段判断要进行的操作
 	switch ws.Spec.Command {
 	// case2: 启动WorkSpace,检查PVC是否存在,如果不存在则创建
 	case mv1.WorkSpaceStart:
+		// 到达计划的停止时间后将操作修改为Stop, 更新后会重新触发Reconcile
+		if ws.Spec.ScheduledStop != nil && !ws.Spec.ScheduledStop.After(time.Now()) {
+			ws.Spec.Command = mv1.WorkSpaceStop
+			ws.Spec.ScheduledStop = nil
+			if err := r.Client.Update(ctx, &ws); err != nil {
+				lgr.Error(err, "stop scheduled workspace")
+				return ctrl.Result{Requeue: true}, err
+			}
+
+			return ctrl.Result{}, nil
+		}
+
+		// 启动过程中的调谐属于启动请求的trace
+		ctx, span := workspaceSpan(ctx, &ws, "WorkSpaceReconciler.start")
+		defer span.End()
+
 		// 检查PVC是否存在,不存在则创建
-		err = r.createPVC(ctx, &ws, req.NamespacedName)
+		stepCtx, step := childSpan(ctx, "createPVC")
+		err = r.createPVC(stepCtx, &ws, req.NamespacedName)
+		endSpan(step, err)
 		if err != nil {
 			lgr.Error(err, "create pvc")
+			r.recorder.Eventf(&ws, v1.EventTypeWarning, EventPVCCreateFailed, "create volume: %v", err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		// 创建网络策略, 隔离不同用户的工作空间
+		stepCtx, step = childSpan(ctx, "createNetworkPolicy")
+		err = r.createNetworkPolicy(stepCtx, &ws)
+		endSpan(step, err)
+		if err != nil {
+			lgr.Error(err, "create network policy")
 			return ctrl.Result{Requeue: true}, err
 		}
 		// 创建Pod
-		err = r.createPod(ctx, &ws, req.NamespacedName)
+		stepCtx, step = childSpan(ctx, "createPod")
+		result, err := r.createPod(stepCtx, &ws, req.NamespacedName)
+		endSpan(step, err)
 		if err != nil {
 			lgr.Error(err, "create pod")
+			r.recorder.Eventf(&ws, v1.EventTypeWarning, EventPodCreateFailed, "create pod: %v", err)
 			return ctrl.Result{Requeue: true}, err
 		}
 
-	// case3: 停止WorkSpace,删除Pod
+		// 等待计划的停止时间到达
+		if ws.Spec.ScheduledStop != nil {
+			remaining := time.Until(ws.Spec.ScheduledStop.Time)
+			if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+				result.RequeueAfter = remaining
+			}
+		}
+
+		return result, nil
+
+	// case3: 停止WorkSpace,先通知IDE保存文件,宽限时间结束后删除Pod
 	case mv1.WorkSpaceStop:
-		// 删除Pod
-		err = r.deletePod(ctx, req.NamespacedName)
+		result, deleted, err := r.stopPod(ctx, req.NamespacedName)
 		if err != nil {
-			lgr.Error(err, "delete pod")
+			lgr.Error(err, "stop pod")
 			return ctrl.Result{Requeue: true}, err
 		}
+		if deleted {
+			r.recorder.Eventf(&ws, v1.EventTypeNormal, EventPodDeleted, "deleted pod %s to stop the workspace", req.Name)
+		}
+
+		return result, nil
 	}
 
 	return ctrl.Result{}, nil
@@ -147,44 +234,105 @@ func (r *WorkSpaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
-func (r *WorkSpaceReconciler) createPod(ctx context.Context, space *mv1.WorkSpace, key client.ObjectKey) error {
+func (r *WorkSpaceReconciler) createPod(ctx context.Context, space *mv1.WorkSpace, key client.ObjectKey) (ctrl.Result, error) {
 	// 1.检查Pod是否存在
 	exist, err := r.checkPodExist(ctx, key)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
-	// Pod已存在,直接返回
+	// Pod已存在,检查Pod是否与工作空间的配置一致,如果不一致则删除Pod,等待Pod删除后重新创建
 	if exist {
-		return nil
+		// 在停止的宽限时间内重新启动, 撤销之前的停止通知
+		if err := r.cancelStopNotice(ctx, key); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.checkPodDrift(ctx, space, key)
 	}
 
-	// 2.创建Pod
+	// 2.读取仓库中的devcontainer.json, 读取完成之前等待
+	resolved, err := r.resolveDevContainer(ctx, space)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !resolved {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
+	// 3.创建Pod
 	pod := r.constructPod(space)
 
 	// 设置控制器
 	if err = controllerutil.SetControllerReference(space, pod, r.Scheme); err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	ctx, cancelFunc := context.WithTimeout(ctx, time.Second*30)
 	defer cancelFunc()
+
+	// 认领一个相同镜像的预热Pod, 优先调度到其所在的节点
+	var node string
+	warm := r.warmPool.Claim(ctx, pod.Spec.Containers[0].Image, space.Name)
+	if warm != nil {
+		node = warm.Spec.NodeName
+		preferNode(pod, node)
+	}
+
 	err = r.Client.Create(ctx, pod)
 	if err != nil {
+		// 创建失败时将预热Pod放回预热池
+		r.warmPool.Unclaim(ctx, warm)
 		// 如果Pod已经存在,直接返回
 		if errors.IsAlreadyExists(err) {
-			return nil
+			return ctrl.Result{}, nil
 		}
 
-		return err
+		return ctrl.Result{}, err
 	}
+	// 工作空间的Pod创建成功后删除预热Pod, 由预热池重新补充
+	r.warmPool.Release(ctx, warm)
 
-	return nil
+	if node != "" {
+		r.recorder.Eventf(space, v1.EventTypeNormal, EventPodCreated, "created pod %s, preferring node %s where the image is cached", pod.Name, node)
+	} else {
+		r.recorder.Eventf(space, v1.EventTypeNormal, EventPodCreated, "created pod %s", pod.Name)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// 检查Pod与WorkSpace中记录的配置是否一致, 不一致时删除Pod
+// Pod在宽限时间内删除, IDE可以保存未保存的文件, Pod删除后会触发Reconcile重新创建
+func (r *WorkSpaceReconciler) checkPodDrift(ctx context.Context, space *mv1.WorkSpace, key client.ObjectKey) (ctrl.Result, error) {
+	pod := &v1.Pod{}
+	if err := r.Client.Get(ctx, key, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	reason := podDrift(space, pod)
+	if reason == "" {
+		return ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).Info("workspace changed, recreate pod", "name", key.Name, "reason", reason)
+	deleted, err := r.deletePod(ctx, key)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if deleted {
+		r.recorder.Eventf(space, v1.EventTypeNormal, EventPodRecreating, "%s, recreate pod %s", reason, key.Name)
+	}
+
+	// 重新入队, 防止错过Pod的删除事件
+	return ctrl.Result{RequeueAfter: time.Second * 2}, nil
 }
 
 // 构造一个Pod对象
 func (r *WorkSpaceReconciler) constructPod(space *mv1.WorkSpace) *v1.Pod {
-	volumeName := "volume-user-workspace"
+	volumeName := workspaceVolumeName
 	workspaceDir := filepath.Join(space.Spec.MountPath, "/workspace")
 	pod := &v1.Pod{
 		TypeMeta: metav1.TypeMeta{
@@ -195,24 +343,20 @@ func (r *WorkSpaceReconciler) constructPod(space *mv1.WorkSpace) *v1.Pod {
 			Name:      space.Name,
 			Namespace: space.Namespace,
 			Annotations: map[string]string{
-				"sid": space.Spec.SID,
-				"uid": space.Spec.UID,
+				"sid":              space.Spec.SID,
+				"uid":              space.Spec.UID,
+				AnnotationSpecHash: specHash(space),
 			},
 			Labels: map[string]string{
-				"app": "cloud-ide",
+				"app":          workspaceAppLabel,
+				LabelWorkspace: space.Name,
 			},
 		},
 		Spec: v1.PodSpec{
+			// IDE在删除Pod之前的宽限时间内保存文件, preStop钩子在删除Pod之后的宽限时间内执行完成
+			TerminationGracePeriodSeconds: pointer.Int64(int64(StopGracePeriod / time.Second)),
 			Volumes: []v1.Volume{
-				{
-					Name: volumeName,
-					VolumeSource: v1.VolumeSource{
-						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
-							ClaimName: space.Name,
-							ReadOnly:  false,
-						},
-					},
-				},
+				workspaceVolume(space),
 			},
 			Containers: []v1.Container{
 				{
@@ -238,64 +382,94 @@ func (r *WorkSpaceReconciler) constructPod(space *mv1.WorkSpace) *v1.Pod {
 							Value: workspaceDir,
 						},
 					},
+					// 用户设置的环境变量保存在Secret中, 没有设置环境变量时Secret不存在
+					EnvFrom: []v1.EnvFromSource{
+						{
+							SecretRef: &v1.SecretEnvSource{
+								LocalObjectReference: v1.LocalObjectReference{Name: mv1.EnvSecretName(space.Name)},
+								Optional:             pointer.Bool(true),
+							},
+						},
+					},
 				},
 			},
 		},
 	}
 
-	// 设置资源限制
-	if Mode == ModeRelease {
-		// 最小需求CPU2核、内存1Gi == 1 * 2^10
-		pod.Spec.Containers[0].Resources = v1.ResourceRequirements{
-			Requests: map[v1.ResourceName]resource.Quantity{
-				v1.ResourceCPU:    resource.MustParse("2"),
-				v1.ResourceMemory: resource.MustParse("1Gi"),
-			},
-			Limits: map[v1.ResourceName]resource.Quantity{
-				v1.ResourceCPU:    resource.MustParse(space.Spec.Cpu),
-				v1.ResourceMemory: resource.MustParse(space.Spec.Memory),
-			},
+	// 用户自定义模板使用私有镜像时，需要指定拉取镜像的Secret
+	if space.Spec.ImagePullSecret != "" {
+		pod.Spec.ImagePullSecrets = []v1.LocalObjectReference{
+			{Name: space.Spec.ImagePullSecret},
 		}
 	}
 
-	if space.Spec.GitRepository == "" {
-		return pod
-	}
+	// 根据规格设置资源的请求和限制
+	pod.Spec.Containers[0].Resources = workspaceResources(space)
+
+	// 根据规格的调度约束选择节点
+	applyScheduling(pod, space.Spec.Scheduling)
 
 	// 如果设置了git仓库，则通过init容器来clone
-	idx := strings.LastIndexByte(space.Spec.GitRepository, '/') + 1
-
-	localPath := filepath.Join(workspaceDir, strings.TrimSuffix(space.Spec.GitRepository[idx:], ".git"))
-	pod.Spec.InitContainers = []v1.Container{
-		{
-			Name:            "git-cloner",
-			Image:           GitClonerName,
-			WorkingDir:      space.Spec.MountPath,
-			ImagePullPolicy: v1.PullIfNotPresent,
-			// 容器挂载存储卷
-			VolumeMounts: []v1.VolumeMount{
-				{
-					Name:      volumeName,
-					ReadOnly:  false,
-					MountPath: space.Spec.MountPath,
-				},
-			},
-			Env: []v1.EnvVar{
-				{
-					Name:  "REPO_URL",
-					Value: space.Spec.GitRepository,
-				},
-				{
-					Name:  "LOCAL_PATH",
-					Value: localPath,
-				},
+	if repositories := workspaceRepositories(space); len(repositories) > 0 || space.Spec.Dotfiles != nil {
+		// 应用从git仓库中读取的devcontainer.json
+		if space.Status.DevContainer != nil {
+			applyDevContainer(pod, space.Status.DevContainer)
+		}
+		applyGitCloners(pod, space, volumeName, workspaceDir, repositories)
+	}
+
+	// 生命周期钩子在git仓库克隆完成后执行
+	applyHooks(pod, space)
+
+	// IDE会话通过网关获取工作空间的通知, 例如工作空间即将停止
+	if r.notifier != nil {
+		url, token := r.notifier.NoticeSource(space.Spec.SID)
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env,
+			v1.EnvVar{Name: NoticeUrlEnv, Value: url},
+			v1.EnvVar{Name: NoticeTokenEnv, Value: token})
+	}
+
+	// 安全配置需要应用到所有的容器中
+	applySecurity(pod)
+
+	return pod
+}
+
+// 工作空间的存储卷, PVC与工作空间同名
+func workspaceVolume(space *mv1.WorkSpace) v1.Volume {
+	return v1.Volume{
+		Name: workspaceVolumeName,
+		VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: space.Name,
+				ReadOnly:  false,
 			},
 		},
 	}
-	// 设置环境变量，code-server打开时使用该路径
-	pod.Spec.Containers[0].Env[0].Value = localPath
+}
 
-	return pod
+// 工作空间容器的资源限制与规格相同, 请求的资源为限制除以超售比例
+func workspaceResources(space *mv1.WorkSpace) v1.ResourceRequirements {
+	res := v1.ResourceRequirements{
+		Requests: v1.ResourceList{},
+		Limits:   v1.ResourceList{},
+	}
+	if cpu, err := resource.ParseQuantity(space.Spec.Cpu); err == nil && cpu.Sign() > 0 {
+		res.Limits[v1.ResourceCPU] = cpu
+		res.Requests[v1.ResourceCPU] = *resource.NewMilliQuantity(int64(float64(cpu.MilliValue())/CpuOvercommitRatio), resource.DecimalSI)
+	}
+	if memory, err := resource.ParseQuantity(space.Spec.Memory); err == nil && memory.Sign() > 0 {
+		res.Limits[v1.ResourceMemory] = memory
+		res.Requests[v1.ResourceMemory] = *resource.NewQuantity(int64(float64(memory.Value())/MemoryOvercommitRatio), resource.BinarySI)
+	}
+	// 只设置临时存储的限制时, 请求与限制相同
+	if EphemeralStorageLimit != "" {
+		if storage, err := resource.ParseQuantity(EphemeralStorageLimit); err == nil && storage.Sign() > 0 {
+			res.Limits[v1.ResourceEphemeralStorage] = storage
+		}
+	}
+
+	return res
 }
 
 func (r *WorkSpaceReconciler) createPVC(ctx context.Context, space *mv1.WorkSpace, key client.ObjectKey) error {
@@ -307,9 +481,9 @@ func (r *WorkSpaceReconciler) createPVC(ctx context.Context, space *mv1.WorkSpac
 		return err
 	}
 
-	// PVC已经存在,无需创建
+	// PVC已经存在,无需创建, 如果是之前删除的同名工作空间保留的PVC则恢复
 	if exist {
-		return nil
+		return r.restorePVC(ctx, space)
 	}
 
 	// 2.PVC不存在,创建PVC
@@ -318,6 +492,20 @@ func (r *WorkSpaceReconciler) createPVC(ctx context.Context, space *mv1.WorkSpac
 		lgr.Error(err, "construct pvc")
 		return err
 	}
+	// 之前删除的同名工作空间保留了快照时, 从快照中恢复数据
+	snapshot, err := r.findSnapshot(ctx, space)
+	if err != nil {
+		lgr.Error(err, "find volume snapshot")
+		return err
+	}
+	if snapshot != "" {
+		lgr.Info("restore pvc from volume snapshot", "name", pvc.Name, "snapshot", snapshot)
+		pvc.Spec.DataSource = &v1.TypedLocalObjectReference{
+			APIGroup: pointer.String(volumeSnapshotGVK.Group),
+			Kind:     volumeSnapshotGVK.Kind,
+			Name:     snapshot,
+		}
+	}
 
 	// 设置了OwnerReference之后,PVC的状态发生变化,也会触发Reconcile方法
 	// 但是对于PVC来说,我们不希望它触发这个方法,因此我们可以使用过滤器来进行过滤
@@ -336,6 +524,12 @@ func (r *WorkSpaceReconciler) createPVC(ctx context.Context, space *mv1.WorkSpac
 		return err
 	}
 
+	if snapshot != "" {
+		r.recorder.Eventf(space, v1.EventTypeNormal, EventPVCCreated, "created volume %s of %s from snapshot %s", pvc.Name, space.Spec.Storage, snapshot)
+	} else {
+		r.recorder.Eventf(space, v1.EventTypeNormal, EventPVCCreated, "created volume %s of %s", pvc.Name, space.Spec.Storage)
+	}
+
 	return nil
 }
 
@@ -390,22 +584,24 @@ func (r *WorkSpaceReconciler) checkPodExist(ctx context.Context, key client.Obje
 	return true, nil
 }
 
-func (r *WorkSpaceReconciler) deletePod(ctx context.Context, key client.ObjectKey) error {
+// 删除Pod, 返回是否发起了删除, Pod不存在或者已经在删除中时返回false
+func (r *WorkSpaceReconciler) deletePod(ctx context.Context, key client.ObjectKey) (bool, error) {
 	lgr := log.FromContext(ctx)
 
-	exist, err := r.checkPodExist(ctx, key)
+	pod := &v1.Pod{}
+	err := r.Client.Get(ctx, key, pod)
 	if err != nil {
-		return err
-	}
+		// Pod不存在,直接返回
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
 
-	// Pod不存在,直接返回
-	if !exist {
-		return nil
+		lgr.Error(err, "get pod")
+		return false, err
+	}
+	if pod.DeletionTimestamp != nil {
+		return false, nil
 	}
-
-	pod := &v1.Pod{}
-	pod.Name = key.Name
-	pod.Namespace = key.Namespace
 
 	ctx, cancelFunc := context.WithTimeout(ctx, time.Second*30)
 	defer cancelFunc()
@@ -413,12 +609,82 @@ func (r *WorkSpaceReconciler) deletePod(ctx context.Context, key client.ObjectKe
 	err = r.Client.Delete(ctx, pod)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return nil
+			return false, nil
 		}
 
 		lgr.Error(err, "delete pod")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// 停止工作空间的Pod, 运行中的Pod先通过网关通知IDE工作空间即将停止, 宽限时间结束后再删除
+// 没有运行中的IDE会话时直接删除
+func (r *WorkSpaceReconciler) stopPod(ctx context.Context, key client.ObjectKey) (ctrl.Result, bool, error) {
+	pod := &v1.Pod{}
+	if err := r.Client.Get(ctx, key, pod); err != nil {
+		return ctrl.Result{}, false, client.IgnoreNotFound(err)
+	}
+	if pod.DeletionTimestamp != nil {
+		return ctrl.Result{}, false, nil
+	}
+
+	if r.notifier != nil && pod.Status.Phase == v1.PodRunning && StopGracePeriod >= time.Second {
+		deadline, err := r.noticeStop(ctx, pod)
+		if err != nil {
+			return ctrl.Result{}, false, err
+		}
+		if remaining := time.Until(deadline); remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, false, nil
+		}
+	}
+
+	deleted, err := r.deletePod(ctx, key)
+	return ctrl.Result{}, deleted, err
+}
+
+// 通知IDE工作空间即将停止, 截止时间保存在Pod的注解中, 保证每次停止只通知一次
+// 已经通知过时返回之前的截止时间
+func (r *WorkSpaceReconciler) noticeStop(ctx context.Context, pod *v1.Pod) (time.Time, error) {
+	if sec, err := strconv.ParseInt(pod.Annotations[AnnotationStopDeadline], 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+
+	deadline := time.Now().Add(StopGracePeriod).Truncate(time.Second)
+	patch := client.MergeFrom(pod.DeepCopy())
+	metav1.SetMetaDataAnnotation(&pod.ObjectMeta, AnnotationStopDeadline, strconv.FormatInt(deadline.Unix(), 10))
+	if err := r.Client.Patch(ctx, pod, patch); err != nil {
+		return time.Time{}, err
+	}
+
+	r.notifier.Notice(pod.Annotations["sid"], &notifier.Notice{
+		Type:     notifier.NoticeStop,
+		Message:  "workspace is stopping, please save your files",
+		Deadline: deadline.Unix(),
+	})
+
+	return deadline, nil
+}
+
+// 移除Pod注解中的停止截止时间并撤销停止通知
+func (r *WorkSpaceReconciler) cancelStopNotice(ctx context.Context, key client.ObjectKey) error {
+	pod := &v1.Pod{}
+	if err := r.Client.Get(ctx, key, pod); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, ok := pod.Annotations[AnnotationStopDeadline]; !ok || pod.DeletionTimestamp != nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	delete(pod.Annotations, AnnotationStopDeadline)
+	if err := r.Client.Patch(ctx, pod, patch); err != nil {
 		return err
 	}
+	if r.notifier != nil {
+		r.notifier.Notice(pod.Annotations["sid"], nil)
+	}
 
 	return nil
 }
@@ -443,23 +709,27 @@ func (r *WorkSpaceReconciler) checkPVCExist(ctx context.Context, key client.Obje
 func (r *WorkSpaceReconciler) deletePVC(ctx context.Context, key client.ObjectKey) error {
 	lgr := log.FromContext(ctx)
 
-	exist, err := r.checkPVCExist(ctx, key)
+	pvc := &v1.PersistentVolumeClaim{}
+	err := r.Client.Get(ctx, key, pvc)
 	if err != nil {
+		// pvc不存在,无需再删除
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		lgr.Error(err, "get pvc")
 		return err
 	}
 
-	// pvc不存在,无需再删除
-	if !exist {
+	// 保留的PVC在过期后由RetentionCollector删除
+	if _, ok := pvc.Labels[LabelRetained]; ok {
 		return nil
 	}
 
-	pvc := &v1.PersistentVolumeClaim{}
-	pvc.Name = key.Name
-	pvc.Namespace = key.Namespace
-
 	ctx, cancelFunc := context.WithTimeout(ctx, time.Second*30)
 	defer cancelFunc()
-	err = r.Client.Delete(ctx, pvc)
+	// 缓存中的PVC可能还没有保留的标签, 使用资源版本作为删除的前提条件, 版本冲突时重试
+	err = r.Client.Delete(ctx, pvc, client.Preconditions{ResourceVersion: &pvc.ResourceVersion})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil
@@ -0,0 +1,688 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/notifier"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testWorkspace() *mv1.WorkSpace {
+	return &mv1.WorkSpace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-user01-space01", Namespace: "cloud-ide-ws"},
+		Spec: mv1.WorkSpaceSpec{
+			UID:       "user01",
+			SID:       "space01",
+			Cpu:       "2",
+			Memory:    "4Gi",
+			Storage:   "8Gi",
+			Image:     "code-server:go",
+			Port:      9999,
+			MountPath: "/root",
+			Command:   mv1.WorkSpaceStart,
+		},
+	}
+}
+
+// 设置超售比例和临时存储, 测试结束后恢复
+func setResourceConfig(t *testing.T, cpuRatio, memoryRatio float64, ephemeral string) {
+	oldCpu, oldMemory, oldEphemeral := CpuOvercommitRatio, MemoryOvercommitRatio, EphemeralStorageLimit
+	CpuOvercommitRatio, MemoryOvercommitRatio, EphemeralStorageLimit = cpuRatio, memoryRatio, ephemeral
+	t.Cleanup(func() {
+		CpuOvercommitRatio, MemoryOvercommitRatio, EphemeralStorageLimit = oldCpu, oldMemory, oldEphemeral
+	})
+}
+
+func TestConstructPodResources(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		cpu         string
+		memory      string
+		cpuRatio    float64
+		memoryRatio float64
+		ephemeral   string
+		requests    map[v1.ResourceName]string
+		limits      map[v1.ResourceName]string
+	}{
+		{
+			name: "no overcommit", mode: ModeRelease, cpu: "2", memory: "4Gi", cpuRatio: 1, memoryRatio: 1,
+			requests: map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "4Gi"},
+			limits:   map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "4Gi"},
+		},
+		{
+			name: "overcommit", mode: ModeRelease, cpu: "4", memory: "8Gi", cpuRatio: 4, memoryRatio: 2,
+			requests: map[v1.ResourceName]string{v1.ResourceCPU: "1", v1.ResourceMemory: "4Gi"},
+			limits:   map[v1.ResourceName]string{v1.ResourceCPU: "4", v1.ResourceMemory: "8Gi"},
+		},
+		{
+			name: "dev mode uses the same logic", mode: ModDev, cpu: "2", memory: "2Gi", cpuRatio: 8, memoryRatio: 4,
+			requests: map[v1.ResourceName]string{v1.ResourceCPU: "250m", v1.ResourceMemory: "512Mi"},
+			limits:   map[v1.ResourceName]string{v1.ResourceCPU: "2", v1.ResourceMemory: "2Gi"},
+		},
+		{
+			name: "ephemeral storage", mode: ModeRelease, cpu: "500m", memory: "1Gi", cpuRatio: 2, memoryRatio: 1, ephemeral: "10Gi",
+			requests: map[v1.ResourceName]string{v1.ResourceCPU: "250m", v1.ResourceMemory: "1Gi"},
+			limits:   map[v1.ResourceName]string{v1.ResourceCPU: "500m", v1.ResourceMemory: "1Gi", v1.ResourceEphemeralStorage: "10Gi"},
+		},
+	}
+
+	r := &WorkSpaceReconciler{}
+	for _, test := range tests {
+		oldMode := Mode
+		Mode = test.mode
+		setResourceConfig(t, test.cpuRatio, test.memoryRatio, test.ephemeral)
+
+		space := testWorkspace()
+		space.Spec.Cpu, space.Spec.Memory = test.cpu, test.memory
+		resources := r.constructPod(space).Spec.Containers[0].Resources
+		Mode = oldMode
+
+		checkResourceList(t, test.name+" requests", resources.Requests, test.requests)
+		checkResourceList(t, test.name+" limits", resources.Limits, test.limits)
+	}
+}
+
+func checkResourceList(t *testing.T, name string, got v1.ResourceList, want map[v1.ResourceName]string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %v, want %v", name, got, want)
+		return
+	}
+	for k, v := range want {
+		q, ok := got[k]
+		if !ok || q.Cmp(resource.MustParse(v)) != 0 {
+			t.Errorf("%s: %s got %s, want %s", name, k, q.String(), v)
+		}
+	}
+}
+
+func TestConstructPod(t *testing.T) {
+	setResourceConfig(t, 1, 1, "")
+
+	space := testWorkspace()
+	space.Spec.ImagePullSecret = "ips-user01-abc"
+	space.Spec.Scheduling = &mv1.WorkspaceScheduling{
+		NodeSelector:      map[string]string{"node-pool": "large"},
+		Tolerations:       []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "ide", Effect: v1.TaintEffectNoSchedule}},
+		PriorityClassName: "workspace-high",
+		TopologySpread:    []mv1.TopologySpread{{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 1}},
+	}
+
+	pod := (&WorkSpaceReconciler{}).constructPod(space)
+	if pod.Name != space.Name || pod.Namespace != space.Namespace || pod.Labels["app"] != workspaceAppLabel {
+		t.Errorf("metadata: %s/%s %v", pod.Namespace, pod.Name, pod.Labels)
+	}
+	if pod.Annotations["sid"] != "space01" || pod.Annotations["uid"] != "user01" {
+		t.Errorf("annotations: %v", pod.Annotations)
+	}
+
+	container := pod.Spec.Containers[0]
+	if container.Image != "code-server:go" || container.Ports[0].ContainerPort != 9999 {
+		t.Errorf("container: %s %v", container.Image, container.Ports)
+	}
+	if container.VolumeMounts[0].MountPath != "/root" || pod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName != space.Name {
+		t.Errorf("volume: %v %v", container.VolumeMounts, pod.Spec.Volumes)
+	}
+	if container.Env[0].Name != "OPEN_DIR" || container.Env[0].Value != "/root/workspace" {
+		t.Errorf("env: %v", container.Env)
+	}
+	if len(pod.Spec.ImagePullSecrets) != 1 || pod.Spec.ImagePullSecrets[0].Name != "ips-user01-abc" {
+		t.Errorf("image pull secrets: %v", pod.Spec.ImagePullSecrets)
+	}
+	if len(pod.Spec.InitContainers) != 0 || container.Lifecycle != nil {
+		t.Errorf("unexpected init containers or lifecycle: %v %v", pod.Spec.InitContainers, container.Lifecycle)
+	}
+
+	if pod.Spec.NodeSelector["node-pool"] != "large" || pod.Spec.PriorityClassName != "workspace-high" {
+		t.Errorf("scheduling: %v %s", pod.Spec.NodeSelector, pod.Spec.PriorityClassName)
+	}
+	if len(pod.Spec.Tolerations) != 1 || pod.Spec.Tolerations[0].Key != "dedicated" {
+		t.Errorf("tolerations: %v", pod.Spec.Tolerations)
+	}
+	if len(pod.Spec.TopologySpreadConstraints) != 1 ||
+		pod.Spec.TopologySpreadConstraints[0].WhenUnsatisfiable != v1.DoNotSchedule ||
+		pod.Spec.TopologySpreadConstraints[0].LabelSelector.MatchLabels["app"] != workspaceAppLabel {
+		t.Errorf("topology spread: %v", pod.Spec.TopologySpreadConstraints)
+	}
+}
+
+func TestConstructPodWithoutScheduling(t *testing.T) {
+	pod := (&WorkSpaceReconciler{}).constructPod(testWorkspace())
+	if pod.Spec.NodeSelector != nil || len(pod.Spec.Tolerations) != 0 || pod.Spec.PriorityClassName != "" ||
+		len(pod.Spec.TopologySpreadConstraints) != 0 || pod.Spec.Affinity != nil {
+		t.Errorf("unexpected scheduling: %+v", pod.Spec)
+	}
+}
+
+func TestConstructPodSecurity(t *testing.T) {
+	oldNonRoot, oldUser, oldDrop, oldSeccomp, oldRuntime := RunAsNonRoot, RunAsUser, DropCapabilities, SeccompProfile, RuntimeClassName
+	defer func() {
+		RunAsNonRoot, RunAsUser, DropCapabilities, SeccompProfile, RuntimeClassName = oldNonRoot, oldUser, oldDrop, oldSeccomp, oldRuntime
+	}()
+
+	seccomp, err := ParseSeccompProfile("Localhost/profiles/ide.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	RunAsNonRoot, RunAsUser, DropCapabilities, SeccompProfile, RuntimeClassName = true, 1000, []string{"ALL"}, seccomp, "gvisor"
+
+	space := testWorkspace()
+	space.Spec.Hooks = &mv1.LifecycleHooks{PostCreate: "make init"}
+	pod := (&WorkSpaceReconciler{}).constructPod(space)
+
+	if pod.Spec.AutomountServiceAccountToken == nil || *pod.Spec.AutomountServiceAccountToken {
+		t.Errorf("service account token should not be mounted")
+	}
+	if pod.Spec.RuntimeClassName == nil || *pod.Spec.RuntimeClassName != "gvisor" {
+		t.Errorf("runtime class: %v", pod.Spec.RuntimeClassName)
+	}
+	sc := pod.Spec.SecurityContext
+	if sc == nil || !*sc.RunAsNonRoot || *sc.RunAsUser != 1000 || *sc.FSGroup != 1000 ||
+		sc.SeccompProfile.Type != v1.SeccompProfileTypeLocalhost || *sc.SeccompProfile.LocalhostProfile != "profiles/ide.json" {
+		t.Errorf("pod security context: %+v", sc)
+	}
+
+	if len(pod.Spec.InitContainers) != 1 {
+		t.Fatalf("init containers: %v", pod.Spec.InitContainers)
+	}
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if c.SecurityContext == nil || *c.SecurityContext.AllowPrivilegeEscalation ||
+			len(c.SecurityContext.Capabilities.Drop) != 1 || c.SecurityContext.Capabilities.Drop[0] != "ALL" {
+			t.Errorf("container %s security context: %+v", c.Name, c.SecurityContext)
+		}
+	}
+}
+
+func TestParseSeccompProfile(t *testing.T) {
+	tests := []struct {
+		s       string
+		typ     v1.SeccompProfileType
+		invalid bool
+	}{
+		{s: ""},
+		{s: "RuntimeDefault", typ: v1.SeccompProfileTypeRuntimeDefault},
+		{s: "unconfined", typ: v1.SeccompProfileTypeUnconfined},
+		{s: "Localhost/ide.json", typ: v1.SeccompProfileTypeLocalhost},
+		{s: "Localhost/", invalid: true},
+		{s: "docker/default", invalid: true},
+	}
+
+	for _, test := range tests {
+		profile, err := ParseSeccompProfile(test.s)
+		if (err != nil) != test.invalid {
+			t.Errorf("%q: unexpected error %v", test.s, err)
+			continue
+		}
+		if test.typ == "" && profile != nil || test.typ != "" && (profile == nil || profile.Type != test.typ) {
+			t.Errorf("%q: got %v, want %s", test.s, profile, test.typ)
+		}
+	}
+}
+
+func TestConstructNetworkPolicy(t *testing.T) {
+	space := testWorkspace()
+	policy := constructNetworkPolicy(space)
+	pod := (&WorkSpaceReconciler{}).constructPod(space)
+
+	for k, v := range policy.Spec.PodSelector.MatchLabels {
+		if pod.Labels[k] != v {
+			t.Errorf("policy selector %s=%s does not match pod labels %v", k, v, pod.Labels)
+		}
+	}
+	if len(policy.Spec.Ingress) != 1 || policy.Spec.Ingress[0].Ports[0].Port.IntValue() != 9999 {
+		t.Fatalf("ingress: %+v", policy.Spec.Ingress)
+	}
+	from := policy.Spec.Ingress[0].From
+	if len(from) != 1 || from[0].NamespaceSelector.MatchLabels[v1.LabelMetadataName] != GatewayNamespace || from[0].PodSelector != nil {
+		t.Errorf("ingress from: %+v", from)
+	}
+}
+
+func TestNetworkPolicyEgress(t *testing.T) {
+	oldDefault, oldExcept := DefaultEgressCIDRs, EgressExceptCIDRs
+	defer func() {
+		DefaultEgressCIDRs, EgressExceptCIDRs = oldDefault, oldExcept
+	}()
+	DefaultEgressCIDRs = []string{"0.0.0.0/0"}
+	EgressExceptCIDRs = []string{"10.0.0.0/8", "192.168.0.0/16", "fc00::/7"}
+
+	// 没有配置白名单时使用默认的出站策略, 排除内部地址段
+	space := testWorkspace()
+	egress := constructNetworkPolicy(space).Spec.Egress
+	if len(egress) != 3 || len(egress[0].Ports) != 2 || egress[0].Ports[0].Port.IntValue() != 53 {
+		t.Fatalf("default egress: %+v", egress)
+	}
+	if egress[1].Ports[0].Port.IntValue() != 443 || egress[1].To[0].NamespaceSelector.MatchLabels[v1.LabelMetadataName] != GatewayNamespace {
+		t.Errorf("gateway egress: %+v", egress[1])
+	}
+	block := egress[2].To[0].IPBlock
+	if block.CIDR != "0.0.0.0/0" || len(block.Except) != 2 || block.Except[0] != "10.0.0.0/8" || block.Except[1] != "192.168.0.0/16" {
+		t.Errorf("default egress block: %+v", block)
+	}
+
+	// 白名单中直接指定的内部地址段不会被排除
+	space.Spec.Egress = &mv1.WorkspaceEgress{Rules: []mv1.EgressRule{
+		{CIDR: "10.1.0.0/16", Ports: []int32{443, 8443}},
+		{CIDR: "203.0.113.7/32"},
+	}}
+	egress = constructNetworkPolicy(space).Spec.Egress
+	if len(egress) != 4 {
+		t.Fatalf("allowlist egress: %+v", egress)
+	}
+	if block := egress[2].To[0].IPBlock; block.CIDR != "10.1.0.0/16" || len(block.Except) != 0 {
+		t.Errorf("internal allowlist block: %+v", block)
+	}
+	if ports := egress[2].Ports; len(ports) != 2 || *ports[0].Protocol != v1.ProtocolTCP || ports[1].Port.IntValue() != 8443 {
+		t.Errorf("allowlist ports: %+v", ports)
+	}
+	if egress[3].To[0].IPBlock.CIDR != "203.0.113.7/32" || len(egress[3].Ports) != 0 {
+		t.Errorf("allowlist rule: %+v", egress[3])
+	}
+
+	// 白名单为空时只允许访问DNS和gateway
+	space.Spec.Egress = &mv1.WorkspaceEgress{}
+	if egress = constructNetworkPolicy(space).Spec.Egress; len(egress) != 2 {
+		t.Errorf("empty allowlist egress: %+v", egress)
+	}
+}
+
+func TestVolumeSnapshotRetention(t *testing.T) {
+	space := testWorkspace()
+	space.UID = "0d5c7f1e-3a4b-4c5d-8e9f-0a1b2c3d4e5f"
+	until := metav1.NewTime(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+	space.Spec.Retention = &mv1.WorkspaceRetention{Policy: mv1.RetentionSnapshot, RetainUntil: &until}
+
+	snapshot := constructVolumeSnapshot(space)
+	if snapshot.GetName() != "ws-user01-space01-0d5c7f1e" {
+		t.Fatalf("snapshot name: %s", snapshot.GetName())
+	}
+	if source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName"); source != space.Name {
+		t.Fatalf("snapshot source: %s", source)
+	}
+	if snapshot.GetLabels()[LabelRetained] != space.Name {
+		t.Fatalf("snapshot labels: %v", snapshot.GetLabels())
+	}
+	if got := snapshot.GetAnnotations()[AnnotationRetainUntil]; got != "2024-05-01T08:00:00Z" {
+		t.Fatalf("retain until: %s", got)
+	}
+
+	if retentionExpired(snapshot, until.Add(-time.Second)) {
+		t.Fatal("snapshot should not expire before retain until")
+	}
+	if !retentionExpired(snapshot, until.Time) {
+		t.Fatal("snapshot should expire at retain until")
+	}
+	// 没有记录保留时间的数据视为已过期
+	if !retentionExpired(&v1.PersistentVolumeClaim{}, until.Time) {
+		t.Fatal("pvc without retain until should expire")
+	}
+}
+
+func TestPodDrift(t *testing.T) {
+	space := testWorkspace()
+	pod := (&WorkSpaceReconciler{}).constructPod(space)
+	if reason := podDrift(space, pod); reason != "" {
+		t.Fatalf("unexpected drift: %s", reason)
+	}
+
+	// dotfiles等只在启动时更新的配置不影响hash值
+	changed := space.DeepCopy()
+	changed.Spec.Dotfiles = &mv1.GitRepository{URL: "https://github.com/user01/dotfiles"}
+	if reason := podDrift(changed, pod); reason != "" {
+		t.Errorf("unexpected drift after dotfiles change: %s", reason)
+	}
+
+	changed = space.DeepCopy()
+	changed.Spec.Image = "code-server:go-1.21"
+	if reason := podDrift(changed, pod); reason != "spec changed" {
+		t.Errorf("image change: %q", reason)
+	}
+	changed = space.DeepCopy()
+	changed.Spec.Memory = "8Gi"
+	if reason := podDrift(changed, pod); reason != "spec changed" {
+		t.Errorf("memory change: %q", reason)
+	}
+
+	// 之前的版本创建的Pod没有hash值
+	legacy := pod.DeepCopy()
+	delete(legacy.Annotations, AnnotationSpecHash)
+	if reason := podDrift(changed, legacy); reason != "" {
+		t.Errorf("legacy pod: %q", reason)
+	}
+
+	changed = space.DeepCopy()
+	changed.Status.DevContainer = &mv1.DevContainerConfig{Hash: "abc"}
+	if reason := podDrift(changed, pod); reason != "devcontainer changed" {
+		t.Errorf("devcontainer change: %q", reason)
+	}
+
+	failed := pod.DeepCopy()
+	failed.Status.Phase = v1.PodFailed
+	if reason := podDrift(space, failed); reason != "pod Failed" {
+		t.Errorf("failed pod: %q", reason)
+	}
+}
+
+type fakeExecutor struct {
+	output   string
+	commit   string
+	commands [][]string
+}
+
+func (e *fakeExecutor) Exec(ctx context.Context, pod *v1.Pod, container string, command []string) ([]byte, error) {
+	e.commands = append(e.commands, command)
+	if command[0] == "git" {
+		return []byte(e.commit + "\n"), nil
+	}
+	return []byte(e.output), nil
+}
+
+func TestResolveDevContainer(t *testing.T) {
+	oldImages := DevContainerImages
+	DevContainerImages = []string{"golang:*"}
+	t.Cleanup(func() { DevContainerImages = oldImages })
+
+	scheme := runtime.NewScheme()
+	if err := mv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	space := testWorkspace()
+	space.Generation = 2
+	space.Spec.GitRepository = "https://github.com/user01/app.git"
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(space).Build()
+	executor := &fakeExecutor{output: `{"image": "golang:1.21", "containerEnv": {"CGO_ENABLED": "0"}}`, commit: "8f3a1c2"}
+	r := &WorkSpaceReconciler{Client: c, Scheme: scheme, executor: executor}
+	ctx := context.Background()
+
+	// 1.创建克隆仓库的临时Pod
+	if resolved, err := r.resolveDevContainer(ctx, space); err != nil || resolved {
+		t.Fatalf("resolved = %v, err = %v", resolved, err)
+	}
+	key := client.ObjectKey{Name: devContainerPodName(space.Name), Namespace: space.Namespace}
+	var pod v1.Pod
+	if err := c.Get(ctx, key, &pod); err != nil {
+		t.Fatal(err)
+	}
+	if len(pod.Spec.InitContainers) != 1 || pod.Labels[LabelWorkspace] != space.Name || pod.Labels["app"] == workspaceAppLabel {
+		t.Fatalf("unexpected pod %+v", pod)
+	}
+
+	// 2.克隆完成后从存储卷中读取devcontainer.json, 然后删除临时Pod
+	pod.Status.Phase = v1.PodRunning
+	if err := c.Update(ctx, &pod); err != nil {
+		t.Fatal(err)
+	}
+	if resolved, err := r.resolveDevContainer(ctx, space); err != nil || !resolved {
+		t.Fatalf("resolved = %v, err = %v", resolved, err)
+	}
+	if len(executor.commands) != 2 || len(executor.commands[0]) != 2 || executor.commands[0][1] != "/root/.cloud-ide/devcontainer.json" {
+		t.Errorf("commands = %v", executor.commands)
+	}
+	if space.Status.DevContainer == nil || space.Status.DevContainer.Image != "golang:1.21" {
+		t.Fatalf("devcontainer = %+v", space.Status.DevContainer)
+	}
+	if source := space.Status.DevContainerSource; source == nil || source.Commit != "8f3a1c2" || source.URL != space.Spec.GitRepository {
+		t.Fatalf("devcontainer source = %+v", source)
+	}
+	if err := c.Get(ctx, key, &pod); err == nil {
+		t.Error("devcontainer pod should be deleted")
+	}
+
+	// 3.工作空间Pod创建时就应用了devcontainer配置
+	workspacePod := r.constructPod(space)
+	if workspacePod.Spec.Containers[0].Image != "golang:1.21" || podDrift(space, workspacePod) != "" {
+		t.Errorf("devcontainer not applied: %+v", workspacePod.Spec.Containers[0])
+	}
+
+	// 同一次启动中不再重复读取
+	executor.commands = nil
+	if resolved, err := r.resolveDevContainer(ctx, space); err != nil || !resolved || executor.commands != nil {
+		t.Fatalf("resolved = %v, err = %v, commands = %v", resolved, err, executor.commands)
+	}
+
+	// 再次启动时仓库没有变化, 不创建临时Pod
+	space.Generation = 3
+	if resolved, err := r.resolveDevContainer(ctx, space); err != nil || !resolved || executor.commands != nil {
+		t.Fatalf("resolved = %v, err = %v, commands = %v", resolved, err, executor.commands)
+	}
+	if cond := meta.FindStatusCondition(space.Status.Conditions, mv1.ConditionDevContainer); cond == nil || cond.ObservedGeneration != 3 {
+		t.Errorf("condition = %+v", cond)
+	}
+	if err := c.Get(ctx, key, &pod); err == nil {
+		t.Error("devcontainer pod should not be created")
+	}
+
+	// 修改ref之后重新读取
+	space.Generation = 4
+	space.Spec.GitRef = "dev"
+	if resolved, err := r.resolveDevContainer(ctx, space); err != nil || resolved {
+		t.Fatalf("resolved = %v, err = %v", resolved, err)
+	}
+	if err := c.Get(ctx, key, &pod); err != nil {
+		t.Errorf("devcontainer pod should be created: %v", err)
+	}
+}
+
+type fakeNotifier struct {
+	notices []*notifier.Notice
+}
+
+func (n *fakeNotifier) Login(sid, endpoint string) {}
+
+func (n *fakeNotifier) Logout(sid string) {}
+
+func (n *fakeNotifier) Notify(sid string) {}
+
+func (n *fakeNotifier) Notice(sid string, notice *notifier.Notice) {
+	n.notices = append(n.notices, notice)
+}
+
+func (n *fakeNotifier) NoticeSource(sid string) (string, string) {
+	return "https://gateway/notice/" + sid, "token"
+}
+
+func TestStopPod(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	space := testWorkspace()
+	ntf := &fakeNotifier{}
+	r := &WorkSpaceReconciler{Scheme: scheme, notifier: ntf}
+	pod := r.constructPod(space)
+	pod.Status.Phase = v1.PodRunning
+	r.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(pod)
+
+	if env := pod.Spec.Containers[0].Env; env[len(env)-2].Name != NoticeUrlEnv || env[len(env)-1].Value != "token" {
+		t.Errorf("notice env: %+v", env)
+	}
+
+	// 1.先通知IDE, 宽限时间结束前不删除Pod
+	result, deleted, err := r.stopPod(ctx, key)
+	if err != nil || deleted || result.RequeueAfter <= 0 || result.RequeueAfter > StopGracePeriod {
+		t.Fatalf("result = %+v, deleted = %v, err = %v", result, deleted, err)
+	}
+	if len(ntf.notices) != 1 || ntf.notices[0].Type != notifier.NoticeStop {
+		t.Fatalf("notices = %+v", ntf.notices)
+	}
+
+	// 再次调谐时不重复通知
+	if _, deleted, err = r.stopPod(ctx, key); err != nil || deleted || len(ntf.notices) != 1 {
+		t.Fatalf("deleted = %v, err = %v, notices = %d", deleted, err, len(ntf.notices))
+	}
+
+	// 2.宽限时间结束后删除Pod
+	if err := r.Client.Get(ctx, key, pod); err != nil {
+		t.Fatal(err)
+	}
+	pod.Annotations[AnnotationStopDeadline] = strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	if err := r.Client.Update(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, deleted, err = r.stopPod(ctx, key); err != nil || !deleted || len(ntf.notices) != 1 {
+		t.Fatalf("deleted = %v, err = %v, notices = %d", deleted, err, len(ntf.notices))
+	}
+}
+
+func TestCancelStopNotice(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ntf := &fakeNotifier{}
+	r := &WorkSpaceReconciler{Scheme: scheme, notifier: ntf}
+	pod := r.constructPod(testWorkspace())
+	pod.Annotations[AnnotationStopDeadline] = strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	r.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).Build()
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(pod)
+
+	// 停止的宽限时间内重新启动, 撤销通知并移除截止时间
+	if err := r.cancelStopNotice(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(ctx, key, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := pod.Annotations[AnnotationStopDeadline]; ok || len(ntf.notices) != 1 || ntf.notices[0] != nil {
+		t.Errorf("annotations = %v, notices = %+v", pod.Annotations, ntf.notices)
+	}
+}
+
+func TestWarmPoolClaim(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	pool := NewWarmPool(c, logr.Discard(), "cloud-ide-ws", map[string]int{"code-server:go": 1})
+	ctx := context.Background()
+
+	// 预热Pod使用init容器复制的sleep, 不依赖镜像中的shell
+	pod := pool.constructPod("code-server:go")
+	if len(pod.Spec.InitContainers) != 1 || pod.Spec.Containers[0].Command[0] != sleepMountPath+"/sleep" {
+		t.Fatalf("unexpected warm pod %+v", pod.Spec)
+	}
+	pod.Name = "warm-01"
+	pod.Spec.NodeName = "node01"
+	pod.Status.Phase = v1.PodRunning
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	if err := c.Create(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+
+	// 工作空间的Pod创建失败时预热Pod可以被再次认领
+	warm := pool.Claim(ctx, "code-server:go", "ws-user01-space01")
+	if warm == nil || warm.Spec.NodeName != "node01" {
+		t.Fatalf("claimed %+v", warm)
+	}
+	if pool.Claim(ctx, "code-server:go", "ws-user02-space02") != nil {
+		t.Fatal("claimed warm pod should not be claimed again")
+	}
+	pool.Unclaim(ctx, warm)
+	warm = pool.Claim(ctx, "code-server:go", "ws-user02-space02")
+	if warm == nil {
+		t.Fatal("unclaimed warm pod should be claimed again")
+	}
+
+	// 工作空间的Pod创建成功后删除预热Pod
+	pool.Release(ctx, warm)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(warm), &v1.Pod{}); err == nil {
+		t.Error("released warm pod should be deleted")
+	}
+}
+
+func TestWarmPoolSkipsPrePulled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: mv1.PrePullName, Namespace: "cloud-ide-ws"},
+		Data:       map[string]string{mv1.PrePullImagesKey: `[{"image": "code-server:go"}]`},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+	pool := NewWarmPool(c, logr.Discard(), "cloud-ide-ws", map[string]int{"code-server:go": 1, "code-server:python": 1})
+	ctx := context.Background()
+
+	pod := pool.constructPod("code-server:go")
+	pod.Name = "warm-01"
+	pod.Status.Phase = v1.PodRunning
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	if err := c.Create(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+
+	// 预拉取的镜像已经在所有节点上, 不认领预热Pod, 已有的预热Pod被删除
+	if pool.Claim(ctx, "code-server:go", "ws-user01-space01") != nil {
+		t.Error("pre-pulled image should not be claimed")
+	}
+	pool.reconcile(ctx)
+	var pods v1.PodList
+	if err := c.List(ctx, &pods); err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 1 || pods.Items[0].Annotations["image"] != "code-server:python" {
+		t.Errorf("warm pods = %+v", pods.Items)
+	}
+}
+
+func TestPrePullTolerations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := mv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	dedicated := v1.Toleration{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "ide", Effect: v1.TaintEffectNoSchedule}
+	gpu := v1.Toleration{Key: "gpu", Operator: v1.TolerationOpExists}
+	space := func(name string, tolerations ...v1.Toleration) *mv1.WorkSpace {
+		return &mv1.WorkSpace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cloud-ide-ws"},
+			Spec:       mv1.WorkSpaceSpec{Scheduling: &mv1.WorkspaceScheduling{Tolerations: tolerations}},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(space("ws-1", gpu, dedicated), space("ws-2", dedicated), &mv1.WorkSpace{ObjectMeta: metav1.ObjectMeta{Name: "ws-3", Namespace: "cloud-ide-ws"}}).
+		Build()
+	r := NewImagePrePullReconciler(c, scheme)
+
+	// 相同的污点只容忍一次, 并且顺序与工作空间的顺序无关
+	tolerations, err := r.workspaceTolerations(context.Background(), "cloud-ide-ws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tolerations) != 2 || tolerations[0].Key != "dedicated" || tolerations[1].Key != "gpu" {
+		t.Fatalf("tolerations = %v", tolerations)
+	}
+
+	key := client.ObjectKey{Name: mv1.PrePullName, Namespace: "cloud-ide-ws"}
+	images := []mv1.PrePullImage{{Image: "code-server:go"}}
+	ds := constructPrePullDaemonSet(key, images, tolerations)
+	spec := ds.Spec.Template.Spec
+	if len(spec.Tolerations) != 2 || len(spec.InitContainers) != 1 || spec.Containers[0].Command[0] != sleepMountPath+"/sleep" {
+		t.Fatalf("unexpected daemonset %+v", spec)
+	}
+	if ds.Annotations[AnnotationPrePullHash] == constructPrePullDaemonSet(key, images, nil).Annotations[AnnotationPrePullHash] {
+		t.Error("hash should change with the tolerations")
+	}
+}
@@ -0,0 +1,249 @@
+package devcontainer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+)
+
+// 支持的devcontainer.json配置项
+var supportedKeys = map[string]struct{}{
+	"image":             {},
+	"containerEnv":      {},
+	"remoteEnv":         {},
+	"forwardPorts":      {},
+	"postCreateCommand": {},
+	"features":          {},
+}
+
+// 只用于描述的配置项, 直接忽略
+var ignoredKeys = map[string]struct{}{
+	"$schema":        {},
+	"name":           {},
+	"customizations": {},
+}
+
+// UnsupportedKeyError devcontainer.json中包含不支持的配置项
+type UnsupportedKeyError struct {
+	Keys []string
+}
+
+func (e *UnsupportedKeyError) Error() string {
+	return "unsupported devcontainer keys: " + strings.Join(e.Keys, ", ")
+}
+
+type devContainer struct {
+	Image             string                     `json:"image"`
+	ContainerEnv      map[string]string          `json:"containerEnv"`
+	RemoteEnv         map[string]string          `json:"remoteEnv"`
+	ForwardPorts      []json.RawMessage          `json:"forwardPorts"`
+	PostCreateCommand json.RawMessage            `json:"postCreateCommand"`
+	Features          map[string]json.RawMessage `json:"features"`
+}
+
+// Hash 计算devcontainer.json原始内容的hash值
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Parse 解析devcontainer.json, 支持注释和尾随逗号(JSONC)
+// allowedFeatures为允许使用的feature, 这些feature已经内置在工作空间镜像中
+// allowedImages为允许覆盖模板镜像的镜像, 由管理员配置, 为空时不允许指定image
+func Parse(data []byte, allowedFeatures, allowedImages []string) (*mv1.DevContainerConfig, error) {
+	content, err := Standardize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1.检查是否包含不支持的配置项
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return nil, fmt.Errorf("invalid devcontainer.json: %v", err)
+	}
+	var unsupported []string
+	for key := range keys {
+		if _, ok := supportedKeys[key]; ok {
+			continue
+		}
+		if _, ok := ignoredKeys[key]; ok {
+			continue
+		}
+		unsupported = append(unsupported, key)
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return nil, &UnsupportedKeyError{Keys: unsupported}
+	}
+
+	var dc devContainer
+	if err := json.Unmarshal(content, &dc); err != nil {
+		return nil, fmt.Errorf("invalid devcontainer.json: %v", err)
+	}
+
+	if dc.Image != "" && !ImageAllowed(dc.Image, allowedImages) {
+		return nil, fmt.Errorf("image %s is not allowed", dc.Image)
+	}
+
+	config := &mv1.DevContainerConfig{
+		Hash:         Hash(data),
+		Image:        dc.Image,
+		ContainerEnv: dc.ContainerEnv,
+		RemoteEnv:    dc.RemoteEnv,
+	}
+
+	// 2.解析端口, 只支持数字形式的端口
+	for _, raw := range dc.ForwardPorts {
+		port, err := strconv.ParseInt(string(raw), 10, 32)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("unsupported forwardPorts value %s, only port numbers are supported", raw)
+		}
+		config.ForwardPorts = append(config.ForwardPorts, int32(port))
+	}
+
+	// 3.解析postCreateCommand, 支持字符串和数组两种形式
+	if config.PostCreateCommand, err = parseCommand(dc.PostCreateCommand); err != nil {
+		return nil, err
+	}
+
+	// 4.检查features, 只允许使用白名单中的feature
+	allowed := make(map[string]struct{}, len(allowedFeatures))
+	for _, f := range allowedFeatures {
+		allowed[featureId(f)] = struct{}{}
+	}
+	for feature := range dc.Features {
+		if _, ok := allowed[featureId(feature)]; !ok {
+			return nil, fmt.Errorf("feature %s is not allowed", feature)
+		}
+		config.Features = append(config.Features, feature)
+	}
+	sort.Strings(config.Features)
+
+	return config, nil
+}
+
+func parseCommand(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		if str == "" {
+			return nil, nil
+		}
+		return []string{"/bin/sh", "-c", str}, nil
+	}
+
+	var args []string
+	if err := json.Unmarshal(raw, &args); err == nil {
+		return args, nil
+	}
+
+	return nil, fmt.Errorf("unsupported postCreateCommand, only string and array are supported")
+}
+
+// ImageAllowed 检查镜像是否在白名单中, 白名单中以*结尾的项匹配前缀, 例如 registry.example.com/devcontainers/*
+func ImageAllowed(image string, allowed []string) bool {
+	for _, pattern := range allowed {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(image, prefix) {
+				return true
+			}
+		} else if image == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 去掉feature的版本号, 例如ghcr.io/devcontainers/features/go:1 -> ghcr.io/devcontainers/features/go
+func featureId(feature string) string {
+	idx := strings.LastIndexByte(feature, ':')
+	if idx > strings.LastIndexByte(feature, '/') {
+		return feature[:idx]
+	}
+
+	return feature
+}
+
+// Standardize 将JSONC转换为标准的JSON, 去掉注释以及对象和数组中的尾随逗号
+func Standardize(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			buf.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				buf.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			buf.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				buf.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, fmt.Errorf("invalid devcontainer.json: unterminated comment")
+			}
+			i += end + 3
+		case c == ',':
+			// 尾随逗号, 下一个有效字符是 } 或 ]
+			if next := nextToken(data[i+1:]); next == '}' || next == ']' {
+				continue
+			}
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// 获取下一个非空白且不在注释中的字符
+func nextToken(data []byte) byte {
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0
+			}
+			i += end + 3
+		default:
+			return c
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,81 @@
+package devcontainer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := []byte(`{
+	// 工作空间镜像
+	"name": "go", /* 忽略 */
+	"image": "golang:1.21",
+	"containerEnv": {"GOPROXY": "https://goproxy.cn,direct",},
+	"remoteEnv": {"URL": "http://example.com//path"},
+	"forwardPorts": [8080, 9090,],
+	"postCreateCommand": "go mod download",
+	"features": {"ghcr.io/devcontainers/features/git:1": {}},
+}`)
+
+	config, err := Parse(data, []string{"ghcr.io/devcontainers/features/git"}, []string{"golang:*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Image != "golang:1.21" {
+		t.Errorf("image: %s", config.Image)
+	}
+	if config.ContainerEnv["GOPROXY"] != "https://goproxy.cn,direct" || config.RemoteEnv["URL"] != "http://example.com//path" {
+		t.Errorf("env: %v %v", config.ContainerEnv, config.RemoteEnv)
+	}
+	if !reflect.DeepEqual(config.ForwardPorts, []int32{8080, 9090}) {
+		t.Errorf("ports: %v", config.ForwardPorts)
+	}
+	if !reflect.DeepEqual(config.PostCreateCommand, []string{"/bin/sh", "-c", "go mod download"}) {
+		t.Errorf("postCreateCommand: %v", config.PostCreateCommand)
+	}
+	if config.Hash != Hash(data) {
+		t.Errorf("hash: %s", config.Hash)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unsupported key", `{"image": "golang", "build": {"dockerfile": "Dockerfile"}, "mounts": []}`},
+		{"feature not allowed", `{"features": {"ghcr.io/devcontainers/features/docker-in-docker:2": {}}}`},
+		{"port mapping", `{"forwardPorts": ["db:5432"]}`},
+		{"parallel commands", `{"postCreateCommand": {"a": "make", "b": "make test"}}`},
+		{"invalid json", `{"image": }`},
+		{"image not allowed", `{"image": "golang:1.21"}`},
+	}
+
+	for _, test := range tests {
+		if _, err := Parse([]byte(test.data), nil, nil); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+
+	_, err := Parse([]byte(`{"build": {}, "mounts": []}`), nil, nil)
+	if e, ok := err.(*UnsupportedKeyError); !ok || !reflect.DeepEqual(e.Keys, []string{"build", "mounts"}) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestImageAllowed(t *testing.T) {
+	allowed := []string{"registry.example.com/devcontainers/*", "golang:1.21"}
+	tests := map[string]bool{
+		"registry.example.com/devcontainers/go:1.21": true,
+		"golang:1.21":                        true,
+		"golang:1.22":                        false,
+		"registry.example.com/other/go:1.21": false,
+		"evil.com/registry.example.com/devcontainers/go": false,
+	}
+
+	for image, want := range tests {
+		if got := ImageAllowed(image, allowed); got != want {
+			t.Errorf("ImageAllowed(%s) = %v, want %v", image, got, want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package lifecycle
+
+import (
+	"fmt"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// 工作空间的生命周期状态机, PodReconciler和WorkSpaceService都通过它修改工作空间的状态
+//
+//	Pending  -> Starting -> Running -> Stopping -> Stopped -> Starting ...
+//	Pending  -> Stopping, 停止还没有启动的工作空间
+//	Stopping -> Starting, Pod删除中(状态为Stopping)时重新启动, 删除完成后创建新的Pod
+//	停止通知的宽限时间内Pod还没有删除, 状态仍为Running, 此时重新启动只是取消停止, 状态不变
+//	除Deleting之外的状态 -> Failed -> Starting(由控制器重建Pod)或者Stopping
+//	任意状态 -> Deleting, Deleting为终止状态
+//
+// Reconciler只能观察到Pod的最新状态, 可能错过中间状态, 由PodReconciler补全错过的状态, 状态机不允许跳过
+var transitions = map[mv1.WorkSpacePhase][]mv1.WorkSpacePhase{
+	mv1.WorkspacePhasePending: {
+		mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseFailed, mv1.WorkspacePhaseDeleting,
+	},
+	mv1.WorkspacePhaseStarting: {
+		mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseFailed, mv1.WorkspacePhaseDeleting,
+	},
+	mv1.WorkspacePhaseRunning: {
+		mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseFailed, mv1.WorkspacePhaseDeleting,
+	},
+	mv1.WorkspacePhaseStopping: {
+		mv1.WorkspacePhaseStopped, mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseFailed, mv1.WorkspacePhaseDeleting,
+	},
+	mv1.WorkspacePhaseStopped: {
+		mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseFailed, mv1.WorkspacePhaseDeleting,
+	},
+	mv1.WorkspacePhaseFailed: {
+		mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseDeleting,
+	},
+	mv1.WorkspacePhaseDeleting: {},
+}
+
+// TransitionError 不允许的状态转换
+type TransitionError struct {
+	From, To mv1.WorkSpacePhase
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("workspace can't transition from %s to %s", e.From, e.To)
+}
+
+// Phase 获取工作空间当前的状态, 旧版本创建的工作空间的状态可能为空或者为Created, 都视为Pending
+// WorkSpaceReconciler会将旧版本的状态转换为Pending
+func Phase(ws *mv1.WorkSpace) mv1.WorkSpacePhase {
+	if _, ok := transitions[ws.Status.Phase]; !ok {
+		return mv1.WorkspacePhasePending
+	}
+
+	return ws.Status.Phase
+}
+
+// CanTransition 是否允许从from转换到to, 状态相同时也返回true
+func CanTransition(from, to mv1.WorkSpacePhase) bool {
+	if from == to {
+		return true
+	}
+	for _, phase := range transitions[from] {
+		if phase == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Transition 校验状态转换并修改工作空间的状态, 状态没有变化时返回false
+// 调用者负责更新工作空间的状态, 更新成功后调用Record记录事件
+func Transition(ws *mv1.WorkSpace, to mv1.WorkSpacePhase) (bool, error) {
+	from := Phase(ws)
+	if !CanTransition(from, to) {
+		return false, &TransitionError{From: from, To: to}
+	}
+	if ws.Status.Phase == to {
+		return false, nil
+	}
+
+	ws.Status.Phase = to
+	return true, nil
+}
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Record 为状态转换记录Kubernetes事件, 事件的原因为新的状态
+func Record(recorder record.EventRecorder, ws *mv1.WorkSpace, from, to mv1.WorkSpacePhase) {
+	eventType := v1.EventTypeNormal
+	if to == mv1.WorkspacePhaseFailed {
+		eventType = v1.EventTypeWarning
+	}
+
+	recorder.Eventf(ws, eventType, string(to), "workspace phase changed from %s to %s", from, to)
+}
@@ -0,0 +1,92 @@
+package lifecycle
+
+import (
+	"errors"
+	"testing"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to mv1.WorkSpacePhase
+		allowed  bool
+	}{
+		{mv1.WorkspacePhasePending, mv1.WorkspacePhaseStarting, true},
+		{mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseRunning, true},
+		{mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseStopping, true},
+		{mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseStopped, true},
+		{mv1.WorkspacePhaseStopped, mv1.WorkspacePhaseStarting, true},
+		{mv1.WorkspacePhaseFailed, mv1.WorkspacePhaseStarting, true},
+		{mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseRunning, true},
+		{mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseDeleting, true},
+		{mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseStarting, true},
+		{mv1.WorkspacePhaseStopping, mv1.WorkspacePhaseRunning, false},
+		{mv1.WorkspacePhasePending, mv1.WorkspacePhaseRunning, false},
+		{mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseStarting, false},
+		{mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseStopped, false},
+		{mv1.WorkspacePhaseStopped, mv1.WorkspacePhaseRunning, false},
+		{mv1.WorkspacePhaseStopped, mv1.WorkspacePhaseStopping, false},
+		{mv1.WorkspacePhaseRunning, mv1.WorkspacePhasePending, false},
+		{mv1.WorkspacePhaseDeleting, mv1.WorkspacePhaseStarting, false},
+		{mv1.WorkspacePhaseDeleting, mv1.WorkspacePhaseStopped, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.allowed)
+		}
+	}
+}
+
+func TestPhase(t *testing.T) {
+	for _, phase := range []mv1.WorkSpacePhase{"", "Created", "Unknown"} {
+		ws := &mv1.WorkSpace{Status: mv1.WorkSpaceStatus{Phase: phase}}
+		if got := Phase(ws); got != mv1.WorkspacePhasePending {
+			t.Errorf("Phase(%q) = %s, want Pending", phase, got)
+		}
+	}
+}
+
+func TestTransition(t *testing.T) {
+	// 旧版本的状态视为Pending
+	ws := &mv1.WorkSpace{Status: mv1.WorkSpaceStatus{Phase: "Created"}}
+	changed, err := Transition(ws, mv1.WorkspacePhaseStarting)
+	if err != nil || !changed || ws.Status.Phase != mv1.WorkspacePhaseStarting {
+		t.Fatalf("transition to Starting: changed %v, err %v, phase %s", changed, err, ws.Status.Phase)
+	}
+
+	changed, err = Transition(ws, mv1.WorkspacePhaseStarting)
+	if err != nil || changed {
+		t.Errorf("transition to the same phase: changed %v, err %v", changed, err)
+	}
+
+	ws.Status.Phase = mv1.WorkspacePhaseDeleting
+	changed, err = Transition(ws, mv1.WorkspacePhaseStopped)
+	var terr *TransitionError
+	if changed || !errors.As(err, &terr) || terr.From != mv1.WorkspacePhaseDeleting {
+		t.Errorf("transition from Deleting: changed %v, err %v", changed, err)
+	}
+	if ws.Status.Phase != mv1.WorkspacePhaseDeleting {
+		t.Errorf("phase changed by a rejected transition: %s", ws.Status.Phase)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	recorder := record.NewFakeRecorder(2)
+	ws := &mv1.WorkSpace{}
+
+	Record(recorder, ws, mv1.WorkspacePhaseStarting, mv1.WorkspacePhaseRunning)
+	Record(recorder, ws, mv1.WorkspacePhaseRunning, mv1.WorkspacePhaseFailed)
+
+	want := []string{
+		"Normal Running workspace phase changed from Starting to Running",
+		"Warning Failed workspace phase changed from Running to Failed",
+	}
+	for _, w := range want {
+		if got := <-recorder.Events; got != w {
+			t.Errorf("event = %q, want %q", got, w)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/lifecycle"
+	"github.com/mangohow/cloud-ide/pkg/notifier"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// 控制面的自定义指标, 注册到controller-runtime的Registry中, 与默认指标一起通过manager的metrics接口暴露
+// 通知网关的队列长度由workqueue_depth{name="workspace-notifier"}提供
+
+const metricNamespace = "cloud_ide"
+
+var (
+	// StartDuration 从收到创建或者启动请求到Pod可用的时间, operation为create或start
+	StartDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: "workspace",
+		Name:      "start_duration_seconds",
+		Help:      "Time from the create or start request to the workspace pod running.",
+		Buckets:   []float64{1, 2, 5, 10, 15, 20, 30, 45, 60, 90, 120},
+	}, []string{"operation"})
+
+	// StopDuration 从删除Pod到Pod被删除完成的时间, 包括IDE保存文件的宽限时间
+	StopDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: "workspace",
+		Name:      "stop_duration_seconds",
+		Help:      "Time from the pod deletion to the pod removed.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 30, 60, 120, 300},
+	})
+
+	// RPCRequests WorkSpaceService每个方法的请求数量
+	RPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: "rpc",
+		Name:      "requests_total",
+		Help:      "Number of gRPC requests handled by the workspace service.",
+	}, []string{"method"})
+
+	// RPCErrors WorkSpaceService每个方法返回错误的数量, code为grpc的状态码
+	RPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: "rpc",
+		Name:      "errors_total",
+		Help:      "Number of gRPC requests of the workspace service that returned an error.",
+	}, []string{"method", "code"})
+
+	// RPCDuration WorkSpaceService每个方法的耗时
+	RPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricNamespace,
+		Subsystem: "rpc",
+		Name:      "duration_seconds",
+		Help:      "Time spent handling gRPC requests of the workspace service.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 120},
+	}, []string{"method"})
+
+	// WaiterTimeouts 等待Pod可用超时的次数
+	WaiterTimeouts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: "waiter",
+		Name:      "timeouts_total",
+		Help:      "Number of times waiting for the workspace pod running timed out.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		StartDuration,
+		StopDuration,
+		RPCRequests,
+		RPCErrors,
+		RPCDuration,
+		WaiterTimeouts,
+		notifier.RequestFailures,
+	)
+}
+
+// 统计时输出所有的状态, 没有工作空间的状态数量为0
+var phases = []mv1.WorkSpacePhase{
+	mv1.WorkspacePhasePending,
+	mv1.WorkspacePhaseStarting,
+	mv1.WorkspacePhaseRunning,
+	mv1.WorkspacePhaseStopping,
+	mv1.WorkspacePhaseStopped,
+	mv1.WorkspacePhaseFailed,
+	mv1.WorkspacePhaseDeleting,
+}
+
+// WorkspaceCollector 在每次采集时从manager的缓存中统计各个状态的工作空间数量
+type WorkspaceCollector struct {
+	reader    client.Reader
+	namespace string
+	desc      *prometheus.Desc
+}
+
+func NewWorkspaceCollector(reader client.Reader, namespace string) *WorkspaceCollector {
+	return &WorkspaceCollector{
+		reader:    reader,
+		namespace: namespace,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricNamespace, "workspace", "phase_count"),
+			"Number of workspaces in each phase.",
+			[]string{"phase"}, nil,
+		),
+	}
+}
+
+func (c *WorkspaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *WorkspaceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+
+	var list mv1.WorkSpaceList
+	if err := c.reader.List(ctx, &list, client.InNamespace(c.namespace)); err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	counts := make(map[mv1.WorkSpacePhase]int, len(phases))
+	for i := range list.Items {
+		counts[lifecycle.Phase(&list.Items[i])]++
+	}
+	for _, phase := range phases {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[phase]), string(phase))
+	}
+}
+
+// 记录每个工作空间开始停止的时间, 在Pod被删除完成后计算停止用时
+var stopping = struct {
+	sync.Mutex
+	started map[string]time.Time
+}{started: make(map[string]time.Time)}
+
+// StopStarted 记录工作空间的Pod开始删除的时间, 重复调用时保留第一次的时间
+func StopStarted(name string, t time.Time) {
+	stopping.Lock()
+	defer stopping.Unlock()
+	if _, ok := stopping.started[name]; !ok {
+		stopping.started[name] = t
+	}
+}
+
+// StopFinished 工作空间的Pod已经被删除, 记录停止用时, 没有记录开始时间时忽略
+func StopFinished(name string) {
+	stopping.Lock()
+	t, ok := stopping.started[name]
+	delete(stopping.started, name)
+	stopping.Unlock()
+
+	if ok {
+		StopDuration.Observe(time.Since(t).Seconds())
+	}
+}
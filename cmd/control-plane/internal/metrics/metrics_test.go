@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func workspace(name string, phase mv1.WorkSpacePhase) *mv1.WorkSpace {
+	return &mv1.WorkSpace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "cloud-ide-ws"},
+		Status:     mv1.WorkSpaceStatus{Phase: phase},
+	}
+}
+
+func TestWorkspaceCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		workspace("ws-user01-space01", mv1.WorkspacePhaseRunning),
+		workspace("ws-user01-space02", mv1.WorkspacePhaseRunning),
+		workspace("ws-user02-space01", mv1.WorkspacePhaseStopped),
+		// 旧版本的状态统计为Pending
+		workspace("ws-user02-space02", "Created"),
+	).Build()
+
+	want := `
+# HELP cloud_ide_workspace_phase_count Number of workspaces in each phase.
+# TYPE cloud_ide_workspace_phase_count gauge
+cloud_ide_workspace_phase_count{phase="Deleting"} 0
+cloud_ide_workspace_phase_count{phase="Failed"} 0
+cloud_ide_workspace_phase_count{phase="Pending"} 1
+cloud_ide_workspace_phase_count{phase="Running"} 2
+cloud_ide_workspace_phase_count{phase="Starting"} 0
+cloud_ide_workspace_phase_count{phase="Stopped"} 1
+cloud_ide_workspace_phase_count{phase="Stopping"} 0
+`
+	collector := NewWorkspaceCollector(c, "cloud-ide-ws")
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func stopCount(t *testing.T) uint64 {
+	var m dto.Metric
+	if err := StopDuration.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestStopDuration(t *testing.T) {
+	before := stopCount(t)
+
+	// 没有记录开始时间时忽略
+	StopFinished("ws-user01-space01")
+	if got := stopCount(t); got != before {
+		t.Fatalf("observed %d stops without start, want 0", got-before)
+	}
+
+	// 重复记录开始时间时保留第一次的时间, 只记录一次停止用时
+	StopStarted("ws-user01-space01", time.Now().Add(-10*time.Second))
+	StopStarted("ws-user01-space01", time.Now())
+	StopFinished("ws-user01-space01")
+	StopFinished("ws-user01-space01")
+
+	var m dto.Metric
+	if err := StopDuration.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetHistogram().GetSampleCount(); got != before+1 {
+		t.Fatalf("observed %d stops, want 1", got-before)
+	}
+	if sum := m.GetHistogram().GetSampleSum(); sum < 10 {
+		t.Errorf("stop duration sum = %v, want >= 10", sum)
+	}
+}
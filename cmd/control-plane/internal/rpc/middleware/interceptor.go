@@ -2,23 +2,212 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"path"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/metrics"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 var RecoveredErr = errors.New("recovered")
 
-// RecoveryInterceptorMiddleware 防止panic导致整个服务崩溃
-func RecoveryInterceptorMiddleware(logger *logr.Logger) grpc.UnaryServerInterceptor {
+// AuthOptions webserver客户端的认证方式, 都没有开启时不认证
+type AuthOptions struct {
+	// 共享密钥, 客户端通过metadata传递 authorization: Bearer <token>
+	Token string
+	// 要求客户端使用经过CA校验的证书, 证书由grpc server的tls配置校验
+	MutualTLS bool
+}
+
+// UnaryServerInterceptors 按照顺序执行: 链路追踪 -> 日志 -> 指标 -> 认证 -> panic恢复
+// panic恢复放在最内层, 使得panic转换的错误也会被记录日志和统计
+func UnaryServerInterceptors(logger logr.Logger, auth AuthOptions) []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		TracingInterceptorMiddleware(),
+		LoggingInterceptorMiddleware(logger),
+		MetricsInterceptorMiddleware(),
+		AuthInterceptorMiddleware(auth),
+		RecoveryInterceptorMiddleware(&logger),
+	}
+}
+
+// StreamServerInterceptors 与UnaryServerInterceptors的顺序相同
+func StreamServerInterceptors(logger logr.Logger, auth AuthOptions) []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		TracingStreamInterceptorMiddleware(),
+		LoggingStreamInterceptorMiddleware(logger),
+		MetricsStreamInterceptorMiddleware(),
+		AuthStreamInterceptorMiddleware(auth),
+		RecoveryStreamInterceptorMiddleware(&logger),
+	}
+}
+
+// TracingInterceptorMiddleware 从metadata中提取webserver传递的链路追踪上下文, 并为每个请求创建span
+func TracingInterceptorMiddleware() grpc.UnaryServerInterceptor {
+	return otelgrpc.UnaryServerInterceptor(otelgrpc.WithPropagators(tracing.Propagator))
+}
+
+func TracingStreamInterceptorMiddleware() grpc.StreamServerInterceptor {
+	return otelgrpc.StreamServerInterceptor(otelgrpc.WithPropagators(tracing.Propagator))
+}
+
+// LoggingInterceptorMiddleware 记录每个请求的方法、工作空间、状态码和耗时
+func LoggingInterceptorMiddleware(logger logr.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		keysAndValues := []interface{}{"method", path.Base(info.FullMethod), "code", status.Code(err).String(),
+			"duration", time.Since(start)}
+		if r, ok := req.(interface{ GetSid() string }); ok && r.GetSid() != "" {
+			keysAndValues = append(keysAndValues, "sid", r.GetSid())
+		}
+		if r, ok := req.(interface{ GetUid() string }); ok && r.GetUid() != "" {
+			keysAndValues = append(keysAndValues, "uid", r.GetUid())
+		}
+		if err != nil {
+			logger.Error(err, "grpc request", keysAndValues...)
+		} else {
+			logger.V(1).Info("grpc request", keysAndValues...)
+		}
+
+		return resp, err
+	}
+}
+
+func LoggingStreamInterceptorMiddleware(logger logr.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		keysAndValues := []interface{}{"method", path.Base(info.FullMethod), "code", status.Code(err).String(),
+			"duration", time.Since(start)}
+		if err != nil {
+			logger.Error(err, "grpc stream", keysAndValues...)
+		} else {
+			logger.V(1).Info("grpc stream", keysAndValues...)
+		}
+
+		return err
+	}
+}
+
+// MetricsInterceptorMiddleware 统计每个方法的请求数量、错误数量和耗时
+func MetricsInterceptorMiddleware() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+func MetricsStreamInterceptorMiddleware() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func observe(fullMethod string, start time.Time, err error) {
+	method := path.Base(fullMethod)
+	metrics.RPCRequests.WithLabelValues(method).Inc()
+	metrics.RPCDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.RPCErrors.WithLabelValues(method, status.Code(err).String()).Inc()
+	}
+}
+
+// AuthInterceptorMiddleware 校验webserver客户端的身份, 认证失败返回codes.Unauthenticated
+func AuthInterceptorMiddleware(opts AuthOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authenticate(ctx, opts); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func AuthStreamInterceptorMiddleware(opts AuthOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authenticate(ss.Context(), opts); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func authenticate(ctx context.Context, opts AuthOptions) error {
+	if opts.MutualTLS {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return status.Error(codes.Unauthenticated, "no peer found")
+		}
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+			return status.Error(codes.Unauthenticated, "client certificate required")
+		}
+	}
+
+	if opts.Token != "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return status.Error(codes.Unauthenticated, "authorization token required")
+		}
+		token := strings.TrimPrefix(values[0], "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(opts.Token)) != 1 {
+			return status.Error(codes.Unauthenticated, "invalid authorization token")
+		}
+	}
+
+	return nil
+}
+
+// RecoveryInterceptorMiddleware 防止panic导致整个服务崩溃, panic时返回codes.Internal
+func RecoveryInterceptorMiddleware(logger *logr.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer func() {
-			if err := recover(); err != nil {
-				logger.Error(RecoveredErr, "", "info", err)
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
 			}
 		}()
 
 		return handler(ctx, req)
 	}
 }
+
+func RecoveryStreamInterceptorMiddleware(logger *logr.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(logger, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(logger *logr.Logger, method string, r interface{}) error {
+	logger.Error(RecoveredErr, "", "method", method, "info", r, "stack", string(debug.Stack()))
+	return status.Errorf(codes.Internal, "internal error in %s", path.Base(method))
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/pb.CloudIdeService/startSpace"}
+
+func okHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return &pb.ResponseStart{}, nil
+}
+
+func TestAuthToken(t *testing.T) {
+	interceptor := AuthInterceptorMiddleware(AuthOptions{Token: "secret"})
+	tests := []struct {
+		name string
+		md   metadata.MD
+		code codes.Code
+	}{
+		{name: "valid", md: metadata.Pairs("authorization", "Bearer secret"), code: codes.OK},
+		{name: "missing", md: metadata.MD{}, code: codes.Unauthenticated},
+		{name: "invalid", md: metadata.Pairs("authorization", "Bearer other"), code: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			_, err := interceptor(ctx, &pb.RequestStart{}, testInfo, okHandler)
+			if status.Code(err) != tt.code {
+				t.Errorf("code = %v, want %v", status.Code(err), tt.code)
+			}
+		})
+	}
+}
+
+func TestAuthMutualTLS(t *testing.T) {
+	interceptor := AuthInterceptorMiddleware(AuthOptions{MutualTLS: true})
+
+	_, err := interceptor(context.Background(), &pb.RequestStart{}, testInfo, okHandler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no peer: code = %v, want Unauthenticated", status.Code(err))
+	}
+
+	verified := credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{&x509.Certificate{}}},
+	}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: verified})
+	if _, err := interceptor(ctx, &pb.RequestStart{}, testInfo, okHandler); err != nil {
+		t.Errorf("verified client: %v", err)
+	}
+
+	ctx = peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}})
+	_, err = interceptor(ctx, &pb.RequestStart{}, testInfo, okHandler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unverified client: code = %v, want Unauthenticated", status.Code(err))
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	logger := logr.Discard()
+	interceptor := RecoveryInterceptorMiddleware(&logger)
+
+	resp, err := interceptor(context.Background(), &pb.RequestStart{}, testInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if resp != nil || status.Code(err) != codes.Internal {
+		t.Errorf("resp = %v, code = %v, want Internal", resp, status.Code(err))
+	}
+}
+
+func TestTracingPropagation(t *testing.T) {
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+
+	var got trace.SpanContext
+	_, err := TracingInterceptorMiddleware()(ctx, &pb.RequestStart{}, testInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = trace.SpanContextFromContext(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s, want the trace id of the client", got.TraceID())
+	}
+}
+
+func TestChain(t *testing.T) {
+	interceptors := UnaryServerInterceptors(logr.Discard(), AuthOptions{Token: "secret"})
+	// 按照grpc的方式依次调用拦截器, 认证失败时不会调用handler
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+	var chained grpc.UnaryHandler = handler
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], chained
+		chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, testInfo, next)
+		}
+	}
+
+	_, err := chained(context.Background(), &pb.RequestStart{Sid: "space01", Uid: "user01"})
+	if status.Code(err) != codes.Unauthenticated || called {
+		t.Errorf("code = %v, handler called %v", status.Code(err), called)
+	}
+}
@@ -2,25 +2,43 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
+	"os"
 
 	"github.com/go-logr/logr"
 	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/rpc/middleware"
 	"github.com/mangohow/cloud-ide/pkg/pb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+// Options grpc服务的认证配置, 都为空时使用明文并且不认证
+type Options struct {
+	// webserver调用时需要携带的token
+	Token string
+	// 服务端证书和私钥, 为空时不使用TLS
+	CertFile string
+	KeyFile  string
+	// 校验webserver客户端证书的CA, 不为空时开启mTLS, 需要同时指定服务端证书
+	ClientCAFile string
+}
+
 type GrpcServer struct {
 	logger logr.Logger
 	addr   string
 	wsSvc  pb.CloudIdeServiceServer
+	opts   Options
 }
 
-func New(addr string, logger logr.Logger, wsSvc pb.CloudIdeServiceServer) *GrpcServer {
+func New(addr string, logger logr.Logger, wsSvc pb.CloudIdeServiceServer, opts Options) *GrpcServer {
 	return &GrpcServer{
 		logger: logger,
 		addr:   addr,
 		wsSvc:  wsSvc,
+		opts:   opts,
 	}
 }
 
@@ -29,14 +47,28 @@ func (r *GrpcServer) Start(ctx context.Context) error {
 		r.addr = ":6387"
 	}
 
+	auth := middleware.AuthOptions{Token: r.opts.Token, MutualTLS: r.opts.ClientCAFile != ""}
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(middleware.UnaryServerInterceptors(r.logger, auth)...),
+		grpc.ChainStreamInterceptor(middleware.StreamServerInterceptors(r.logger, auth)...),
+	}
+	if r.opts.CertFile != "" {
+		tlsConfig, err := r.tlsConfig()
+		if err != nil {
+			r.logger.Error(err, "load grpc tls config")
+			return err
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else if auth.MutualTLS {
+		return fmt.Errorf("grpc client ca requires the server certificate")
+	}
+
 	listener, err := net.Listen("tcp", r.addr)
 	if err != nil {
 		r.logger.Error(err, "create grpc service")
 		return err
 	}
-	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
-		middleware.RecoveryInterceptorMiddleware(&r.logger),
-	))
+	server := grpc.NewServer(serverOpts...)
 	pb.RegisterCloudIdeServiceServer(server, r.wsSvc)
 
 	go func() {
@@ -44,7 +76,7 @@ func (r *GrpcServer) Start(ctx context.Context) error {
 		server.GracefulStop()
 	}()
 
-	r.logger.Info("grpc server listen", "addr", r.addr)
+	r.logger.Info("grpc server listen", "addr", r.addr, "tls", r.opts.CertFile != "", "mtls", auth.MutualTLS, "token", auth.Token != "")
 	if err := server.Serve(listener); err != nil {
 		r.logger.Error(err, "start grpc server")
 		return err
@@ -54,3 +86,30 @@ func (r *GrpcServer) Start(ctx context.Context) error {
 
 	return nil
 }
+
+func (r *GrpcServer) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if r.opts.ClientCAFile == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(r.opts.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in %s", r.opts.ClientCAFile)
+	}
+	config.ClientCAs = pool
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return config, nil
+}
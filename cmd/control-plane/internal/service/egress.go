@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"net"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+)
+
+func validateEgress(egress *pb.EgressPolicy) error {
+	if egress == nil {
+		return nil
+	}
+	// 与webhook使用相同的限制, 在创建工作空间之前返回错误
+	if len(egress.Rules) > mv1.MaxEgressRules {
+		return fmt.Errorf("too many egress rules, max is %d", mv1.MaxEgressRules)
+	}
+
+	for _, rule := range egress.Rules {
+		if _, _, err := net.ParseCIDR(rule.Cidr); err != nil {
+			return fmt.Errorf("egress cidr %q invalid", rule.Cidr)
+		}
+		for _, port := range rule.Ports {
+			if port < 1 || port > 65535 {
+				return fmt.Errorf("egress port invalid, port must be [1,65535], now is %d", port)
+			}
+		}
+	}
+
+	return nil
+}
+
+// egress为nil时使用默认的出站策略, 不为nil时即使没有规则也允许访问DNS和gateway
+func toWorkspaceEgress(egress *pb.EgressPolicy) *mv1.WorkspaceEgress {
+	if egress == nil {
+		return nil
+	}
+
+	res := &mv1.WorkspaceEgress{}
+	for _, rule := range egress.Rules {
+		res.Rules = append(res.Rules, mv1.EgressRule{
+			CIDR:  rule.Cidr,
+			Ports: rule.Ports,
+		})
+	}
+
+	return res
+}
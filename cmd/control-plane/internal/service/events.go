@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list
+
+// GetWorkspaceEvents 获取工作空间的事件, Pod和PVC与工作空间同名, 它们的事件也一起返回
+// 事件直接从apiserver中查询, 不在manager中缓存整个命名空间的事件
+func (s *WorkSpaceService) GetWorkspaceEvents(ctx context.Context, req *pb.RequestWorkspaceEvents) (*pb.ResponseWorkspaceEvents, error) {
+	res := &pb.ResponseWorkspaceEvents{}
+	key := client.ObjectKey{Name: workspaceName(req.Uid, req.Sid), Namespace: s.namespace}
+	var ws mv1.WorkSpace
+	err := s.client.Get(ctx, key, &ws)
+	if errors.IsNotFound(err) {
+		res.Status = pb.ResponseWorkspaceEvents_NotFound
+		res.Message = WorkspaceNotExist
+		return res, status.Error(codes.NotFound, WorkspaceNotExist)
+	}
+	if err != nil {
+		s.logger.Error(err, "get workspace")
+		res.Status = pb.ResponseWorkspaceEvents_Error
+		res.Message = err.Error()
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	var events v1.EventList
+	err = s.reader.List(ctx, &events, client.InNamespace(s.namespace), client.MatchingFields{"involvedObject.name": key.Name})
+	if err != nil {
+		s.logger.Error(err, "list events")
+		res.Status = pb.ResponseWorkspaceEvents_Error
+		res.Message = err.Error()
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	res.Events = convertEvents(events.Items)
+	return res, nil
+}
+
+func convertEvents(events []v1.Event) []*pb.WorkspaceEvent {
+	res := make([]*pb.WorkspaceEvent, 0, len(events))
+	for i := range events {
+		e := &events[i]
+		count := e.Count
+		if count == 0 {
+			count = 1
+		}
+		res = append(res, &pb.WorkspaceEvent{
+			Type:      e.Type,
+			Reason:    e.Reason,
+			Message:   e.Message,
+			Kind:      e.InvolvedObject.Kind,
+			Count:     count,
+			Timestamp: eventTime(e).Unix(),
+		})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp < res[j].Timestamp
+	})
+
+	return res
+}
+
+// 事件最近一次发生的时间, 不同版本的recorder记录时间的字段不同
+func eventTime(e *v1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	case !e.FirstTimestamp.IsZero():
+		return e.FirstTimestamp.Time
+	}
+
+	return e.CreationTimestamp.Time
+}
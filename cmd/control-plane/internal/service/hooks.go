@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+)
+
+// MaxHookLength 每个生命周期钩子命令的最大长度
+const MaxHookLength = 4096
+
+func validateHooks(hooks *pb.LifecycleHooks) error {
+	if hooks == nil {
+		return nil
+	}
+	for name, command := range map[string]string{
+		"postCreate": hooks.PostCreate,
+		"postStart":  hooks.PostStart,
+		"preStop":    hooks.PreStop,
+	} {
+		if len(command) > MaxHookLength {
+			return fmt.Errorf("%s hook too long, max length is %d", name, MaxHookLength)
+		}
+	}
+
+	return nil
+}
+
+func toLifecycleHooks(hooks *pb.LifecycleHooks) *mv1.LifecycleHooks {
+	if hooks == nil || (hooks.PostCreate == "" && hooks.PostStart == "" && hooks.PreStop == "") {
+		return nil
+	}
+
+	return &mv1.LifecycleHooks{
+		PostCreate: hooks.PostCreate,
+		PostStart:  hooks.PostStart,
+		PreStop:    hooks.PreStop,
+	}
+}
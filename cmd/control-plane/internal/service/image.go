@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const ImageInvalid = "image reference invalid"
+
+// UpdateImage 修改工作空间的镜像, 用于升级或者回滚模板版本
+// 运行中的工作空间的Pod会在宽限时间后使用新的镜像重建, 停止的工作空间在下次启动时使用新的镜像
+func (s *WorkSpaceService) UpdateImage(ctx context.Context, req *pb.RequestUpdateImage) (*pb.ResponseUpdateImage, error) {
+	res := &pb.ResponseUpdateImage{}
+	if !utils.VerifyImageReference(req.Image) {
+		res.Status = pb.ResponseUpdateImage_Error
+		res.Message = ImageInvalid
+		return res, status.Error(codes.InvalidArgument, ImageInvalid)
+	}
+
+	key := client.ObjectKey{Name: workspaceName(req.Uid, req.Sid), Namespace: s.namespace}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var ws mv1.WorkSpace
+		if err := s.client.Get(ctx, key, &ws); err != nil {
+			return err
+		}
+		if ws.Spec.Image == req.Image {
+			return nil
+		}
+
+		ws.Spec.Image = req.Image
+		return s.client.Update(ctx, &ws)
+	})
+	if errors.IsNotFound(err) {
+		res.Status = pb.ResponseUpdateImage_NotFound
+		res.Message = WorkspaceNotExist
+		return res, status.Error(codes.NotFound, WorkspaceNotExist)
+	}
+	if err != nil {
+		s.logger.Error(err, "update image")
+		res.Status = pb.ResponseUpdateImage_Error
+		res.Message = err.Error()
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	return res, nil
+}
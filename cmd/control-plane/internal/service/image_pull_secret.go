@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	ImagePullSecretCreateFailed = "create image pull secret error"
+	ImagePullSecretDeleteFailed = "delete image pull secret error"
+	ImagePullSecretNotOwned     = "image pull secret not owned by user"
+)
+
+// ImagePullSecretNameFormat ips-{uid}-{hash(registry/username)}
+// 同一个用户对同一个镜像仓库使用相同的账号时会复用同一个Secret
+const ImagePullSecretNameFormat = "ips-%s-%s"
+
+type dockerConfigJson struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// CreateImagePullSecret 创建或更新用户拉取私有镜像使用的Secret
+func (s *WorkSpaceService) CreateImagePullSecret(ctx context.Context, req *pb.RequestImagePullSecret) (*pb.ResponseImagePullSecret, error) {
+	res := &pb.ResponseImagePullSecret{}
+	if len(req.Uid) < 6 || len(req.Uid) > 24 || req.Registry == "" || req.Username == "" {
+		res.Status = pb.ResponseImagePullSecret_Error
+		return res, status.Error(codes.InvalidArgument, "uid, registry and username are required")
+	}
+
+	data, err := json.Marshal(dockerConfigJson{
+		Auths: map[string]dockerConfigEntry{
+			req.Registry: {
+				Username: req.Username,
+				Password: req.Password,
+				Auth:     base64.StdEncoding.EncodeToString([]byte(req.Username + ":" + req.Password)),
+			},
+		},
+	})
+	if err != nil {
+		res.Status = pb.ResponseImagePullSecret_Error
+		return res, status.Error(codes.Internal, err.Error())
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      imagePullSecretName(req.Uid, req.Registry, req.Username),
+			Namespace: s.namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, s.client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels["uid"] = req.Uid
+		secret.Type = v1.SecretTypeDockerConfigJson
+		secret.Data = map[string][]byte{v1.DockerConfigJsonKey: data}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error(err, "create image pull secret", "uid", req.Uid)
+		res.Status = pb.ResponseImagePullSecret_Error
+		res.Message = ImagePullSecretCreateFailed
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	res.Name = secret.Name
+	return res, nil
+}
+
+// DeleteImagePullSecret 删除用户拉取私有镜像使用的Secret, 只能删除属于该用户的Secret
+func (s *WorkSpaceService) DeleteImagePullSecret(ctx context.Context, req *pb.RequestDeleteImagePullSecret) (*pb.ResponseDeleteImagePullSecret, error) {
+	res := &pb.ResponseDeleteImagePullSecret{}
+
+	var secret v1.Secret
+	err := s.client.Get(ctx, client.ObjectKey{Name: req.Name, Namespace: s.namespace}, &secret)
+	if errors.IsNotFound(err) {
+		return res, nil
+	}
+	if err != nil {
+		s.logger.Error(err, "get image pull secret", "name", req.Name)
+		res.Status = pb.ResponseDeleteImagePullSecret_Error
+		res.Message = ImagePullSecretDeleteFailed
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	if secret.Labels["uid"] != req.Uid {
+		res.Status = pb.ResponseDeleteImagePullSecret_Error
+		res.Message = ImagePullSecretNotOwned
+		return res, status.Error(codes.PermissionDenied, ImagePullSecretNotOwned)
+	}
+
+	if err := s.client.Delete(ctx, &secret); err != nil && !errors.IsNotFound(err) {
+		s.logger.Error(err, "delete image pull secret", "name", req.Name)
+		res.Status = pb.ResponseDeleteImagePullSecret_Error
+		res.Message = ImagePullSecretDeleteFailed
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	return res, nil
+}
+
+func imagePullSecretName(uid, registry, username string) string {
+	sum := sha256.Sum256([]byte(registry + "/" + username))
+	return fmt.Sprintf(ImagePullSecretNameFormat, uid, hex.EncodeToString(sum[:])[:8])
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const PrePullSetFailed = "set pre-pull images error"
+
+// 镜像拉取失败时容器处于Waiting状态的原因
+var imagePullFailedReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// SetPrePullImages 将需要预拉取的镜像保存到ConfigMap中, 由ImagePrePullReconciler维护DaemonSet
+func (s *WorkSpaceService) SetPrePullImages(ctx context.Context, req *pb.RequestSetPrePullImages) (*pb.ResponseSetPrePullImages, error) {
+	res := &pb.ResponseSetPrePullImages{}
+
+	// 去重并排序, 镜像列表没有变化时不会更新DaemonSet
+	seen := make(map[string]bool, len(req.Images))
+	images := make([]mv1.PrePullImage, 0, len(req.Images))
+	for _, image := range req.Images {
+		if !utils.VerifyImageReference(image.Image) {
+			res.Status = pb.ResponseSetPrePullImages_Error
+			res.Message = fmt.Sprintf("image %q invalid", image.Image)
+			return res, status.Error(codes.InvalidArgument, res.Message)
+		}
+		if seen[image.Image] {
+			continue
+		}
+		seen[image.Image] = true
+		images = append(images, mv1.PrePullImage{Image: image.Image, PullSecret: image.PullSecret})
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Image < images[j].Image
+	})
+
+	data, err := json.Marshal(images)
+	if err != nil {
+		res.Status = pb.ResponseSetPrePullImages_Error
+		return res, status.Error(codes.Internal, err.Error())
+	}
+
+	key := client.ObjectKey{Name: mv1.PrePullName, Namespace: s.namespace}
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var cm v1.ConfigMap
+		err := s.client.Get(ctx, key, &cm)
+		if errors.IsNotFound(err) {
+			cm = v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Data:       map[string]string{mv1.PrePullImagesKey: string(data)},
+			}
+			return s.client.Create(ctx, &cm)
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data[mv1.PrePullImagesKey] == string(data) {
+			return nil
+		}
+
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[mv1.PrePullImagesKey] = string(data)
+		return s.client.Update(ctx, &cm)
+	})
+	if err != nil {
+		s.logger.Error(err, "set pre-pull images")
+		res.Status = pb.ResponseSetPrePullImages_Error
+		res.Message = PrePullSetFailed
+		return res, status.Error(codes.Internal, PrePullSetFailed)
+	}
+
+	return res, nil
+}
+
+// PrePullStatus 根据DaemonSet中每个Pod的容器状态获取镜像在各个节点上的拉取状态
+func (s *WorkSpaceService) PrePullStatus(ctx context.Context, req *pb.RequestPrePullStatus) (*pb.ResponsePrePullStatus, error) {
+	res := &pb.ResponsePrePullStatus{}
+
+	var cm v1.ConfigMap
+	err := s.client.Get(ctx, client.ObjectKey{Name: mv1.PrePullName, Namespace: s.namespace}, &cm)
+	if errors.IsNotFound(err) {
+		return res, nil
+	}
+	if err != nil {
+		s.logger.Error(err, "get pre-pull images")
+		return res, status.Error(codes.Internal, err.Error())
+	}
+
+	var images []mv1.PrePullImage
+	if data := cm.Data[mv1.PrePullImagesKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &images); err != nil {
+			return res, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	statuses := make(map[string]*pb.ImagePrePullStatus, len(images))
+	for _, image := range images {
+		st := &pb.ImagePrePullStatus{Image: image.Image}
+		statuses[image.Image] = st
+		res.Images = append(res.Images, st)
+	}
+
+	var pods v1.PodList
+	if err := s.client.List(ctx, &pods, client.InNamespace(s.namespace), client.MatchingLabels{"app": mv1.PrePullName}); err != nil {
+		s.logger.Error(err, "list pre-pull pods")
+		return res, status.Error(codes.Internal, err.Error())
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		res.Nodes++
+		prePullPodStatus(pod, statuses)
+	}
+
+	return res, nil
+}
+
+// 容器已经创建时镜像一定已经拉取到节点上了, 因此使用ImageID判断镜像是否拉取完成
+func prePullPodStatus(pod *v1.Pod, statuses map[string]*pb.ImagePrePullStatus) {
+	images := make(map[string]string, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		images[c.Name] = c.Image
+	}
+
+	reported := make(map[string]bool, len(images))
+	for _, cs := range pod.Status.ContainerStatuses {
+		st, ok := statuses[images[cs.Name]]
+		if !ok {
+			continue
+		}
+		reported[images[cs.Name]] = true
+
+		switch {
+		case cs.ImageID != "":
+			st.ReadyNodes = append(st.ReadyNodes, pod.Spec.NodeName)
+		case cs.State.Waiting != nil && imagePullFailedReasons[cs.State.Waiting.Reason]:
+			st.FailedNodes = append(st.FailedNodes, pod.Spec.NodeName)
+			st.Message = cs.State.Waiting.Message
+		default:
+			st.PendingNodes = append(st.PendingNodes, pod.Spec.NodeName)
+		}
+	}
+
+	// Pod还没有上报容器状态, 或者Pod还在使用旧的镜像列表
+	for image, st := range statuses {
+		if !reported[image] {
+			st.PendingNodes = append(st.PendingNodes, pod.Spec.NodeName)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+)
+
+const MaxRepositoryCount = 8
+
+// 校验git仓库
+func validateRepository(repo *pb.GitRepository) error {
+	if !utils.VerifyGitRepository(repo.Url) {
+		return fmt.Errorf("git repository %s invalid", repo.Url)
+	}
+	if repo.Ref != "" && !utils.VerifyGitRef(repo.Ref) {
+		return fmt.Errorf("git ref %s invalid", repo.Ref)
+	}
+	if repo.Depth < 0 {
+		return fmt.Errorf("git depth invalid, must be >= 0, now is %d", repo.Depth)
+	}
+	if repo.Path != "" && !utils.VerifyRepositoryPath(repo.Path) {
+		return fmt.Errorf("repository path %s invalid", repo.Path)
+	}
+
+	return nil
+}
+
+// 校验工作空间中的所有仓库, 仓库的路径不能重复
+func validateRepositories(req *pb.RequestCreate) error {
+	if len(req.Repositories) > MaxRepositoryCount {
+		return fmt.Errorf("too many repositories, max is %d", MaxRepositoryCount)
+	}
+
+	paths := make(map[string]struct{}, len(req.Repositories)+1)
+	if req.GitRepository != "" {
+		paths[utils.GitRepositoryName(req.GitRepository)] = struct{}{}
+	}
+	for _, repo := range req.Repositories {
+		if err := validateRepository(repo); err != nil {
+			return err
+		}
+		p := repo.Path
+		if p == "" {
+			p = utils.GitRepositoryName(repo.Url)
+		}
+		if _, ok := paths[p]; ok {
+			return fmt.Errorf("repository path %s duplicated", p)
+		}
+		paths[p] = struct{}{}
+	}
+
+	if req.Dotfiles != nil {
+		return validateRepository(req.Dotfiles)
+	}
+
+	return nil
+}
+
+func toGitRepository(repo *pb.GitRepository) *mv1.GitRepository {
+	if repo == nil {
+		return nil
+	}
+
+	return &mv1.GitRepository{
+		URL:   repo.Url,
+		Path:  repo.Path,
+		Ref:   repo.Ref,
+		Depth: repo.Depth,
+	}
+}
+
+func toGitRepositories(repos []*pb.GitRepository) []mv1.GitRepository {
+	if len(repos) == 0 {
+		return nil
+	}
+
+	res := make([]mv1.GitRepository, 0, len(repos))
+	for _, repo := range repos {
+		res = append(res, *toGitRepository(repo))
+	}
+
+	return res
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/cron"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// 检查定时启动和停止的间隔, cron表达式的最小精度为分钟
+const scheduleInterval = time.Minute
+
+// 定时启动时等待Pod可用的最长时间
+const scheduleStartTimeout = 90 * time.Second
+
+func validateSchedule(schedule *pb.WorkspaceSchedule) error {
+	if schedule == nil {
+		return nil
+	}
+	if schedule.Start != "" {
+		if _, err := cron.Parse(schedule.Start); err != nil {
+			return fmt.Errorf("schedule start invalid: %w", err)
+		}
+	}
+	if schedule.Stop != "" {
+		if _, err := cron.Parse(schedule.Stop); err != nil {
+			return fmt.Errorf("schedule stop invalid: %w", err)
+		}
+	}
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return fmt.Errorf("schedule timezone invalid: %w", err)
+	}
+
+	return nil
+}
+
+func toWorkspaceSchedule(schedule *pb.WorkspaceSchedule) *mv1.WorkspaceSchedule {
+	if schedule == nil || (schedule.Start == "" && schedule.Stop == "") {
+		return nil
+	}
+
+	return &mv1.WorkspaceSchedule{
+		Start:    schedule.Start,
+		Stop:     schedule.Stop,
+		Timezone: schedule.Timezone,
+	}
+}
+
+// SetSchedule 设置工作空间的定时启动和停止
+func (s *WorkSpaceService) SetSchedule(ctx context.Context, req *pb.RequestSetSchedule) (*pb.ResponseSetSchedule, error) {
+	res := &pb.ResponseSetSchedule{}
+	if err := validateSchedule(req.Schedule); err != nil {
+		s.logger.Error(err, "request param invalid")
+		res.Status = pb.ResponseSetSchedule_Error
+		res.Message = err.Error()
+		return res, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	key := client.ObjectKey{Name: workspaceName(req.Uid, req.Sid), Namespace: s.namespace}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var ws mv1.WorkSpace
+		if err := s.client.Get(ctx, key, &ws); err != nil {
+			return err
+		}
+
+		ws.Spec.Schedule = toWorkspaceSchedule(req.Schedule)
+		return s.client.Update(ctx, &ws)
+	})
+	if errors.IsNotFound(err) {
+		res.Status = pb.ResponseSetSchedule_NotFound
+		res.Message = WorkspaceNotExist
+		return res, status.Error(codes.NotFound, WorkspaceNotExist)
+	}
+	if err != nil {
+		s.logger.Error(err, "set schedule")
+		res.Status = pb.ResponseSetSchedule_Error
+		res.Message = err.Error()
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
+	return res, nil
+}
+
+// WorkspaceScheduler 定时检查工作空间的定时配置, 通过WorkSpaceService启动和停止工作空间
+// 实现了manager.Runnable, 开启选主时只在leader中运行
+type WorkspaceScheduler struct {
+	service  *WorkSpaceService
+	interval time.Duration
+}
+
+func NewWorkspaceScheduler(service *WorkSpaceService) *WorkspaceScheduler {
+	return &WorkspaceScheduler{
+		service:  service,
+		interval: scheduleInterval,
+	}
+}
+
+// Start 每个间隔检查一次在上次检查之后到达的启动和停止时间
+func (w *WorkspaceScheduler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			w.schedule(ctx, last, now)
+			last = now
+		}
+	}
+}
+
+func (w *WorkspaceScheduler) schedule(ctx context.Context, last, now time.Time) {
+	var wss mv1.WorkSpaceList
+	if err := w.service.client.List(ctx, &wss, client.InNamespace(w.service.namespace)); err != nil {
+		w.service.logger.Error(err, "list workspace")
+		return
+	}
+
+	for i := range wss.Items {
+		ws := &wss.Items[i]
+		if ws.Spec.Schedule == nil || ws.DeletionTimestamp != nil {
+			continue
+		}
+
+		command, err := scheduledCommand(ws.Spec.Schedule, last, now)
+		if err != nil {
+			w.service.logger.Error(err, "parse schedule", "name", ws.Name)
+			continue
+		}
+
+		switch {
+		case command == mv1.WorkSpaceStart && ws.Spec.Command != mv1.WorkSpaceStart:
+			w.service.logger.Info("scheduled start", "name", ws.Name)
+			if err := w.start(ctx, ws); err != nil {
+				w.service.logger.Error(err, "scheduled start", "name", ws.Name)
+			}
+		case command == mv1.WorkSpaceStop && ws.Spec.Command == mv1.WorkSpaceStart:
+			w.service.logger.Info("scheduled stop", "name", ws.Name)
+			if _, err := w.service.StopSpace(ctx, &pb.RequestStop{Sid: ws.Spec.SID, Uid: ws.Spec.UID}); err != nil {
+				w.service.logger.Error(err, "scheduled stop", "name", ws.Name)
+			}
+		}
+	}
+}
+
+// 返回(last, now]之间到达的操作, 启动和停止时间都到达时以后到达的为准
+func scheduledCommand(schedule *mv1.WorkspaceSchedule, last, now time.Time) (mv1.WorkspaceCommand, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return "", err
+	}
+	last = last.In(loc)
+
+	var (
+		command mv1.WorkspaceCommand
+		latest  time.Time
+	)
+	for _, item := range []struct {
+		spec    string
+		command mv1.WorkspaceCommand
+	}{
+		{schedule.Start, mv1.WorkSpaceStart},
+		{schedule.Stop, mv1.WorkSpaceStop},
+	} {
+		if item.spec == "" {
+			continue
+		}
+		s, err := cron.Parse(item.spec)
+		if err != nil {
+			return "", err
+		}
+
+		next := s.Next(last)
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+		if next.After(latest) {
+			command, latest = item.command, next
+		}
+	}
+
+	return command, nil
+}
+
+// 定时启动通过StartSpace使用上次启动时的配置, 环境变量和git凭证保存在Secret中
+// 每个用户同时只能运行一个工作空间, 用户已经有运行中的工作空间时跳过
+// 定时配置保存在工作空间中, 只在数据库中创建而没有启动过的工作空间在控制面中不存在, 第一次手动启动后定时才会生效
+// StartSpace会等待Pod可用, 在单独的协程中执行, 避免阻塞其它工作空间的定时
+func (w *WorkspaceScheduler) start(ctx context.Context, ws *mv1.WorkSpace) error {
+	running, err := w.service.RunningWorkspaces(ctx, &pb.RequestRunningWorkspaces{Uid: ws.Spec.UID})
+	if err != nil {
+		return err
+	}
+	if len(running.Workspaces) > 0 {
+		w.service.logger.Info("user has running workspace, skip scheduled start", "name", ws.Name)
+		return nil
+	}
+
+	req := &pb.RequestStart{Sid: ws.Spec.SID, Uid: ws.Spec.UID, ReuseConfig: true}
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, scheduleStartTimeout)
+		defer cancel()
+		if _, err := w.service.StartSpace(ctx, req); err != nil {
+			w.service.logger.Error(err, "scheduled start", "name", ws.Name)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+)
+
+func validateScheduling(scheduling *pb.Scheduling) error {
+	if scheduling == nil {
+		return nil
+	}
+
+	for k, v := range scheduling.NodeSelector {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("node selector key %q invalid: %s", k, strings.Join(errs, ","))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("node selector value %q invalid: %s", v, strings.Join(errs, ","))
+		}
+	}
+
+	for _, t := range scheduling.Tolerations {
+		switch v1.TolerationOperator(t.Operator) {
+		case v1.TolerationOpExists:
+			if t.Value != "" {
+				return fmt.Errorf("toleration value must be empty when operator is Exists")
+			}
+		case v1.TolerationOpEqual, "":
+			if t.Key == "" {
+				return fmt.Errorf("toleration key is required when operator is Equal")
+			}
+		default:
+			return fmt.Errorf("toleration operator %q invalid", t.Operator)
+		}
+		switch v1.TaintEffect(t.Effect) {
+		case "", v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+		default:
+			return fmt.Errorf("toleration effect %q invalid", t.Effect)
+		}
+		if t.TolerationSeconds < 0 {
+			return fmt.Errorf("toleration seconds must be >= 0")
+		}
+	}
+
+	if scheduling.PriorityClassName != "" {
+		if errs := validation.IsDNS1123Subdomain(scheduling.PriorityClassName); len(errs) > 0 {
+			return fmt.Errorf("priority class name invalid: %s", strings.Join(errs, ","))
+		}
+	}
+
+	for _, spread := range scheduling.TopologySpread {
+		if errs := validation.IsQualifiedName(spread.TopologyKey); len(errs) > 0 {
+			return fmt.Errorf("topology key %q invalid: %s", spread.TopologyKey, strings.Join(errs, ","))
+		}
+		if spread.MaxSkew < 1 {
+			return fmt.Errorf("topology max skew must be >= 1, now is %d", spread.MaxSkew)
+		}
+	}
+
+	return nil
+}
+
+func toWorkspaceScheduling(scheduling *pb.Scheduling) *mv1.WorkspaceScheduling {
+	if scheduling == nil || (len(scheduling.NodeSelector) == 0 && len(scheduling.Tolerations) == 0 &&
+		scheduling.PriorityClassName == "" && len(scheduling.TopologySpread) == 0) {
+		return nil
+	}
+
+	res := &mv1.WorkspaceScheduling{
+		NodeSelector:      scheduling.NodeSelector,
+		PriorityClassName: scheduling.PriorityClassName,
+	}
+	for _, t := range scheduling.Tolerations {
+		toleration := v1.Toleration{
+			Key:      t.Key,
+			Operator: v1.TolerationOperator(t.Operator),
+			Value:    t.Value,
+			Effect:   v1.TaintEffect(t.Effect),
+		}
+		if t.TolerationSeconds > 0 && toleration.Effect == v1.TaintEffectNoExecute {
+			toleration.TolerationSeconds = pointer.Int64(t.TolerationSeconds)
+		}
+		res.Tolerations = append(res.Tolerations, toleration)
+	}
+	for _, spread := range scheduling.TopologySpread {
+		whenUnsatisfiable := v1.DoNotSchedule
+		if spread.ScheduleAnyway {
+			whenUnsatisfiable = v1.ScheduleAnyway
+		}
+		res.TopologySpread = append(res.TopologySpread, mv1.TopologySpread{
+			TopologyKey:       spread.TopologyKey,
+			MaxSkew:           spread.MaxSkew,
+			WhenUnsatisfiable: whenUnsatisfiable,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// 校验环境变量的名称
+func validateEnvs(envs map[string]string) error {
+	for name := range envs {
+		if errs := validation.IsEnvVarName(name); len(errs) > 0 {
+			return fmt.Errorf("env name %s invalid: %s", name, strings.Join(errs, ","))
+		}
+	}
+
+	return nil
+}
+
+// 创建或更新保存工作空间环境变量的Secret, 如果没有环境变量则删除Secret
+func (s *WorkSpaceService) applyEnvSecret(ctx context.Context, name, uid string, envs map[string]string, owner *mv1.WorkSpace) error {
+	var data map[string][]byte
+	if len(envs) > 0 {
+		data = make(map[string][]byte, len(envs))
+		for k, v := range envs {
+			data[k] = []byte(v)
+		}
+	}
+
+	return s.applySecret(ctx, mv1.EnvSecretName(name), uid, data, owner)
+}
+
+// 创建或更新克隆git仓库使用的凭证, 如果没有凭证则删除Secret
+// https凭证使用git-credential-store的格式保存在同一个文件中, ssh私钥和主机公钥按照主机名分别保存
+func (s *WorkSpaceService) applyGitSecret(ctx context.Context, name, uid string, creds []*pb.GitCredential, owner *mv1.WorkSpace) error {
+	data := make(map[string][]byte)
+	var store strings.Builder
+	for _, cred := range creds {
+		if cred == nil || cred.Secret == "" {
+			continue
+		}
+
+		switch cred.Type {
+		case pb.GitCredential_Https:
+			u := url.URL{Scheme: "https", User: url.UserPassword(cred.Username, cred.Secret), Host: cred.Host}
+			store.WriteString(u.String() + "\n")
+		case pb.GitCredential_Ssh:
+			key := cred.Secret
+			if !strings.HasSuffix(key, "\n") {
+				key += "\n"
+			}
+			data[mv1.GitSshKeyPrefix+cred.Host] = []byte(key)
+			if cred.KnownHosts != "" {
+				data[mv1.GitKnownHostsPrefix+cred.Host] = []byte(strings.TrimSpace(cred.KnownHosts) + "\n")
+			}
+		}
+	}
+	if store.Len() > 0 {
+		data[mv1.GitCredentialsKey] = []byte(store.String())
+	}
+
+	return s.applySecret(ctx, mv1.GitSecretName(name), uid, data, owner)
+}
+
+// 校验git凭证
+func validateGitCredentials(creds []*pb.GitCredential) error {
+	for _, cred := range creds {
+		if cred == nil || cred.Secret == "" {
+			continue
+		}
+		host := cred.Host
+		if cred.Type == pb.GitCredential_Https {
+			host = strings.Split(host, ":")[0]
+		}
+		if errs := validation.IsDNS1123Subdomain(strings.ToLower(host)); len(errs) > 0 {
+			return fmt.Errorf("git credential host %s invalid: %s", cred.Host, strings.Join(errs, ","))
+		}
+		if cred.Type == pb.GitCredential_Https && cred.Username == "" {
+			return fmt.Errorf("git credential username is required")
+		}
+	}
+
+	return nil
+}
+
+// 创建或更新Secret, data为空时删除Secret
+// Secret需要在Pod创建之前准备好, 因此在创建工作空间时先创建Secret, 工作空间创建完成后再设置OwnerReference
+func (s *WorkSpaceService) applySecret(ctx context.Context, name, uid string, data map[string][]byte, owner *mv1.WorkSpace) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.namespace,
+		},
+	}
+
+	if len(data) == 0 {
+		err := s.client.Delete(ctx, secret)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, s.client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels["uid"] = uid
+		secret.Type = v1.SecretTypeOpaque
+		secret.Data = data
+		if owner != nil {
+			return controllerutil.SetControllerReference(owner, secret, s.client.Scheme())
+		}
+		return nil
+	})
+
+	return err
+}
+
+// 设置工作空间的Secret的OwnerReference, 工作空间被删除时Secret会被回收
+func (s *WorkSpaceService) setSecretOwner(ctx context.Context, ws *mv1.WorkSpace) error {
+	for _, name := range []string{mv1.EnvSecretName(ws.Name), mv1.GitSecretName(ws.Name)} {
+		var secret v1.Secret
+		err := s.client.Get(ctx, client.ObjectKey{Name: name, Namespace: s.namespace}, &secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if err := controllerutil.SetControllerReference(ws, &secret, s.client.Scheme()); err != nil {
+			return err
+		}
+		if err := s.client.Update(ctx, &secret); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/mangohow/cloud-ide/cmd/control-plane/internal/service")
+
+// 为工作空间的操作创建span, 通过grpc调用时父span为grpc请求的span, 定时启动和停止时为新的trace
+func startSpan(ctx context.Context, name, sid, uid string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attribute.String("sid", sid), attribute.String("uid", uid)))
+}
+
+// 根据err设置span的状态并结束span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -2,20 +2,25 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/lifecycle"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/metrics"
 	"github.com/mangohow/cloud-ide/pkg/notifier"
 	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
+	"github.com/mangohow/cloud-ide/pkg/utils"
 	"google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -24,15 +29,23 @@ type WorkSpaceService struct {
 	pb.UnimplementedCloudIdeServiceServer
 	logger    logr.Logger
 	client    client.Client
+	reader    client.Reader
 	waiter    notifier.Waiter
+	notifier  notifier.Notifier
+	recorder  record.EventRecorder
 	namespace string
 }
 
-func NewWorkSpaceService(c client.Client, logger logr.Logger, waiter notifier.Waiter, namespace string) *WorkSpaceService {
+// NewWorkSpaceService reader用于查询不在manager中缓存的资源, 例如事件
+func NewWorkSpaceService(c client.Client, reader client.Reader, logger logr.Logger, waiter notifier.Waiter, ntf notifier.Notifier,
+	recorder record.EventRecorder, namespace string) *WorkSpaceService {
 	return &WorkSpaceService{
 		logger:    logger,
 		client:    c,
+		reader:    reader,
 		waiter:    waiter,
+		notifier:  ntf,
+		recorder:  recorder,
 		namespace: namespace,
 	}
 }
@@ -46,16 +59,30 @@ const (
 	WorkspaceStartFailed  = "start workspace error"
 	WorkspaceStopFailed   = "stop workspace error"
 	WorkspaceDeleteFailed = "delete workspace error"
+	WorkspaceNotScheduled = "workspace stop not scheduled"
 )
 
+// MaxStopDelay 延迟停止的最大时间
+const MaxStopDelay = 24 * time.Hour
+
 const WorkspaceNameFormat = "ws-%s-%s"
 
 // CreateSpace 创建并且启动Workspace,将Operation字段置为"Start",当Workspace被创建时,PVC和Pod也会被创建
 // 该接口仅被用于第一次创建工作空间并且启动
-func (s *WorkSpaceService) CreateSpace(ctx context.Context, info *pb.RequestCreate) (*pb.ResponseCreate, error) {
+func (s *WorkSpaceService) CreateSpace(ctx context.Context, req *pb.RequestCreate) (*pb.ResponseCreate, error) {
+	ctx, span := startSpan(ctx, "WorkSpaceService.CreateSpace", req.Sid, req.Uid)
+	res, err := s.createSpace(ctx, req)
+	endSpan(span, err)
+
+	return res, err
+}
+
+// createSpace 创建Secret和Workspace并等待Pod可用
+func (s *WorkSpaceService) createSpace(ctx context.Context, info *pb.RequestCreate) (*pb.ResponseCreate, error) {
 	var (
-		ws  = &mv1.WorkSpace{}
-		res = &pb.ResponseCreate{}
+		ws    = &mv1.WorkSpace{}
+		res   = &pb.ResponseCreate{}
+		begin = time.Now()
 	)
 
 	// 校验参数
@@ -76,8 +103,23 @@ func (s *WorkSpaceService) CreateSpace(ctx context.Context, info *pb.RequestCrea
 		return res, stus.Err()
 	}
 
-	// 2.如果不存在就创建
+	// 2.如果不存在就创建,先创建保存环境变量和git凭证的Secret,保证Pod创建时Secret已经存在
+	if err := s.applyEnvSecret(ctx, name, info.Uid, info.Envs, nil); err != nil {
+		s.logger.Error(err, "create env secret")
+		res.Status = pb.ResponseCreate_Error
+		res.Message = WorkspaceCreateFailed
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+	if err := s.applyGitSecret(ctx, name, info.Uid, info.GitCredentials, nil); err != nil {
+		s.logger.Error(err, "create git secret")
+		res.Status = pb.ResponseCreate_Error
+		res.Message = WorkspaceCreateFailed
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+
 	w := s.constructWorkspace(info, name)
+	// 控制器创建PVC和Pod的span与本次请求属于同一个trace
+	w.Annotations = tracing.InjectAnnotations(ctx, w.Annotations)
 	if err := s.client.Create(ctx, w); err != nil {
 		if errors.IsAlreadyExists(err) {
 			res.Status = pb.ResponseCreate_AlreadyExist
@@ -86,13 +128,23 @@ func (s *WorkSpaceService) CreateSpace(ctx context.Context, info *pb.RequestCrea
 		}
 
 		s.logger.Error(err, "create workspace")
+		if err := s.applyEnvSecret(ctx, name, info.Uid, nil, nil); err != nil {
+			s.logger.Error(err, "delete env secret")
+		}
+		if err := s.applyGitSecret(ctx, name, info.Uid, nil, nil); err != nil {
+			s.logger.Error(err, "delete git secret")
+		}
 		res.Status = pb.ResponseCreate_Error
 		res.Message = WorkspaceCreateFailed
 		return res, status.Error(codes.Unknown, err.Error())
 	}
 
+	if err := s.setSecretOwner(ctx, w); err != nil {
+		s.logger.Error(err, "set secret owner")
+	}
+
 	// 3.等待Pod处于Running状态
-	err = s.waitForPodRunning(ctx, client.ObjectKey{Name: w.Name, Namespace: w.Namespace}, w)
+	err = s.waitForPodRunning(ctx, client.ObjectKey{Name: w.Name, Namespace: w.Namespace}, w, "create", begin)
 	if err != nil {
 		s.logger.Error(err, "wait for pod running")
 		res.Status = pb.ResponseCreate_Error
@@ -103,18 +155,25 @@ func (s *WorkSpaceService) CreateSpace(ctx context.Context, info *pb.RequestCrea
 	return res, nil
 }
 
-func (s *WorkSpaceService) waitForPodRunning(ctx context.Context, key client.ObjectKey, ws *mv1.WorkSpace) error {
+// 等待Pod可用, 记录从收到请求(begin)到Pod可用的时间, operation为create或start
+func (s *WorkSpaceService) waitForPodRunning(ctx context.Context, key client.ObjectKey, ws *mv1.WorkSpace, operation string, begin time.Time) error {
 	s.logger.Info("waiting for pod ready", "name", key.Name)
 
-	c, cancelFunc := context.WithTimeout(ctx, time.Second*60)
+	c, span := startSpan(ctx, "WorkSpaceService.waitForPodRunning", ws.Spec.SID, ws.Spec.UID)
+	c, cancelFunc := context.WithTimeout(c, time.Second*60)
 	defer cancelFunc()
 	// 1.等待Pod可用，最久等待60s
 	err := s.waiter.WaitFor(c, ws.Spec.SID)
+	endSpan(span, err)
 	if err == nil {
+		metrics.StartDuration.WithLabelValues(operation).Observe(time.Since(begin).Seconds())
 		return nil
 	}
 
 	s.logger.Error(err, "wait for pod ready")
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		metrics.WaiterTimeouts.Inc()
+	}
 	// 2.处理错误情况,停止工作空间
 	_, err = s.StopSpace(ctx, &pb.RequestStop{
 		Uid: ws.Spec.UID,
@@ -129,9 +188,21 @@ func (s *WorkSpaceService) waitForPodRunning(ctx context.Context, key client.Obj
 
 // StartSpace 启动Workspace
 func (s *WorkSpaceService) StartSpace(ctx context.Context, req *pb.RequestStart) (*pb.ResponseStart, error) {
-	if err := s.validateResourceLimit(req.ResourceLimit); err != nil {
-		s.logger.Error(err, "request param invalid")
-		return &pb.ResponseStart{}, err
+	ctx, span := startSpan(ctx, "WorkSpaceService.StartSpace", req.Sid, req.Uid)
+	res, err := s.startSpace(ctx, req)
+	endSpan(span, err)
+
+	return res, err
+}
+
+// startSpace 更新Workspace的配置后等待Pod可用, ReuseConfig为true时使用上次启动时的配置
+func (s *WorkSpaceService) startSpace(ctx context.Context, req *pb.RequestStart) (*pb.ResponseStart, error) {
+	begin := time.Now()
+	if !req.ReuseConfig {
+		if err := s.validateStartConfig(req); err != nil {
+			s.logger.Error(err, "request param invalid")
+			return &pb.ResponseStart{}, err
+		}
 	}
 
 	res := &pb.ResponseStart{}
@@ -149,31 +220,53 @@ func (s *WorkSpaceService) StartSpace(ctx context.Context, req *pb.RequestStart)
 		return res, status.Error(codes.NotFound, WorkspaceNotExist)
 	}
 
-	// 2.判断workspace是否处于运行或启动中状态
-	if ws.Status.Phase == mv1.WorkspacePhaseStaring || ws.Status.Phase == mv1.WorkspacePhaseRunning {
+	// 2.判断workspace是否处于运行或启动中状态, 删除中的工作空间不能启动
+	// 停止通知的宽限时间内Pod还没有删除, 状态仍为Running, 此时将操作改回Start以取消停止, 计划的停止同样被取消
+	phase := workspacePhase(&ws)
+	running := phase == mv1.WorkspacePhaseStarting || phase == mv1.WorkspacePhaseRunning
+	if running && ws.Spec.Command == mv1.WorkSpaceStart && ws.Spec.ScheduledStop == nil {
 		return res, nil
 	}
+	if !running && !lifecycle.CanTransition(phase, mv1.WorkspacePhaseStarting) {
+		err := &lifecycle.TransitionError{From: phase, To: mv1.WorkspacePhaseStarting}
+		res.Status = pb.ResponseStart_Error
+		res.Message = WorkspaceStartFailed
+		return res, status.Error(codes.FailedPrecondition, err.Error())
+	}
 
-	// 3.Pod的配置可能会改变
-	ws.Spec.Cpu = req.ResourceLimit.Cpu
-	ws.Spec.Memory = req.ResourceLimit.Memory
-	// TODO storage改变需要特殊处理
-	// ws.Spec.Storage = req.ResourceLimit.Storage
+	// 3.Pod的配置可能会改变,环境变量需要在Pod创建之前更新
+	// 取消停止时Pod继续运行, 新的配置在下一次启动时生效
+	applyConfig := !req.ReuseConfig && !running
+	if applyConfig {
+		if err := s.applyStartSecrets(ctx, key.Name, req, &ws); err != nil {
+			res.Status = pb.ResponseStart_Error
+			res.Message = WorkspaceStartFailed
+			return res, status.Error(codes.Unknown, err.Error())
+		}
+	}
 
 	// 4.更新Workspace的Operation字段以启动,使用RetryOnConflict,当资源版本冲突时重试
+	scheduled := false
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		// 每次更新前要获取最新的版本
+		// 每次更新前要获取最新的版本, 修改应用到最新的版本上
 		var p mv1.WorkSpace
-		exist := s.checkWorkspaceExist(ctx, key, &p)
+		exist = s.checkWorkspaceExist(ctx, key, &p)
 		if !exist {
 			return nil
 		}
 
-		// 更新workspace的Operation字段
-		ws.Spec.Command = mv1.WorkSpaceStart
-		if err := s.client.Update(ctx, &ws); err != nil {
+		if applyConfig {
+			applyStartConfig(req, &p)
+		}
+		// 更新workspace的Operation字段, 重新启动后之前计划的停止不再生效
+		scheduled = p.Spec.ScheduledStop != nil
+		p.Spec.Command = mv1.WorkSpaceStart
+		p.Spec.ScheduledStop = nil
+		p.Annotations = tracing.InjectAnnotations(ctx, p.Annotations)
+		if err := s.client.Update(ctx, &p); err != nil {
 			return err
 		}
+		ws = p
 
 		return nil
 	})
@@ -191,7 +284,16 @@ func (s *WorkSpaceService) StartSpace(ctx context.Context, req *pb.RequestStart)
 		return res, status.Error(codes.NotFound, WorkspaceNotExist)
 	}
 
-	err = s.waitForPodRunning(ctx, key, &ws)
+	// 撤销计划的停止推送给IDE的通知
+	if scheduled {
+		s.notifier.Notice(req.Sid, nil)
+	}
+	// 取消停止时Pod没有重建, 不需要等待
+	if running {
+		return res, nil
+	}
+
+	err = s.waitForPodRunning(ctx, key, &ws, "start", begin)
 	if err != nil {
 		s.logger.Error(err, "wait for pod running")
 		res.Status = pb.ResponseStart_Error
@@ -202,8 +304,72 @@ func (s *WorkSpaceService) StartSpace(ctx context.Context, req *pb.RequestStart)
 	return res, nil
 }
 
+// 校验启动时更新的配置
+func (s *WorkSpaceService) validateStartConfig(req *pb.RequestStart) error {
+	if err := s.validateResourceLimit(req.ResourceLimit); err != nil {
+		return err
+	}
+	if err := validateEnvs(req.Envs); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateGitCredentials(req.GitCredentials); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Dotfiles != nil {
+		if err := validateRepository(req.Dotfiles); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := validateHooks(req.Hooks); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateEgress(req.Egress); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
+
+// 更新环境变量和git凭证的Secret
+func (s *WorkSpaceService) applyStartSecrets(ctx context.Context, name string, req *pb.RequestStart, ws *mv1.WorkSpace) error {
+	if err := s.applyEnvSecret(ctx, name, req.Uid, req.Envs, ws); err != nil {
+		s.logger.Error(err, "update env secret")
+		return err
+	}
+	if err := s.applyGitSecret(ctx, name, req.Uid, req.GitCredentials, ws); err != nil {
+		s.logger.Error(err, "update git secret")
+		return err
+	}
+
+	return nil
+}
+
+// 更新工作空间中每次启动时更新的配置
+func applyStartConfig(req *pb.RequestStart, ws *mv1.WorkSpace) {
+	ws.Spec.Cpu = req.ResourceLimit.Cpu
+	ws.Spec.Memory = req.ResourceLimit.Memory
+	// 管理员可能修改了规格的调度约束
+	ws.Spec.Scheduling = toWorkspaceScheduling(req.ResourceLimit.Scheduling)
+	// dotfiles和生命周期钩子在每次启动时应用
+	ws.Spec.Dotfiles = toGitRepository(req.Dotfiles)
+	ws.Spec.Hooks = toLifecycleHooks(req.Hooks)
+	// 管理员可能修改了模板的出站白名单
+	ws.Spec.Egress = toWorkspaceEgress(req.Egress)
+	// TODO storage改变需要特殊处理
+	// ws.Spec.Storage = req.ResourceLimit.Storage
+}
+
 // DeleteSpace 只需要将workspace删除即可,controller会负责删除对应的Pod和PVC
 func (s *WorkSpaceService) DeleteSpace(ctx context.Context, req *pb.RequestDelete) (*pb.ResponseDelete, error) {
+	ctx, span := startSpan(ctx, "WorkSpaceService.DeleteSpace", req.Sid, req.Uid)
+	res, err := s.deleteSpace(ctx, req)
+	endSpan(span, err)
+
+	return res, err
+}
+
+// deleteSpace 记录保留策略后删除Workspace
+func (s *WorkSpaceService) deleteSpace(ctx context.Context, req *pb.RequestDelete) (*pb.ResponseDelete, error) {
 	res := &pb.ResponseDelete{}
 	// 先查询是否存在,如果不存在则无需删除
 	var ws mv1.WorkSpace
@@ -213,20 +379,58 @@ func (s *WorkSpaceService) DeleteSpace(ctx context.Context, req *pb.RequestDelet
 		return res, nil
 	}
 
+	// 记录数据的保留策略, 由WorkSpaceReconciler在删除时处理工作空间的数据
+	retention := toWorkspaceRetention(req.Retention, req.RetainDays, req.RetainUntil)
+	// 工作空间已经在删除中时不再修改保留策略
+	if ws.DeletionTimestamp == nil && (retention != nil || ws.Spec.Retention != nil) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := s.client.Get(ctx, client.ObjectKeyFromObject(&ws), &ws); err != nil {
+				return err
+			}
+			ws.Spec.Retention = retention
+			return s.client.Update(ctx, &ws)
+		})
+		if err != nil && !errors.IsNotFound(err) {
+			s.logger.Error(err, "update workspace retention")
+			res.Status = pb.ResponseDelete_Error
+			res.Message = WorkspaceDeleteFailed
+			return res, status.Error(codes.Unknown, err.Error())
+		}
+	}
+
 	// 删除Workspace
-	if err := s.client.Delete(ctx, &ws); err != nil {
+	if err := s.client.Delete(ctx, &ws); client.IgnoreNotFound(err) != nil {
 		s.logger.Error(err, "delete workspace")
 		res.Status = pb.ResponseDelete_Error
 		res.Message = WorkspaceDeleteFailed
 		return res, status.Error(codes.Unknown, err.Error())
 	}
 
+	// 标记为删除中, 之后不能再启动或者停止, 工作空间没有finalizer时已经被删除
+	if err := s.transition(ctx, client.ObjectKeyFromObject(&ws), mv1.WorkspacePhaseDeleting); client.IgnoreNotFound(err) != nil {
+		s.logger.Error(err, "update workspace phase")
+	}
+
 	return res, nil
 }
 
 // StopSpace 停止Workspace,只需要删除对应的Pod,因此修改Workspace的操作为Stop即可
+// 指定了延迟时间时只记录计划的停止时间, 由WorkSpaceReconciler在到达停止时间后停止
 func (s *WorkSpaceService) StopSpace(ctx context.Context, req *pb.RequestStop) (*pb.ResponseStop, error) {
+	ctx, span := startSpan(ctx, "WorkSpaceService.StopSpace", req.Sid, req.Uid)
+	res, err := s.stopSpace(ctx, req)
+	endSpan(span, err)
+
+	return res, err
+}
+
+// stopSpace 修改Workspace的操作为Stop或者记录计划的停止时间
+func (s *WorkSpaceService) stopSpace(ctx context.Context, req *pb.RequestStop) (*pb.ResponseStop, error) {
 	res := &pb.ResponseStop{}
+	delay := time.Duration(req.DelaySeconds) * time.Second
+	if delay < 0 || delay > MaxStopDelay {
+		return res, status.Error(codes.InvalidArgument, fmt.Sprintf("stop delay invalid, must be [0,%s]", MaxStopDelay))
+	}
 
 	// 1.先查询Workspace是否存在，不存在则直接返回
 	var ws mv1.WorkSpace
@@ -238,13 +442,25 @@ func (s *WorkSpaceService) StopSpace(ctx context.Context, req *pb.RequestStop) (
 		return res, status.Error(codes.NotFound, WorkspaceNotExist)
 	}
 
-	// 2.工作空间正在停止或已停止
-	if ws.Status.Phase == mv1.WorkspacePhaseStopping || ws.Status.Phase == mv1.WorkspacePhaseStopped {
+	// 2.工作空间正在停止或已停止, 删除中的工作空间不能停止
+	phase := workspacePhase(&ws)
+	if phase == mv1.WorkspacePhaseStopping || phase == mv1.WorkspacePhaseStopped {
 		return res, nil
 	}
+	if !lifecycle.CanTransition(phase, mv1.WorkspacePhaseStopping) {
+		err := &lifecycle.TransitionError{From: phase, To: mv1.WorkspacePhaseStopping}
+		res.Status = pb.ResponseStop_Error
+		res.Message = WorkspaceStopFailed
+		return res, status.Error(codes.FailedPrecondition, err.Error())
+	}
 
-	// 3.更新Operation字段以停止Workspace
+	// 3.更新Operation字段以停止Workspace, 或者记录计划的停止时间
 	// 使用Update时,可能由于版本冲突而导致失败,需要重试
+	var scheduled *metav1.Time
+	if delay > 0 {
+		t := metav1.NewTime(time.Now().Add(delay).Truncate(time.Second))
+		scheduled = &t
+	}
 	exist := true
 	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		var wp mv1.WorkSpace
@@ -254,7 +470,12 @@ func (s *WorkSpaceService) StopSpace(ctx context.Context, req *pb.RequestStop) (
 		}
 
 		// 更新workspace的Operation字段
-		wp.Spec.Command = mv1.WorkSpaceStop
+		if scheduled != nil {
+			wp.Spec.ScheduledStop = scheduled
+		} else {
+			wp.Spec.Command = mv1.WorkSpaceStop
+			wp.Spec.ScheduledStop = nil
+		}
 		if err := s.client.Update(ctx, &wp); err != nil {
 			return err
 		}
@@ -275,9 +496,92 @@ func (s *WorkSpaceService) StopSpace(ctx context.Context, req *pb.RequestStop) (
 		return res, status.Error(codes.NotFound, WorkspaceNotExist)
 	}
 
+	// 4.通知IDE工作空间将在计划的时间停止, 立即停止时由PodReconciler在Pod删除时通知
+	if scheduled != nil {
+		s.notifier.Notice(req.Sid, &notifier.Notice{
+			Type:     notifier.NoticeStop,
+			Message:  fmt.Sprintf("workspace will be stopped at %s", scheduled.Format(time.RFC3339)),
+			Deadline: scheduled.Unix(),
+		})
+		res.ScheduledStopTime = scheduled.Unix()
+	}
+
 	return res, nil
 }
 
+// CancelStop 取消计划的停止
+func (s *WorkSpaceService) CancelStop(ctx context.Context, req *pb.RequestCancelStop) (*pb.ResponseCancelStop, error) {
+	res := &pb.ResponseCancelStop{}
+	key := client.ObjectKey{Name: workspaceName(req.Uid, req.Sid), Namespace: s.namespace}
+
+	scheduled := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var ws mv1.WorkSpace
+		if err := s.client.Get(ctx, key, &ws); err != nil {
+			return err
+		}
+		if ws.Spec.ScheduledStop == nil || ws.Spec.Command != mv1.WorkSpaceStart {
+			scheduled = false
+			return nil
+		}
+
+		ws.Spec.ScheduledStop = nil
+		return s.client.Update(ctx, &ws)
+	})
+	if errors.IsNotFound(err) {
+		res.Status = pb.ResponseCancelStop_NotFound
+		res.Message = WorkspaceNotExist
+		return res, status.Error(codes.NotFound, WorkspaceNotExist)
+	}
+	if err != nil {
+		s.logger.Error(err, "cancel stop")
+		res.Status = pb.ResponseCancelStop_Error
+		res.Message = err.Error()
+		return res, status.Error(codes.Unknown, err.Error())
+	}
+	if !scheduled {
+		res.Status = pb.ResponseCancelStop_NotScheduled
+		res.Message = WorkspaceNotScheduled
+		return res, status.Error(codes.FailedPrecondition, WorkspaceNotScheduled)
+	}
+
+	// 撤销之前推送给IDE的停止通知
+	s.notifier.Notice(req.Sid, nil)
+
+	return res, nil
+}
+
+// 工作空间当前的状态, 正在删除的工作空间的状态可能还没有更新为Deleting
+func workspacePhase(ws *mv1.WorkSpace) mv1.WorkSpacePhase {
+	if ws.DeletionTimestamp != nil {
+		return mv1.WorkspacePhaseDeleting
+	}
+
+	return lifecycle.Phase(ws)
+}
+
+// 通过状态机修改工作空间的状态并记录事件
+func (s *WorkSpaceService) transition(ctx context.Context, key client.ObjectKey, phase mv1.WorkSpacePhase) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var ws mv1.WorkSpace
+		if err := s.client.Get(ctx, key, &ws); err != nil {
+			return err
+		}
+
+		from := lifecycle.Phase(&ws)
+		changed, err := lifecycle.Transition(&ws, phase)
+		if err != nil || !changed {
+			return err
+		}
+		if err := s.client.Status().Update(ctx, &ws); err != nil {
+			return err
+		}
+		lifecycle.Record(s.recorder, &ws, from, phase)
+
+		return nil
+	})
+}
+
 // RunningWorkspaces 获取运行中的Workspace
 func (s *WorkSpaceService) RunningWorkspaces(ctx context.Context, req *pb.RequestRunningWorkspaces) (*pb.ResponseRunningWorkspace, error) {
 	res := &pb.ResponseRunningWorkspace{}
@@ -290,7 +594,7 @@ func (s *WorkSpaceService) RunningWorkspaces(ctx context.Context, req *pb.Reques
 
 	// 过滤出正在运行中的Workspace
 	for _, item := range wss.Items {
-		if item.Status.Phase == mv1.WorkspacePhaseStaring || item.Status.Phase == mv1.WorkspacePhaseRunning {
+		if item.Status.Phase == mv1.WorkspacePhaseStarting || item.Status.Phase == mv1.WorkspacePhaseRunning {
 			res.Workspaces = append(res.Workspaces, &pb.ResponseRunningWorkspace_WorkspaceBasicInfo{
 				Sid:  item.Spec.SID,
 				Name: item.Name,
@@ -315,8 +619,7 @@ func (s *WorkSpaceService) checkWorkspaceExist(ctx context.Context, key client.O
 }
 
 func (s *WorkSpaceService) constructWorkspace(space *pb.RequestCreate, name string) *mv1.WorkSpace {
-	hardware := fmt.Sprintf("%sC%s%s", space.ResourceLimit.Cpu,
-		strings.Split(space.ResourceLimit.Memory, "i")[0], strings.Split(space.ResourceLimit.Storage, "i")[0])
+	hardware := mv1.HardwareDescription(space.ResourceLimit.Cpu, space.ResourceLimit.Memory, space.ResourceLimit.Storage)
 	return &mv1.WorkSpace{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "cloud-ide.mangohow.com/v1",
@@ -331,17 +634,26 @@ func (s *WorkSpaceService) constructWorkspace(space *pb.RequestCreate, name stri
 			},
 		},
 		Spec: mv1.WorkSpaceSpec{
-			UID:           space.Uid,
-			SID:           space.Sid,
-			Cpu:           space.ResourceLimit.Cpu,
-			Memory:        space.ResourceLimit.Memory,
-			Storage:       space.ResourceLimit.Storage,
-			Hardware:      hardware,
-			Image:         space.Image,
-			Port:          space.Port,
-			MountPath:     space.VolumeMountPath,
-			GitRepository: space.GitRepository,
-			Command:       mv1.WorkSpaceStart,
+			UID:             space.Uid,
+			SID:             space.Sid,
+			Cpu:             space.ResourceLimit.Cpu,
+			Memory:          space.ResourceLimit.Memory,
+			Storage:         space.ResourceLimit.Storage,
+			Hardware:        hardware,
+			Image:           space.Image,
+			Port:            space.Port,
+			MountPath:       space.VolumeMountPath,
+			GitRepository:   space.GitRepository,
+			GitRef:          space.GitRef,
+			GitDepth:        space.GitDepth,
+			Repositories:    toGitRepositories(space.Repositories),
+			Dotfiles:        toGitRepository(space.Dotfiles),
+			Hooks:           toLifecycleHooks(space.Hooks),
+			Schedule:        toWorkspaceSchedule(space.Schedule),
+			Scheduling:      toWorkspaceScheduling(space.ResourceLimit.Scheduling),
+			Egress:          toWorkspaceEgress(space.Egress),
+			ImagePullSecret: space.ImagePullSecret,
+			Command:         mv1.WorkSpaceStart,
 		},
 	}
 }
@@ -356,15 +668,29 @@ func (s *WorkSpaceService) validateRequestCreate(req *pb.RequestCreate) error {
 	if req.Port < 1024 || req.Port > 65535 {
 		return fmt.Errorf("port invalid, port must be [1024,65535], now is%d", req.Port)
 	}
-	if req.GitRepository != "" {
-		matched, err := regexp.MatchString(`^https://\S+.git$`, req.GitRepository)
-		if err != nil {
-			s.logger.Error(err, "regexp")
-			return err
-		}
-		if !matched {
-			return fmt.Errorf("git repository invalid")
-		}
+	if req.GitRepository != "" && !utils.VerifyGitRepository(req.GitRepository) {
+		return fmt.Errorf("git repository invalid")
+	}
+	if req.GitRef != "" && !utils.VerifyGitRef(req.GitRef) {
+		return fmt.Errorf("git ref invalid")
+	}
+	if req.GitDepth < 0 {
+		return fmt.Errorf("git depth invalid, must be >= 0, now is %d", req.GitDepth)
+	}
+	if err := validateGitCredentials(req.GitCredentials); err != nil {
+		return err
+	}
+	if err := validateRepositories(req); err != nil {
+		return err
+	}
+	if err := validateHooks(req.Hooks); err != nil {
+		return err
+	}
+	if err := validateSchedule(req.Schedule); err != nil {
+		return err
+	}
+	if err := validateEgress(req.Egress); err != nil {
+		return err
 	}
 	matched, err := regexp.MatchString(`^\/(?:[\w-]+\/)*(?:[\w-]+\.[\w-]+|[\w-]+\/?)$`, req.VolumeMountPath)
 	if err != nil {
@@ -374,6 +700,9 @@ func (s *WorkSpaceService) validateRequestCreate(req *pb.RequestCreate) error {
 	if !matched {
 		return fmt.Errorf("mount path invalid")
 	}
+	if err := validateEnvs(req.Envs); err != nil {
+		return err
+	}
 
 	return s.validateResourceLimit(req.ResourceLimit)
 }
@@ -394,7 +723,30 @@ func (s *WorkSpaceService) validateResourceLimit(limit *pb.ResourceLimit) error
 		return fmt.Errorf("resource limit storage invalid %s", err.Error())
 	}
 
-	return nil
+	return validateScheduling(limit.Scheduling)
+}
+
+// 保留天数不大于0时立即删除数据, 指定了保留截止时间时使用webserver回收站中记录的时间
+func toWorkspaceRetention(retention pb.RequestDelete_Retention, retainDays int32, retainUntil int64) *mv1.WorkspaceRetention {
+	if retainDays <= 0 {
+		return nil
+	}
+
+	var policy mv1.RetentionPolicy
+	switch retention {
+	case pb.RequestDelete_Retain:
+		policy = mv1.RetentionRetain
+	case pb.RequestDelete_Snapshot:
+		policy = mv1.RetentionSnapshot
+	default:
+		return nil
+	}
+	until := metav1.NewTime(time.Now().AddDate(0, 0, int(retainDays)).Truncate(time.Second))
+	if retainUntil > 0 {
+		until = metav1.NewTime(time.Unix(retainUntil, 0))
+	}
+
+	return &mv1.WorkspaceRetention{Policy: policy, RetainUntil: &until}
 }
 
 func workspaceName(uid, sid string) string {
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	mv1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	"github.com/mangohow/cloud-ide/pkg/notifier"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeNotifier struct {
+	notices []*notifier.Notice
+}
+
+func (n *fakeNotifier) Login(sid, endpoint string) {}
+
+func (n *fakeNotifier) Logout(sid string) {}
+
+func (n *fakeNotifier) Notify(sid string) {}
+
+func (n *fakeNotifier) Notice(sid string, notice *notifier.Notice) {
+	n.notices = append(n.notices, notice)
+}
+
+func (n *fakeNotifier) NoticeSource(sid string) (string, string) {
+	return "", ""
+}
+
+// 等待Pod可用时直接失败, 取消停止时不应该等待Pod
+type failWaiter struct{}
+
+func (failWaiter) WaitFor(ctx context.Context, sid string) error {
+	return context.DeadlineExceeded
+}
+
+func TestStartSpaceCancelsStop(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := mv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ws := &mv1.WorkSpace{
+		ObjectMeta: metav1.ObjectMeta{Name: workspaceName("user01", "space01"), Namespace: "cloud-ide-ws"},
+		Spec: mv1.WorkSpaceSpec{
+			UID:     "user01",
+			SID:     "space01",
+			Command: mv1.WorkSpaceStart,
+		},
+		Status: mv1.WorkSpaceStatus{Phase: mv1.WorkspacePhaseRunning},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ws).Build()
+	ntf := &fakeNotifier{}
+	s := NewWorkSpaceService(c, c, logr.Discard(), failWaiter{}, ntf, nil, "cloud-ide-ws")
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(ws)
+
+	tests := []struct {
+		name  string
+		delay int32
+	}{
+		{name: "scheduled stop", delay: 60},
+		// 停止通知的宽限时间内Pod还没有删除, 工作空间仍为Running
+		{name: "stop notice", delay: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.StopSpace(ctx, &pb.RequestStop{Uid: "user01", Sid: "space01", DelaySeconds: tt.delay}); err != nil {
+				t.Fatalf("stop: %v", err)
+			}
+			var got mv1.WorkSpace
+			if err := c.Get(ctx, key, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Spec.Command != mv1.WorkSpaceStop && got.Spec.ScheduledStop == nil {
+				t.Fatalf("stop not issued: %+v", got.Spec)
+			}
+
+			if _, err := s.StartSpace(ctx, &pb.RequestStart{Uid: "user01", Sid: "space01", ReuseConfig: true}); err != nil {
+				t.Fatalf("start: %v", err)
+			}
+			if err := c.Get(ctx, key, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Spec.Command != mv1.WorkSpaceStart || got.Spec.ScheduledStop != nil {
+				t.Errorf("workspace should keep running: %+v", got.Spec)
+			}
+			if got.Status.Phase != mv1.WorkspacePhaseRunning {
+				t.Errorf("phase = %s, want %s", got.Status.Phase, mv1.WorkspacePhaseRunning)
+			}
+		})
+	}
+
+	// 计划的停止被取消后撤销推送给IDE的通知
+	if len(ntf.notices) != 2 || ntf.notices[0] == nil || ntf.notices[1] != nil {
+		t.Errorf("notices = %v", ntf.notices)
+	}
+}
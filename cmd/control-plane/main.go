@@ -17,26 +17,34 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/controllers"
+	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/metrics"
 	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/rpc"
 	"github.com/mangohow/cloud-ide/cmd/control-plane/internal/service"
 	"github.com/mangohow/cloud-ide/pkg/notifier"
 	"github.com/mangohow/cloud-ide/pkg/proc"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	cloudidev1 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1"
+	cloudidev1beta2 "github.com/mangohow/cloud-ide/cmd/control-plane/internal/api/v1beta2"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +57,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(cloudidev1.AddToScheme(scheme))
+	utilruntime.Must(cloudidev1beta2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -56,11 +65,24 @@ func main() {
 	var (
 		metricsAddr          string
 		enableLeaderElection bool
+		enableWebhook        bool
 		probeAddr            string
 
-		gatewayToken   string
-		gatewayPath    string
-		gatewayService string
+		gatewayToken      string
+		gatewayPath       string
+		gatewayNoticePath string
+		gatewayService    string
+
+		devContainerFeatures string
+		devContainerImages   string
+		warmPool             string
+		dropCapabilities     string
+		seccompProfile       string
+		defaultEgressCIDRs   string
+		egressExceptCIDRs    string
+
+		grpcOptions    rpc.Options
+		tracingOptions = tracing.Options{ServiceName: "control-plane"}
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -68,15 +90,19 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	// 指定是否启用WorkSpace的准入webhook, 启用时需要将证书挂载到/tmp/k8s-webhook-server/serving-certs
+	flag.BoolVar(&enableWebhook, "webhook-enabled", false, "Enable the validating and mutating webhooks of WorkSpace.")
 
 	// 指定namespace
 	flag.StringVar(&controllers.WorkspaceNamespace, "ns", "cloud-ide-ws", "The namespace controller listened")
-	// 指定运行模式
+	// 指定运行模式, 资源的请求和限制在所有模式下都根据规格和超售比例设置
 	flag.StringVar(&controllers.Mode, "mode", controllers.ModeRelease, "The mode program running")
 	// 指定gateway的token，在下发配置时需要使用
 	flag.StringVar(&gatewayToken, "gateway-token", "", "specify gateway token")
 	// 指定gateway的访问路径
 	flag.StringVar(&gatewayPath, "gateway-path", "/internal/endpoint", "specify gateway path")
+	// 指定gateway中推送IDE通知的访问路径
+	flag.StringVar(&gatewayNoticePath, "gateway-notice-path", "/internal/notice", "specify gateway notice path")
 	// 指定gateway的service name
 	flag.StringVar(&gatewayService, "gateway-service", "cloud-ide-gateway-svc", "specify gateway service")
 	// 指定动态卷的storageClass
@@ -85,6 +111,44 @@ func main() {
 	flag.BoolVar(&controllers.DynamicStorageEnabled, "dynamic-storage-enabled", false, "specify dynamic storage enabled")
 	// 指定用于克隆git的初始化容器镜像
 	flag.StringVar(&controllers.GitClonerName, "git-cloner-image", "git-cloner", "specify git cloner images")
+	// 指定提供静态链接的busybox的镜像, 预热Pod和预拉取镜像的Pod使用其中的sleep
+	flag.StringVar(&controllers.SleepImage, "sleep-image", "busybox:1.36", "specify the image providing a static busybox, used to run sleep in warm pods and pre-pull pods")
+	// 指定停止工作空间时的宽限时间, 在宽限时间内IDE可以保存未保存的文件, 删除Pod之后preStop钩子也有相同的宽限时间
+	flag.DurationVar(&controllers.StopGracePeriod, "stop-grace-period", 30*time.Second, "specify the grace period for stopping workspace, applied to both the IDE stop notice and the pod termination, so a stop takes up to twice this period")
+	// 指定devcontainer.json中允许使用的feature, 多个feature使用逗号分隔
+	flag.StringVar(&devContainerFeatures, "devcontainer-features", "", "specify devcontainer features allowed, separated by commas")
+	// 指定devcontainer.json中允许覆盖模板镜像的镜像, 以*结尾时匹配前缀, 为空时不允许覆盖
+	flag.StringVar(&devContainerImages, "devcontainer-images", "", "specify images devcontainer.json can use instead of the template image, separated by commas, a trailing * matches a prefix")
+	// 指定CPU和内存的超售比例, 工作空间请求的资源为规格中的资源除以超售比例
+	flag.Float64Var(&controllers.CpuOvercommitRatio, "cpu-overcommit-ratio", 2, "specify the cpu overcommit ratio, cpu request = cpu limit / ratio")
+	flag.Float64Var(&controllers.MemoryOvercommitRatio, "memory-overcommit-ratio", 1, "specify the memory overcommit ratio, memory request = memory limit / ratio")
+	// 指定工作空间容器可以使用的临时存储, 为空时不限制
+	flag.StringVar(&controllers.EphemeralStorageLimit, "ephemeral-storage-limit", "", "specify the ephemeral storage limit of workspace container")
+	// 指定预热Pod的镜像和数量, 格式为 image=size,image=size, 为空时不使用预热Pod
+	// 预热Pod只使工作空间的Pod优先调度到缓存了镜像的节点上, 已经预拉取到所有节点的镜像不会创建预热Pod
+	flag.StringVar(&warmPool, "warm-pool", "", "specify the images and sizes of warm pods, e.g. image=size,image=size, workspace pods prefer the nodes of warm pods, images pre-pulled to all nodes are skipped")
+	// 指定工作空间Pod的安全配置
+	flag.BoolVar(&controllers.RunAsNonRoot, "run-as-non-root", false, "specify whether workspace containers must run as non-root user")
+	flag.Int64Var(&controllers.RunAsUser, "run-as-user", 0, "specify the user id to run workspace containers, 0 means the user of image")
+	flag.StringVar(&dropCapabilities, "drop-capabilities", "NET_RAW", "specify the capabilities dropped from workspace containers, separated by commas")
+	flag.StringVar(&seccompProfile, "seccomp-profile", "RuntimeDefault", "specify the seccomp profile of workspace pods, RuntimeDefault, Unconfined or Localhost/<profile>")
+	flag.StringVar(&controllers.RuntimeClassName, "runtime-class-name", "", "specify the runtime class of workspace pods, e.g. gvisor or kata")
+	// 指定是否为工作空间创建网络策略, 以及允许访问工作空间的gateway所在的命名空间
+	flag.BoolVar(&controllers.NetworkPolicyEnabled, "network-policy-enabled", true, "specify whether to create network policy for workspace")
+	flag.StringVar(&controllers.GatewayNamespace, "gateway-namespace", "cloud-ide", "specify the namespace of gateway which is allowed to access workspaces")
+	// 指定模板没有配置出站白名单时允许访问的地址段, 以及出站时排除的集群内部地址段
+	flag.StringVar(&defaultEgressCIDRs, "default-egress-cidrs", strings.Join(controllers.DefaultEgressCIDRs, ","), "specify the cidrs workspaces can access when the template has no egress allowlist, separated by commas")
+	flag.StringVar(&egressExceptCIDRs, "egress-except-cidrs", strings.Join(controllers.EgressExceptCIDRs, ","), "specify the internal cidrs excluded from egress unless allowed explicitly, separated by commas")
+	// 指定删除工作空间时创建快照使用的VolumeSnapshotClass, 为空时使用默认的VolumeSnapshotClass
+	flag.StringVar(&controllers.VolumeSnapshotClassName, "volume-snapshot-class", "", "specify the volume snapshot class used when deleting workspace with snapshot retention")
+	// grpc服务的认证, 可以使用共享的token或者mTLS校验webserver客户端
+	flag.StringVar(&grpcOptions.Token, "grpc-token", "", "specify the token the webserver must present when calling grpc, empty means no token")
+	flag.StringVar(&grpcOptions.CertFile, "grpc-tls-cert", "", "specify the certificate file of grpc server, empty means plaintext")
+	flag.StringVar(&grpcOptions.KeyFile, "grpc-tls-key", "", "specify the private key file of grpc server")
+	flag.StringVar(&grpcOptions.ClientCAFile, "grpc-client-ca", "", "specify the ca file to verify webserver client certificates, enables mTLS")
+	// 链路追踪, span通过OTLP/HTTP导出到collector
+	flag.StringVar(&tracingOptions.Endpoint, "otlp-endpoint", "", "specify the OTLP/HTTP endpoint spans are exported to, e.g. http://otel-collector:4318, empty means no export")
+	flag.Float64Var(&tracingOptions.SampleRatio, "trace-sample-ratio", 1, "specify the ratio of requests sampled when the caller has not decided")
 
 	opts := zap.Options{
 		Development: true,
@@ -95,6 +159,55 @@ func main() {
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
 
+	if devContainerFeatures != "" {
+		controllers.DevContainerFeatures = strings.Split(devContainerFeatures, ",")
+	}
+	if devContainerImages != "" {
+		controllers.DevContainerImages = strings.Split(devContainerImages, ",")
+	}
+
+	controllers.DropCapabilities = nil
+	for _, c := range strings.Split(dropCapabilities, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			controllers.DropCapabilities = append(controllers.DropCapabilities, strings.ToUpper(c))
+		}
+	}
+
+	var err error
+	if controllers.SeccompProfile, err = controllers.ParseSeccompProfile(seccompProfile); err != nil {
+		logger.Error(err, "invalid seccomp profile")
+		os.Exit(1)
+	}
+	if controllers.DefaultEgressCIDRs, err = controllers.ParseCIDRs(defaultEgressCIDRs); err != nil {
+		logger.Error(err, "invalid default egress cidrs")
+		os.Exit(1)
+	}
+	if controllers.EgressExceptCIDRs, err = controllers.ParseCIDRs(egressExceptCIDRs); err != nil {
+		logger.Error(err, "invalid egress except cidrs")
+		os.Exit(1)
+	}
+	if controllers.RunAsUser < 0 {
+		logger.Error(nil, "run-as-user must be >= 0")
+		os.Exit(1)
+	}
+
+	warmPoolSizes, err := controllers.ParseWarmPool(warmPool)
+	if err != nil {
+		logger.Error(err, "invalid warm pool")
+		os.Exit(1)
+	}
+
+	if controllers.CpuOvercommitRatio < 1 || controllers.MemoryOvercommitRatio < 1 {
+		logger.Error(nil, "overcommit ratio must be >= 1")
+		os.Exit(1)
+	}
+	if controllers.EphemeralStorageLimit != "" {
+		if _, err := resource.ParseQuantity(controllers.EphemeralStorageLimit); err != nil {
+			logger.Error(err, "invalid ephemeral storage limit")
+			os.Exit(1)
+		}
+	}
+
 	if gatewayToken == "" {
 		logger.Error(nil, "must specify gateway token")
 		os.Exit(1)
@@ -102,6 +215,8 @@ func main() {
 
 	logger.Info("watched namespace", "namespace", controllers.WorkspaceNamespace)
 	logger.Info("running mode", "mode", controllers.Mode)
+	logger.Info("overcommit ratio", "cpu", controllers.CpuOvercommitRatio, "memory", controllers.MemoryOvercommitRatio)
+	logger.Info("security", "runAsNonRoot", controllers.RunAsNonRoot, "runtimeClass", controllers.RuntimeClassName, "networkPolicy", controllers.NetworkPolicyEnabled)
 	logger.Info("dynamic storage enabled", "value", controllers.DynamicStorageEnabled, "StorageClassName", controllers.StorageClassName)
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
@@ -131,26 +246,70 @@ func main() {
 
 	ctx := proc.SetupSignalHandler()
 
-	if err = controllers.NewWorkSpaceReconciler(
-		mgr.GetClient(),
-		mgr.GetScheme()).
-		SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create  controller", "controller", "WorkSpace")
+	shutdownTracing, err := tracing.Init(tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to init tracing")
 		os.Exit(1)
 	}
+	logger.Info("tracing", "endpoint", tracingOptions.Endpoint, "sampleRatio", tracingOptions.SampleRatio)
 
-	ntf, err := notifier.NewWorkspaceNotifier(ctx, logger, gatewayService, gatewayPath, gatewayToken, 8)
+	// 为常用的模板镜像维护预热Pod, 加快工作空间的启动
+	var pool *controllers.WarmPool
+	if len(warmPoolSizes) > 0 {
+		pool = controllers.NewWarmPool(mgr.GetClient(), logger, controllers.WorkspaceNamespace, warmPoolSizes)
+		if err := mgr.Add(pool); err != nil {
+			setupLog.Error(err, "unable to set up warm pool")
+			os.Exit(1)
+		}
+	}
+
+	// 通过exec从临时Pod的存储卷中读取仓库中的devcontainer.json
+	executor, err := controllers.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create pod executor")
+		os.Exit(1)
+	}
+
+	ntf, err := notifier.NewWorkspaceNotifier(ctx, logger, gatewayService, controllers.GatewayNamespace, gatewayPath, gatewayNoticePath, gatewayToken, 8)
 	if err != nil {
 		panic(err)
 	}
+
+	if err = controllers.NewWorkSpaceReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		pool,
+		executor,
+		ntf,
+		mgr.GetEventRecorderFor("workspace-controller")).
+		SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create  controller", "controller", "WorkSpace")
+		os.Exit(1)
+	}
 	if err = controllers.NewPodReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
 		ntf,
+		mgr.GetEventRecorderFor("workspace-pod-controller"),
 	).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pod")
 		os.Exit(1)
 	}
+	// 将模板镜像预先拉取到所有节点上
+	if err = controllers.NewImagePrePullReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+	).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ImagePrePull")
+		os.Exit(1)
+	}
+	// 校验和补全通过任意方式创建的工作空间, 同时注册v1beta2与v1之间的转换webhook
+	if enableWebhook {
+		if err = (&cloudidev1.WorkSpace{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "WorkSpace")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -163,14 +322,38 @@ func main() {
 	}
 
 	// 将grpc交由manager管理,manager会调用Start方法启动
-	if err := mgr.Add(rpc.New(":6387", logger, service.NewWorkSpaceService(mgr.GetClient(), logger, ntf, controllers.WorkspaceNamespace))); err != nil {
+	wsService := service.NewWorkSpaceService(mgr.GetClient(), mgr.GetAPIReader(), logger, ntf, ntf,
+		mgr.GetEventRecorderFor("workspace-service"), controllers.WorkspaceNamespace)
+	if err := mgr.Add(rpc.New(":6387", logger, wsService, grpcOptions)); err != nil {
 		setupLog.Error(err, "unable to set up grpc server")
 		os.Exit(1)
 	}
 
+	// 定时启动和停止工作空间
+	if err := mgr.Add(service.NewWorkspaceScheduler(wsService)); err != nil {
+		setupLog.Error(err, "unable to set up workspace scheduler")
+		os.Exit(1)
+	}
+
+	// 统计各个状态的工作空间数量, 与其它自定义指标一起通过metrics接口暴露
+	ctrlmetrics.Registry.MustRegister(metrics.NewWorkspaceCollector(mgr.GetClient(), controllers.WorkspaceNamespace))
+
+	// 定时删除超过保留时间的存储卷和快照
+	if err := mgr.Add(controllers.NewRetentionCollector(mgr.GetClient(), logger, controllers.WorkspaceNamespace)); err != nil {
+		setupLog.Error(err, "unable to set up retention collector")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// 导出剩余的span
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "shutdown tracing")
+	}
 }
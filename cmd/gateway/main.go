@@ -29,6 +29,7 @@ var (
 	serverKey         string
 	webSvcName        string
 	webPort           int
+	traceSampleRatio  float64
 )
 
 func main() {
@@ -70,6 +71,7 @@ func parseFlags(gomaxprocs int) (*tmpl.Config, error) {
 	flag.StringVar(&serverKey, "server-key", "", "specify ssl certificate key")
 	flag.StringVar(&webSvcName, "web-service-name", "cloud-ide-web-svc.cloud-ide.svc.cluster.local", "specify the service of the web to reverse proxy, fully qualified domain names must be written")
 	flag.IntVar(&webPort, "web-port", 8088, "specify the port of the web to reverse proxy")
+	flag.Float64Var(&traceSampleRatio, "trace-sample-ratio", 1, "specify the ratio of requests sampled when the gateway starts a trace")
 	flag.Parse()
 
 	cfg := &tmpl.Config{}
@@ -130,6 +132,12 @@ func parseFlags(gomaxprocs int) (*tmpl.Config, error) {
 	cfg.WebServiceName = webSvcName
 	cfg.WebPort = webPort
 
+	if traceSampleRatio < 0 || traceSampleRatio > 1 {
+		slog.Error("set trace sample ratio", "error", "must be in [0,1]")
+		return nil, errors.New("trace sample ratio invalid")
+	}
+	cfg.TraceSampleRatio = traceSampleRatio
+
 	return cfg, nil
 }
 
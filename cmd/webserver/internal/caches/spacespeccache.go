@@ -28,23 +28,26 @@ func newSpecCache(dao *dao.SpaceTemplateDao) *SpecCache {
 		defer ticker.Stop()
 		for {
 			<-ticker.C
-			s.refresh()
+			s.Refresh()
 		}
 	}()
 
 	return s
 }
 
-func (c *SpecCache) refresh() {
+// Refresh 从mysql中重新加载规格, 在规格被修改后调用可以使修改立即生效
+func (c *SpecCache) Refresh() error {
 	specs, err := c.dao.GetAllSpec()
 	if err != nil {
-		return
+		return err
 	}
 	m := make(map[string]interface{}, len(specs))
 	for i, _ := range specs {
 		m[strconv.Itoa(int(specs[i].Id))] = &specs[i]
 	}
 	c.cache.Replace(m)
+
+	return nil
 }
 
 func (c *SpecCache) LoadCache() {
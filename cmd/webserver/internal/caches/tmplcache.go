@@ -26,7 +26,7 @@ func newTmplCache(dao *dao.SpaceTemplateDao) *TmplCache {
 		defer ticker.Stop()
 		for {
 			<-ticker.C
-			t.LoadCache()
+			t.Refresh()
 		}
 	}()
 
@@ -39,10 +39,17 @@ const (
 )
 
 func (t *TmplCache) LoadCache() {
-	tmpls, err := t.dao.GetAllUsingTmpl()
-	if err != nil {
+	if err := t.Refresh(); err != nil {
 		panic(err)
 	}
+}
+
+// Refresh 从mysql中重新加载模板和类别, 在模板被修改后调用可以使修改立即生效
+func (t *TmplCache) Refresh() error {
+	tmpls, err := t.dao.GetAllAvailableTmpl()
+	if err != nil {
+		return err
+	}
 
 	tpls := make(map[uint32]*model.SpaceTemplate, len(tmpls))
 
@@ -50,18 +57,21 @@ func (t *TmplCache) LoadCache() {
 		tp := tmpls[i]
 		tpls[tp.Id] = &tp
 	}
-	t.cache.Set(TmplsKey, tpls)
 
 	kinds, err := t.dao.GetAllTmplKind()
 	if err != nil {
-		panic(err)
+		return err
 	}
 	kds := make(map[uint32]*model.TmplKind, len(kinds))
 	for i := 0; i < len(kinds); i++ {
 		kd := kinds[i]
 		kds[kd.Id] = &kd
 	}
+
+	t.cache.Set(TmplsKey, tpls)
 	t.cache.Set(KindsKey, kds)
+
+	return nil
 }
 
 func (t *TmplCache) GetTmpl(key uint32) *model.SpaceTemplate {
@@ -75,10 +85,35 @@ func (t *TmplCache) GetTmpl(key uint32) *model.SpaceTemplate {
 		return nil
 	}
 
-	tmpl := *(tps[key])
+	tp, ok := tps[key]
+	if !ok {
+		return nil
+	}
+
+	tmpl := *tp
 	return &tmpl
 }
 
+func (t *TmplCache) GetKind(key uint32) *model.TmplKind {
+	item, ok := t.cache.Get(KindsKey)
+	if !ok {
+		return nil
+	}
+
+	kds, ok := item.(map[uint32]*model.TmplKind)
+	if !ok {
+		return nil
+	}
+
+	kd, ok := kds[key]
+	if !ok {
+		return nil
+	}
+
+	kind := *kd
+	return &kind
+}
+
 func (t *TmplCache) GetAllTmpl() []*model.SpaceTemplate {
 	get, ok := t.cache.Get(TmplsKey)
 	if !ok {
@@ -111,6 +146,7 @@ func (t *TmplCache) GetAllKinds() []*model.TmplKind {
 	for _, v := range items {
 		k := *v
 		kinds[i] = &k
+		i++
 	}
 
 	return kinds
@@ -48,6 +48,53 @@ const (
 	SpaceAlreadyExist
 	SpaceNotFound
 	ResourceExhausted
+
+	TmplCreateFailed
+	TmplUpdateFailed
+	TmplDeleteFailed
+	TmplNotFound
+	TmplInUse
+	TmplImageInvalid
+	TmplDeprecated
+	KindNotFound
+	KindInUse
+	SpecNotFound
+	SpecInUse
+	SpecInvalid
+	TmplImagePullCheckFailed
+	TmplReachMaxCount
+	EnvListFailed
+	EnvSetFailed
+	EnvDeleteFailed
+	EnvNotFound
+	EnvNameInvalid
+	EnvValueTooLong
+	EnvReachMaxCount
+	GitCredentialListFailed
+	GitCredentialSetFailed
+	GitCredentialDeleteFailed
+	GitCredentialNotFound
+	GitCredentialInvalid
+	DotfilesGetFailed
+	DotfilesSetFailed
+	DotfilesInvalid
+	HooksInvalid
+	SpaceStopDelayInvalid
+	SpaceStopCancelFailed
+	SpaceStopNotScheduled
+	ScheduleInvalid
+	ScheduleGetFailed
+	ScheduleSetFailed
+	TmplPrePullStatusFailed
+	TmplEgressInvalid
+	SpaceRestoreFailed
+	SpaceTrashExpired
+	SpaceVersionsFailed
+	SpaceUpgradeFailed
+	SpaceNoUpgrade
+	SpaceRollbackFailed
+	SpaceNoRollback
+	SpaceEventsFailed
 )
 
 type UserStatus uint32
@@ -95,6 +142,52 @@ var messageForCode = map[int]string{
 	SpaceAlreadyExist:           "工作空间已存在",
 	SpaceNotFound:               "未找到该工作空间",
 	ResourceExhausted:           "资源不足,无法启动工作空间",
+	TmplCreateFailed:            "创建失败",
+	TmplUpdateFailed:            "修改失败",
+	TmplDeleteFailed:            "删除失败",
+	TmplNotFound:                "未找到该模板",
+	TmplInUse:                   "该模板正在被工作空间使用,无法删除",
+	TmplImageInvalid:            "镜像格式不正确",
+	TmplDeprecated:              "该模板已弃用,请选择其它模板",
+	KindNotFound:                "未找到该模板类别",
+	KindInUse:                   "该类别下还有模板,无法删除",
+	SpecNotFound:                "未找到该规格",
+	SpecInUse:                   "该规格正在被工作空间使用,无法删除",
+	SpecInvalid:                 "规格的CPU、内存或存储格式不正确",
+	TmplImagePullCheckFailed:    "镜像拉取检查失败,请检查镜像地址和镜像仓库的账号密码",
+	TmplReachMaxCount:           "达到最大自定义模板创建上限,请删除其它模板后重试",
+	EnvListFailed:               "获取环境变量失败",
+	EnvSetFailed:                "设置环境变量失败",
+	EnvDeleteFailed:             "删除环境变量失败",
+	EnvNotFound:                 "未找到该环境变量",
+	EnvNameInvalid:              "环境变量名称只能包含字母、数字和下划线,且不能以数字开头",
+	EnvValueTooLong:             "环境变量的值过长",
+	EnvReachMaxCount:            "达到最大环境变量数量上限",
+	GitCredentialListFailed:     "获取git凭证失败",
+	GitCredentialSetFailed:      "设置git凭证失败",
+	GitCredentialDeleteFailed:   "删除git凭证失败",
+	GitCredentialNotFound:       "未找到该git凭证",
+	GitCredentialInvalid:        "git凭证格式不正确,https需要用户名和token,ssh需要PEM格式的私钥",
+	DotfilesGetFailed:           "获取dotfiles仓库失败",
+	DotfilesSetFailed:           "设置dotfiles仓库失败",
+	DotfilesInvalid:             "dotfiles仓库地址或者分支不合法",
+	HooksInvalid:                "生命周期钩子命令过长",
+	SpaceStopDelayInvalid:       "延迟停止的时间不能超过24小时",
+	SpaceStopCancelFailed:       "取消停止工作空间失败",
+	SpaceStopNotScheduled:       "工作空间没有计划停止",
+	ScheduleInvalid:             "定时表达式或者时区不合法",
+	ScheduleGetFailed:           "获取定时设置失败",
+	ScheduleSetFailed:           "设置定时启动和停止失败",
+	TmplPrePullStatusFailed:     "获取镜像预拉取状态失败",
+	TmplEgressInvalid:           "出站白名单的地址段或者端口不合法",
+	SpaceRestoreFailed:          "恢复工作空间失败",
+	SpaceTrashExpired:           "工作空间已超过保留时间,无法恢复",
+	SpaceVersionsFailed:         "获取模板版本失败",
+	SpaceUpgradeFailed:          "升级工作空间失败",
+	SpaceNoUpgrade:              "工作空间已经是最新版本",
+	SpaceRollbackFailed:         "回滚工作空间失败",
+	SpaceNoRollback:             "工作空间没有可以回滚的版本",
+	SpaceEventsFailed:           "获取工作空间事件失败",
 }
 
 func GetMessage(code int) string {
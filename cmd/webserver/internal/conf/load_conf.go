@@ -1,7 +1,9 @@
 package conf
 
 import (
+	"errors"
 	"flag"
+	"os"
 	"strings"
 
 	"github.com/mangohow/cloud-ide/pkg/conf"
@@ -9,12 +11,14 @@ import (
 )
 
 var (
-	ServerConfig conf.ServerConf
-	MysqlConfig  conf.MysqlConf
-	RedisConfig  conf.RedisConf
-	LoggerConfig conf.LoggerConf
-	GrpcConfig   conf.GrpcConf
-	EmailConfig  conf.EmailConf
+	ServerConfig    conf.ServerConf
+	MysqlConfig     conf.MysqlConf
+	RedisConfig     conf.RedisConf
+	LoggerConfig    conf.LoggerConf
+	GrpcConfig      conf.GrpcConf
+	EmailConfig     conf.EmailConf
+	RetentionConfig conf.RetentionConf
+	TracingConfig   conf.TracingConf
 )
 
 func LoadConf() error {
@@ -32,18 +36,33 @@ func LoadConf() error {
 	initLogConf()
 	initGrpcConf()
 	initEmailConf()
+	initRetentionConf()
+	initTracingConf()
 
 	parseFlags()
 
+	if ServerConfig.SecretKey == "" {
+		return errors.New("secret key is empty, specify it by -secret-key or " + SecretKeyEnv)
+	}
+
 	return nil
 }
 
+// SecretKeyEnv 指定加密密钥的环境变量, 优先级高于配置文件, 低于命令行参数
+const SecretKeyEnv = "CLOUD_IDE_SECRET_KEY"
+
 func initServerConf() {
 	ServerConfig = conf.ServerConf{
-		Host: viper.GetString("server.host"),
-		Port: viper.GetInt("server.port"),
-		Name: viper.GetString("server.name"),
-		Mode: viper.GetString("server.mode"),
+		Host:   viper.GetString("server.host"),
+		Port:   viper.GetInt("server.port"),
+		Name:   viper.GetString("server.name"),
+		Mode:   viper.GetString("server.mode"),
+		Admins: viper.GetStringSlice("server.admins"),
+
+		SecretKey: viper.GetString("server.secretKey"),
+	}
+	if key := os.Getenv(SecretKeyEnv); key != "" {
+		ServerConfig.SecretKey = key
 	}
 }
 
@@ -76,7 +95,13 @@ func initRedisConf() {
 }
 
 func initGrpcConf() {
-	GrpcConfig = conf.GrpcConf{Addr: viper.GetString("grpc.addr")}
+	GrpcConfig = conf.GrpcConf{
+		Addr:     viper.GetString("grpc.addr"),
+		Token:    viper.GetString("grpc.token"),
+		CAFile:   viper.GetString("grpc.caFile"),
+		CertFile: viper.GetString("grpc.certFile"),
+		KeyFile:  viper.GetString("grpc.keyFile"),
+	}
 }
 
 func initEmailConf() {
@@ -89,6 +114,20 @@ func initEmailConf() {
 	}
 }
 
+func initRetentionConf() {
+	RetentionConfig = conf.RetentionConf{
+		Policy: viper.GetString("retention.policy"),
+		Days:   viper.GetInt("retention.days"),
+	}
+}
+
+func initTracingConf() {
+	TracingConfig = conf.TracingConf{
+		Endpoint:    viper.GetString("tracing.endpoint"),
+		SampleRatio: viper.GetFloat64("tracing.sampleRatio"),
+	}
+}
+
 // 解析命令行参数
 func parseFlags() {
 	var (
@@ -102,6 +141,9 @@ func parseFlags() {
 		senderEmail    string
 		authCode       string
 		grpcAddr       string
+		grpcToken      string
+		secretKey      string
+		otlpEndpoint   string
 	)
 
 	flag.StringVar(&mode, "mode", "", "specify server running mode [dev, release]")
@@ -114,12 +156,18 @@ func parseFlags() {
 	flag.StringVar(&senderEmail, "email-sender", "", "specify sender email if email is enabled")
 	flag.StringVar(&authCode, "email-authcode", "", "specify email auth code if email is enabled")
 	flag.StringVar(&grpcAddr, "grpc-addr", "", "specify control plane grpc addr eg:cloud-ide-control-plane-svc:6387")
+	flag.StringVar(&grpcToken, "grpc-token", "", "specify the token presented to control plane grpc")
+	flag.StringVar(&secretKey, "secret-key", "", "specify the key used to encrypt user secrets")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "specify the OTLP/HTTP endpoint spans are exported to, e.g. http://otel-collector:4318")
 	flag.Parse()
 
 	setString(&ServerConfig.Mode, &mode)
 	setString(&EmailConfig.SenderEmail, &senderEmail)
 	setString(&EmailConfig.AuthCode, &authCode)
 	setString(&GrpcConfig.Addr, &grpcAddr)
+	setString(&GrpcConfig.Token, &grpcToken)
+	setString(&ServerConfig.SecretKey, &secretKey)
+	setString(&TracingConfig.Endpoint, &otlpEndpoint)
 	setString(&MysqlConfig.DataSourceName, &dataSourceName)
 	setString(&LoggerConfig.Level, &logLevel)
 	setString(&EmailConfig.Host, &emailHost)
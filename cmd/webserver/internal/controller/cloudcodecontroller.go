@@ -2,10 +2,11 @@ package controller
 
 import (
 	"net/http"
-	"regexp"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/code"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/service"
 	"github.com/mangohow/cloud-ide/pkg/logger"
@@ -39,7 +40,7 @@ func (c *CloudCodeController) CreateSpace(ctx *gin.Context) *serialize.Response
 	userId := utils.MustGet[uint32](ctx, "id")
 
 	// 3、调用service处理然后响应结果
-	space, err := c.spaceService.CreateWorkspace(req, userId)
+	space, err := c.spaceService.CreateWorkspace(ctx.Request.Context(), req, userId)
 	switch err {
 	case service.ErrNameDuplicate:
 		return serialize.Fail(code.SpaceCreateNameDuplicate)
@@ -49,6 +50,8 @@ func (c *CloudCodeController) CreateSpace(ctx *gin.Context) *serialize.Response
 		return serialize.Fail(code.SpaceCreateFailed)
 	case service.ErrReqParamInvalid:
 		return serialize.Error(http.StatusBadRequest)
+	case service.ErrTmplDeprecated:
+		return serialize.Fail(code.TmplDeprecated)
 	}
 
 	if err != nil {
@@ -70,14 +73,24 @@ func (c *CloudCodeController) creationCheck(ctx *gin.Context) *reqtype.SpaceCrea
 
 	c.logger.Debug(req)
 
-	if req.GitRepository != "" {
-		matched, err := regexp.MatchString(`^https://\S+.git$`, req.GitRepository)
-		if err != nil {
-			c.logger.Error("regexp invalid")
-			return nil
-		}
-		if !matched {
-			c.logger.Error("git repository invalid")
+	if req.GitRepository != "" && !utils.VerifyGitRepository(req.GitRepository) {
+		c.logger.Error("git repository invalid")
+		return nil
+	}
+	if req.GitRef != "" && !utils.VerifyGitRef(req.GitRef) {
+		c.logger.Error("git ref invalid")
+		return nil
+	}
+	if req.GitDepth < 0 {
+		return nil
+	}
+	if !req.Hooks.Valid() {
+		c.logger.Error("lifecycle hooks invalid")
+		return nil
+	}
+	for _, repo := range req.Repositories {
+		if !verifyRepository(&repo) {
+			c.logger.Errorf("repository %s invalid", repo.Url)
 			return nil
 		}
 	}
@@ -96,6 +109,23 @@ func (c *CloudCodeController) creationCheck(ctx *gin.Context) *reqtype.SpaceCrea
 	return &req
 }
 
+func verifyRepository(repo *model.GitRepository) bool {
+	if !utils.VerifyGitRepository(repo.Url) {
+		return false
+	}
+	if repo.Ref != "" && !utils.VerifyGitRef(repo.Ref) {
+		return false
+	}
+	if repo.Depth < 0 {
+		return false
+	}
+	if repo.Path != "" && !utils.VerifyRepositoryPath(repo.Path) {
+		return false
+	}
+
+	return true
+}
+
 // CreateSpaceAndStart 创建一个新的云空间并启动 method: POST path: /api/space_cas
 // Request Param: reqtype.SpaceCreateOption
 func (c *CloudCodeController) CreateSpaceAndStart(ctx *gin.Context) *serialize.Response {
@@ -107,7 +137,7 @@ func (c *CloudCodeController) CreateSpaceAndStart(ctx *gin.Context) *serialize.R
 	userId := utils.MustGet[uint32](ctx, "id")
 	uid := utils.MustGet[string](ctx, "uid")
 
-	space, err := c.spaceService.CreateAndStartWorkspace(req, userId, uid)
+	space, err := c.spaceService.CreateAndStartWorkspace(ctx.Request.Context(), req, userId, uid)
 	switch err {
 	case service.ErrNameDuplicate:
 		return serialize.Fail(code.SpaceCreateNameDuplicate)
@@ -125,6 +155,8 @@ func (c *CloudCodeController) CreateSpaceAndStart(ctx *gin.Context) *serialize.R
 		return serialize.Fail(code.SpaceAlreadyExist)
 	case service.ErrResourceExhausted:
 		return serialize.Fail(code.ResourceExhausted)
+	case service.ErrTmplDeprecated:
+		return serialize.Fail(code.TmplDeprecated)
 	}
 
 	if err != nil {
@@ -147,7 +179,7 @@ func (c *CloudCodeController) StartSpace(ctx *gin.Context) *serialize.Response {
 	userId := utils.MustGet[uint32](ctx, "id")
 	uid := utils.MustGet[string](ctx, "uid")
 
-	space, err := c.spaceService.StartWorkspace(req.Id, userId, uid)
+	space, err := c.spaceService.StartWorkspace(ctx.Request.Context(), req.Id, userId, uid)
 	switch err {
 	case service.ErrWorkSpaceNotExist:
 		return serialize.Fail(code.SpaceStartNotExist)
@@ -167,9 +199,9 @@ func (c *CloudCodeController) StartSpace(ctx *gin.Context) *serialize.Response {
 }
 
 // StopSpace 停止正在运行的云空间 method: PUT path: /api/workspace/stop
-// Request Param: sid
+// Request Param: reqtype.SpaceStopOption
 func (c *CloudCodeController) StopSpace(ctx *gin.Context) *serialize.Response {
-	var req reqtype.SpaceId
+	var req reqtype.SpaceStopOption
 	err := ctx.ShouldBind(&req)
 	if err != nil {
 		c.logger.Warnf("bind param error:%v", err)
@@ -179,7 +211,12 @@ func (c *CloudCodeController) StopSpace(ctx *gin.Context) *serialize.Response {
 	uid := utils.MustGet[string](ctx, "uid")
 	userId := utils.MustGet[uint32](ctx, "id")
 
-	err = c.spaceService.StopWorkspace(req.Id, userId, uid)
+	delay := time.Duration(req.DelayMinutes) * time.Minute
+	if delay > service.MaxStopDelay {
+		return serialize.Fail(code.SpaceStopDelayInvalid)
+	}
+
+	stopTime, err := c.spaceService.StopWorkspace(ctx.Request.Context(), req.Id, userId, uid, delay)
 	if err != nil {
 		if err == service.ErrWorkSpaceIsNotRunning {
 			return serialize.Ok()
@@ -188,9 +225,86 @@ func (c *CloudCodeController) StopSpace(ctx *gin.Context) *serialize.Response {
 		return serialize.Fail(code.SpaceStopFailed)
 	}
 
+	// 延迟停止时返回计划的停止时间
+	if stopTime != nil {
+		return serialize.OkData(struct {
+			StopTime time.Time `json:"stop_time"`
+		}{*stopTime})
+	}
+
 	return serialize.Ok()
 }
 
+// CancelStop 取消计划的停止 method: PUT path: /api/workspace/stop/cancel
+// Request Param: id
+func (c *CloudCodeController) CancelStop(ctx *gin.Context) *serialize.Response {
+	var req reqtype.SpaceId
+	if err := ctx.ShouldBind(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	uid := utils.MustGet[string](ctx, "uid")
+	userId := utils.MustGet[uint32](ctx, "id")
+
+	err := c.spaceService.CancelStop(ctx.Request.Context(), req.Id, userId, uid)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrStopNotScheduled:
+		return serialize.Fail(code.SpaceStopNotScheduled)
+	}
+
+	return serialize.Fail(code.SpaceStopCancelFailed)
+}
+
+// GetSchedule 获取工作空间的定时启动和停止 method: GET path: /api/workspace/schedule
+// Request Param: id
+func (c *CloudCodeController) GetSchedule(ctx *gin.Context) *serialize.Response {
+	var req reqtype.SpaceScheduleQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	info, err := c.spaceService.GetSchedule(req.Id, userId)
+	switch err {
+	case nil:
+		return serialize.OkData(info)
+	case service.ErrSpaceNotFound:
+		return serialize.Fail(code.SpaceNotFound)
+	}
+
+	return serialize.Fail(code.ScheduleGetFailed)
+}
+
+// SetSchedule 设置工作空间的定时启动和停止 method: PUT path: /api/workspace/schedule
+// Request Param: id, start, stop, timezone
+func (c *CloudCodeController) SetSchedule(ctx *gin.Context) *serialize.Response {
+	var req reqtype.SpaceScheduleOption
+	if err := ctx.ShouldBind(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	uid := utils.MustGet[string](ctx, "uid")
+	userId := utils.MustGet[uint32](ctx, "id")
+	schedule := model.SpaceSchedule{Start: req.Start, Stop: req.Stop, Timezone: req.Timezone}
+
+	err := c.spaceService.SetSchedule(ctx.Request.Context(), req.Id, userId, uid, schedule)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrScheduleInvalid:
+		return serialize.Fail(code.ScheduleInvalid)
+	case service.ErrSpaceNotFound:
+		return serialize.Fail(code.SpaceNotFound)
+	}
+
+	return serialize.Fail(code.ScheduleSetFailed)
+}
+
 // DeleteSpace 删除已存在的云空间  method: DELETE path: /api/workspace
 // Request Param: id
 func (c *CloudCodeController) DeleteSpace(ctx *gin.Context) *serialize.Response {
@@ -206,7 +320,7 @@ func (c *CloudCodeController) DeleteSpace(ctx *gin.Context) *serialize.Response
 	userId := utils.MustGet[uint32](ctx, "id")
 	uid := utils.MustGet[string](ctx, "uid")
 
-	err = c.spaceService.DeleteWorkspace(req.Id, userId, uid)
+	err = c.spaceService.DeleteWorkspace(ctx.Request.Context(), req.Id, userId, uid)
 	if err != nil {
 		if err == service.ErrWorkSpaceIsRunning {
 			return serialize.Fail(code.SpaceDeleteIsRunning)
@@ -224,7 +338,7 @@ func (c *CloudCodeController) ListSpace(ctx *gin.Context) *serialize.Response {
 	userId := utils.MustGet[uint32](ctx, "id")
 	uid := utils.MustGet[string](ctx, "uid")
 
-	spaces, err := c.spaceService.ListWorkspace(userId, uid)
+	spaces, err := c.spaceService.ListWorkspace(ctx.Request.Context(), userId, uid)
 	if err != nil {
 		return serialize.Fail(code.QueryFailed)
 	}
@@ -256,3 +370,144 @@ func (c *CloudCodeController) ModifySpaceName(ctx *gin.Context) *serialize.Respo
 		return serialize.Fail(code.SpaceNameModifyFailed)
 	}
 }
+
+// ListTrash 获取回收站中的工作空间 method: GET path: /api/workspace/trash
+func (c *CloudCodeController) ListTrash(ctx *gin.Context) *serialize.Response {
+	userId := utils.MustGet[uint32](ctx, "id")
+
+	spaces, err := c.spaceService.ListTrash(userId)
+	if err != nil {
+		return serialize.Fail(code.QueryFailed)
+	}
+
+	return serialize.OkData(spaces)
+}
+
+// RestoreSpace 从回收站中恢复工作空间 method: POST path: /api/workspace/restore
+// Request Param: reqtype.SpaceId
+func (c *CloudCodeController) RestoreSpace(ctx *gin.Context) *serialize.Response {
+	var req reqtype.SpaceId
+	err := ctx.ShouldBind(&req)
+	if err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+
+	err = c.spaceService.RestoreWorkspace(req.Id, userId)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrWorkSpaceNotExist:
+		return serialize.Fail(code.SpaceNotFound)
+	case service.ErrTrashExpired:
+		return serialize.Fail(code.SpaceTrashExpired)
+	case service.ErrReachMaxSpaceCount:
+		return serialize.Fail(code.SpaceCreateReachMaxCount)
+	case service.ErrNameDuplicate:
+		return serialize.Fail(code.SpaceCreateNameDuplicate)
+	default:
+		return serialize.Fail(code.SpaceRestoreFailed)
+	}
+}
+
+// SpaceVersions 获取工作空间使用的模板版本和模板的更新说明 method: GET path: /api/workspace/versions
+// Request Param: id
+func (c *CloudCodeController) SpaceVersions(ctx *gin.Context) *serialize.Response {
+	var req reqtype.IdQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	versions, err := c.spaceService.SpaceVersions(req.Id, userId)
+	switch err {
+	case nil:
+		return serialize.OkData(versions)
+	case service.ErrWorkSpaceNotExist:
+		return serialize.Fail(code.SpaceNotFound)
+	case service.ErrTmplNotFound:
+		return serialize.Fail(code.TmplNotFound)
+	}
+
+	return serialize.Fail(code.SpaceVersionsFailed)
+}
+
+// UpgradeSpace 将工作空间升级到模板的最新版本, 运行中的工作空间会重新启动 method: PUT path: /api/workspace/upgrade
+// Request Param: reqtype.SpaceId
+func (c *CloudCodeController) UpgradeSpace(ctx *gin.Context) *serialize.Response {
+	var req reqtype.SpaceId
+	if err := ctx.ShouldBind(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	uid := utils.MustGet[string](ctx, "uid")
+	err := c.spaceService.UpgradeWorkspace(ctx.Request.Context(), req.Id, userId, uid)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrNoUpgrade:
+		return serialize.Fail(code.SpaceNoUpgrade)
+	}
+
+	return c.versionFail(err, code.SpaceUpgradeFailed)
+}
+
+// RollbackSpace 将工作空间回滚到升级之前的版本 method: PUT path: /api/workspace/rollback
+// Request Param: reqtype.SpaceId
+func (c *CloudCodeController) RollbackSpace(ctx *gin.Context) *serialize.Response {
+	var req reqtype.SpaceId
+	if err := ctx.ShouldBind(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	uid := utils.MustGet[string](ctx, "uid")
+	err := c.spaceService.RollbackWorkspace(ctx.Request.Context(), req.Id, userId, uid)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrNoRollback:
+		return serialize.Fail(code.SpaceNoRollback)
+	}
+
+	return c.versionFail(err, code.SpaceRollbackFailed)
+}
+
+// SpaceEvents 获取工作空间的启动过程和失败原因 method: GET path: /api/workspace/events
+// Request Param: id
+func (c *CloudCodeController) SpaceEvents(ctx *gin.Context) *serialize.Response {
+	var req reqtype.IdQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		c.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	uid := utils.MustGet[string](ctx, "uid")
+	events, err := c.spaceService.SpaceEvents(ctx.Request.Context(), req.Id, userId, uid)
+	switch err {
+	case nil:
+		return serialize.OkData(events)
+	case service.ErrSpaceNotFound:
+		return serialize.Fail(code.SpaceNotFound)
+	}
+
+	return serialize.Fail(code.SpaceEventsFailed)
+}
+
+func (c *CloudCodeController) versionFail(err error, defaultCode int) *serialize.Response {
+	switch err {
+	case service.ErrWorkSpaceNotExist, service.ErrSpaceNotFound:
+		return serialize.Fail(code.SpaceNotFound)
+	case service.ErrTmplNotFound:
+		return serialize.Fail(code.TmplNotFound)
+	}
+
+	return serialize.Fail(defaultCode)
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/code"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/service"
+	"github.com/mangohow/cloud-ide/pkg/logger"
+	"github.com/mangohow/cloud-ide/pkg/serialize"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type GitCredentialController struct {
+	logger  *logrus.Logger
+	service *service.GitCredentialService
+}
+
+func NewGitCredentialController() *GitCredentialController {
+	return &GitCredentialController{
+		logger:  logger.Logger(),
+		service: service.NewGitCredentialService(),
+	}
+}
+
+// ListCredentials 获取git凭证 method: GET path: /api/git/credential/list
+func (g *GitCredentialController) ListCredentials(ctx *gin.Context) *serialize.Response {
+	userId := utils.MustGet[uint32](ctx, "id")
+	creds, err := g.service.ListCredentials(userId)
+	if err != nil {
+		return serialize.Fail(code.GitCredentialListFailed)
+	}
+
+	return serialize.OkData(creds)
+}
+
+// SetCredential 添加或修改git凭证 method: PUT path: /api/git/credential
+// Request Param: reqtype.GitCredentialOption
+func (g *GitCredentialController) SetCredential(ctx *gin.Context) *serialize.Response {
+	var req reqtype.GitCredentialOption
+	if err := ctx.ShouldBind(&req); err != nil {
+		g.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	err := g.service.SetCredential(&req, userId)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrGitCredentialInvalid:
+		return serialize.Fail(code.GitCredentialInvalid)
+	}
+
+	return serialize.Fail(code.GitCredentialSetFailed)
+}
+
+// DeleteCredential 删除git凭证 method: DELETE path: /api/git/credential
+// Request Param: id
+func (g *GitCredentialController) DeleteCredential(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		g.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	err := g.service.DeleteCredential(req.Id, userId)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrGitCredentialNotFound:
+		return serialize.Fail(code.GitCredentialNotFound)
+	}
+
+	return serialize.Fail(code.GitCredentialDeleteFailed)
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/code"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/service"
+	"github.com/mangohow/cloud-ide/pkg/logger"
+	"github.com/mangohow/cloud-ide/pkg/serialize"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type SpaceEnvController struct {
+	logger  *logrus.Logger
+	service *service.SpaceEnvService
+}
+
+func NewSpaceEnvController() *SpaceEnvController {
+	return &SpaceEnvController{
+		logger:  logger.Logger(),
+		service: service.NewSpaceEnvService(),
+	}
+}
+
+// ListEnvs 获取环境变量 method: GET path: /api/env/list
+// Request Param: space_id, 为0时获取用户的默认环境变量
+func (s *SpaceEnvController) ListEnvs(ctx *gin.Context) *serialize.Response {
+	var req reqtype.EnvQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	envs, err := s.service.ListEnvs(userId, req.SpaceId)
+	switch err {
+	case nil:
+		return serialize.OkData(envs)
+	case service.ErrWorkSpaceNotExist:
+		return serialize.Fail(code.SpaceNotFound)
+	}
+
+	return serialize.Fail(code.EnvListFailed)
+}
+
+// SetEnv 添加或修改环境变量 method: PUT path: /api/env
+// Request Param: reqtype.EnvOption
+func (s *SpaceEnvController) SetEnv(ctx *gin.Context) *serialize.Response {
+	var req reqtype.EnvOption
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	err := s.service.SetEnv(&req, userId)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrEnvNameInvalid:
+		return serialize.Fail(code.EnvNameInvalid)
+	case service.ErrEnvValueTooLong:
+		return serialize.Fail(code.EnvValueTooLong)
+	case service.ErrReachMaxEnvCount:
+		return serialize.Fail(code.EnvReachMaxCount)
+	case service.ErrWorkSpaceNotExist:
+		return serialize.Fail(code.SpaceNotFound)
+	}
+
+	return serialize.Fail(code.EnvSetFailed)
+}
+
+// DeleteEnv 删除环境变量 method: DELETE path: /api/env
+// Request Param: id
+func (s *SpaceEnvController) DeleteEnv(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	err := s.service.DeleteEnv(req.Id, userId)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrEnvNotFound:
+		return serialize.Fail(code.EnvNotFound)
+	}
+
+	return serialize.Fail(code.EnvDeleteFailed)
+}
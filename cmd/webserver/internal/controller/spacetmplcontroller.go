@@ -1,11 +1,16 @@
 package controller
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/code"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/service"
 	"github.com/mangohow/cloud-ide/pkg/logger"
 	"github.com/mangohow/cloud-ide/pkg/serialize"
+	"github.com/mangohow/cloud-ide/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
@@ -45,3 +50,284 @@ func (s *SpaceTmplController) SpaceSpecs(ctx *gin.Context) *serialize.Response {
 
 	return serialize.OkData(specs)
 }
+
+// CustomTmpls 获取用户自定义的模板 method: GET path:/api/template/custom/list
+func (s *SpaceTmplController) CustomTmpls(ctx *gin.Context) *serialize.Response {
+	userId := utils.MustGet[uint32](ctx, "id")
+
+	return serialize.OkData(s.service.GetCustomTmpls(userId))
+}
+
+// CreateCustomTmpl 使用自己的镜像创建模板 method: POST path:/api/template/custom
+// Request Param: reqtype.CustomTmplOption
+func (s *SpaceTmplController) CreateCustomTmpl(ctx *gin.Context) *serialize.Response {
+	var req reqtype.CustomTmplOption
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	uid := utils.MustGet[string](ctx, "uid")
+
+	tmpl, err := s.service.CreateCustomTmpl(&req, userId, uid)
+	switch err {
+	case nil:
+		return serialize.OkData(tmpl)
+	case service.ErrImagePullCheck:
+		return serialize.Fail(code.TmplImagePullCheckFailed)
+	case service.ErrReachMaxCustomTmplCount:
+		return serialize.Fail(code.TmplReachMaxCount)
+	}
+
+	return s.tmplFail(err, code.TmplCreateFailed)
+}
+
+// DeleteCustomTmpl 删除自定义的模板 method: DELETE path:/api/template/custom
+// Request Param: id
+func (s *SpaceTmplController) DeleteCustomTmpl(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	uid := utils.MustGet[string](ctx, "uid")
+
+	if err := s.service.DeleteCustomTmpl(req.Id, userId, uid); err != nil {
+		return s.tmplFail(err, code.TmplDeleteFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// AllTmpls 获取所有未删除的模板, 包括已弃用的模板 method: GET path:/api/admin/template/list
+func (s *SpaceTmplController) AllTmpls(ctx *gin.Context) *serialize.Response {
+	tmpls, kinds := s.service.GetAllTmpl()
+
+	return serialize.OkData(gin.H{
+		"tmpls": tmpls,
+		"kinds": kinds,
+	})
+}
+
+// PrePullStatus 获取模板镜像在各个节点上的预拉取状态 method: GET path:/api/admin/template/prepull
+func (s *SpaceTmplController) PrePullStatus(ctx *gin.Context) *serialize.Response {
+	status, err := s.service.PrePullStatus()
+	if err != nil {
+		return serialize.Fail(code.TmplPrePullStatusFailed)
+	}
+
+	return serialize.OkData(status)
+}
+
+// TmplVersions 获取模板的所有版本 method: GET path:/api/admin/template/versions
+// Request Param: id
+func (s *SpaceTmplController) TmplVersions(ctx *gin.Context) *serialize.Response {
+	var req reqtype.IdQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	versions, err := s.service.TmplVersions(req.Id)
+	if err != nil {
+		return s.tmplFail(err, code.QueryFailed)
+	}
+
+	return serialize.OkData(versions)
+}
+
+// CreateTmpl 创建空间模板 method: POST path:/api/admin/template
+// Request Param: model.SpaceTemplate
+func (s *SpaceTmplController) CreateTmpl(ctx *gin.Context) *serialize.Response {
+	var req model.SpaceTemplate
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	tmpl, err := s.service.CreateTmpl(&req)
+	if err != nil {
+		return s.tmplFail(err, code.TmplCreateFailed)
+	}
+
+	return serialize.OkData(tmpl)
+}
+
+// UpdateTmpl 修改空间模板 method: PUT path:/api/admin/template
+// Request Param: model.SpaceTemplate
+func (s *SpaceTmplController) UpdateTmpl(ctx *gin.Context) *serialize.Response {
+	var req model.SpaceTemplate
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.UpdateTmpl(&req); err != nil {
+		return s.tmplFail(err, code.TmplUpdateFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// DeprecateTmpl 弃用空间模板 method: PUT path:/api/admin/template/deprecate
+// Request Param: id
+func (s *SpaceTmplController) DeprecateTmpl(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.DeprecateTmpl(req.Id); err != nil {
+		return s.tmplFail(err, code.TmplUpdateFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// DeleteTmpl 删除空间模板 method: DELETE path:/api/admin/template
+// Request Param: id
+func (s *SpaceTmplController) DeleteTmpl(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.DeleteTmpl(req.Id); err != nil {
+		return s.tmplFail(err, code.TmplDeleteFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// CreateKind 创建模板类别 method: POST path:/api/admin/kind
+// Request Param: model.TmplKind
+func (s *SpaceTmplController) CreateKind(ctx *gin.Context) *serialize.Response {
+	var req model.TmplKind
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	kind, err := s.service.CreateKind(&req)
+	if err != nil {
+		return s.tmplFail(err, code.TmplCreateFailed)
+	}
+
+	return serialize.OkData(kind)
+}
+
+// UpdateKind 修改模板类别 method: PUT path:/api/admin/kind
+// Request Param: model.TmplKind
+func (s *SpaceTmplController) UpdateKind(ctx *gin.Context) *serialize.Response {
+	var req model.TmplKind
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.UpdateKind(&req); err != nil {
+		return s.tmplFail(err, code.TmplUpdateFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// DeleteKind 删除模板类别 method: DELETE path:/api/admin/kind
+// Request Param: id
+func (s *SpaceTmplController) DeleteKind(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.DeleteKind(req.Id); err != nil {
+		return s.tmplFail(err, code.TmplDeleteFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// CreateSpec 创建空间规格 method: POST path:/api/admin/spec
+// Request Param: model.SpaceSpec
+func (s *SpaceTmplController) CreateSpec(ctx *gin.Context) *serialize.Response {
+	var req model.SpaceSpec
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	spec, err := s.service.CreateSpec(&req)
+	if err != nil {
+		return s.tmplFail(err, code.TmplCreateFailed)
+	}
+
+	return serialize.OkData(spec)
+}
+
+// UpdateSpec 修改空间规格 method: PUT path:/api/admin/spec
+// Request Param: model.SpaceSpec
+func (s *SpaceTmplController) UpdateSpec(ctx *gin.Context) *serialize.Response {
+	var req model.SpaceSpec
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.UpdateSpec(&req); err != nil {
+		return s.tmplFail(err, code.TmplUpdateFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// DeleteSpec 删除空间规格 method: DELETE path:/api/admin/spec
+// Request Param: id
+func (s *SpaceTmplController) DeleteSpec(ctx *gin.Context) *serialize.Response {
+	var req reqtype.Id
+	if err := ctx.ShouldBind(&req); err != nil {
+		s.logger.Warnf("bind param error:%v", err)
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	if err := s.service.DeleteSpec(req.Id); err != nil {
+		return s.tmplFail(err, code.TmplDeleteFailed)
+	}
+
+	return serialize.Ok()
+}
+
+// tmplFail 将service返回的错误转换为响应, 未知错误使用defaultCode
+func (s *SpaceTmplController) tmplFail(err error, defaultCode int) *serialize.Response {
+	switch err {
+	case service.ErrReqParamInvalid:
+		return serialize.Error(http.StatusBadRequest)
+	case service.ErrTmplNotFound:
+		return serialize.Fail(code.TmplNotFound)
+	case service.ErrTmplInUse:
+		return serialize.Fail(code.TmplInUse)
+	case service.ErrImageInvalid:
+		return serialize.Fail(code.TmplImageInvalid)
+	case service.ErrHooksInvalid:
+		return serialize.Fail(code.HooksInvalid)
+	case service.ErrEgressInvalid:
+		return serialize.Fail(code.TmplEgressInvalid)
+	case service.ErrKindNotFound:
+		return serialize.Fail(code.KindNotFound)
+	case service.ErrKindInUse:
+		return serialize.Fail(code.KindInUse)
+	case service.ErrSpecNotFound:
+		return serialize.Fail(code.SpecNotFound)
+	case service.ErrSpecInUse:
+		return serialize.Fail(code.SpecInUse)
+	case service.ErrSpecInvalid:
+		return serialize.Fail(code.SpecInvalid)
+	}
+
+	return serialize.Fail(defaultCode)
+}
@@ -138,3 +138,34 @@ func (u *UserController) GetEmailValidateCode(ctx *gin.Context) *serialize.Respo
 
 	return serialize.Ok()
 }
+
+// GetDotfiles 获取用户的dotfiles仓库 method: GET path: /api/user/dotfiles
+func (u *UserController) GetDotfiles(ctx *gin.Context) *serialize.Response {
+	userId := utils.MustGet[uint32](ctx, "id")
+	dotfiles, err := u.service.GetDotfiles(userId)
+	if err != nil {
+		return serialize.Fail(code.DotfilesGetFailed)
+	}
+
+	return serialize.OkData(dotfiles)
+}
+
+// SetDotfiles 设置用户的dotfiles仓库, 仓库地址为空表示不使用 method: PUT path: /api/user/dotfiles
+// Request Param: model.Dotfiles
+func (u *UserController) SetDotfiles(ctx *gin.Context) *serialize.Response {
+	var req model.Dotfiles
+	if err := ctx.ShouldBind(&req); err != nil {
+		return serialize.Error(http.StatusBadRequest)
+	}
+
+	userId := utils.MustGet[uint32](ctx, "id")
+	err := u.service.SetDotfiles(userId, &req)
+	switch err {
+	case nil:
+		return serialize.Ok()
+	case service.ErrDotfilesInvalid:
+		return serialize.Fail(code.DotfilesInvalid)
+	}
+
+	return serialize.Fail(code.DotfilesSetFailed)
+}
@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao/db"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+)
+
+type GitCredentialDao struct {
+	db *sqlx.DB
+}
+
+func NewGitCredentialDao() *GitCredentialDao {
+	return &GitCredentialDao{
+		db: db.DB(),
+	}
+}
+
+func (d *GitCredentialDao) FindAllByUserId(userId uint32) (creds []*model.GitCredential, err error) {
+	sql := `SELECT id, host, type, username, known_hosts, create_time FROM t_git_credential WHERE user_id = ?`
+	err = d.db.Select(&creds, sql, userId)
+	return
+}
+
+func (d *GitCredentialDao) FindByUserIdAndHost(userId uint32, host string) (*model.GitCredential, error) {
+	sql := `SELECT id, host, type, username, secret, known_hosts FROM t_git_credential WHERE user_id = ? AND host = ?`
+	cred := &model.GitCredential{}
+	err := d.db.Get(cred, sql, userId, host)
+	return cred, err
+}
+
+// Upsert 添加凭证, 同一个主机的凭证会被覆盖
+func (d *GitCredentialDao) Upsert(cred *model.GitCredential) error {
+	sql := `INSERT INTO t_git_credential (user_id, host, type, username, secret, known_hosts, create_time) VALUES (?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE type = VALUES(type), username = VALUES(username), secret = VALUES(secret), known_hosts = VALUES(known_hosts)`
+	_, err := d.db.Exec(sql, cred.UserId, cred.Host, cred.Type, cred.Username, cred.Secret, cred.KnownHosts, cred.CreateTime)
+	return err
+}
+
+func (d *GitCredentialDao) DeleteByIdAndUserId(id, userId uint32) (int64, error) {
+	sql := `DELETE FROM t_git_credential WHERE id = ? AND user_id = ?`
+	res, err := d.db.Exec(sql, id, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
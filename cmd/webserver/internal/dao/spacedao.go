@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao/db"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
@@ -18,10 +20,11 @@ func NewSpaceDao() *SpaceDao {
 
 func (d *SpaceDao) Insert(space *model.Space) (uint32, error) {
 	sql := `INSERT INTO t_space 
-(user_id, tmpl_id, spec_id, sid, name, status, create_time, delete_time, stop_time, total_time, git_repository)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	res, err := d.db.Exec(sql, space.UserId, space.TmplId, space.SpecId, space.Sid, space.Name,
-		space.Status, space.CreateTime, space.DeleteTime, space.StopTime, space.TotalTime, space.GitRepository)
+(user_id, tmpl_id, tmpl_version, spec_id, sid, name, status, create_time, delete_time, retain_until, stop_time, total_time, git_repository, git_ref, git_depth, repositories, hooks, schedule)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	res, err := d.db.Exec(sql, space.UserId, space.TmplId, space.TmplVersion, space.SpecId, space.Sid, space.Name,
+		space.Status, space.CreateTime, space.DeleteTime, space.RetainUntil, space.StopTime, space.TotalTime, space.GitRepository,
+		space.GitRef, space.GitDepth, space.Repositories, space.Hooks, space.Schedule)
 	if err != nil {
 		return 0, err
 	}
@@ -31,26 +34,48 @@ VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 }
 
 // FindByUserIdAndName TODO 增加联合索引 idx_userid_name
-// 根据userid和name查询, 用于查询某个用户下的space名称是否重复
+// 根据userid和name查询, 用于查询某个用户下的space名称是否重复, 回收站中的space不计算在内
 func (d *SpaceDao) FindByUserIdAndName(userId uint32, name string) error {
-	sql := `SELECT id FROM t_space WHERE user_id = ? AND name = ? AND status != ?`
+	sql := `SELECT id FROM t_space WHERE user_id = ? AND name = ? AND status NOT IN (?, ?)`
 	var id uint32
-	return d.db.Get(&id, sql, userId, name, model.SpaceStatusDeleted)
+	return d.db.Get(&id, sql, userId, name, model.SpaceStatusDeleted, model.SpaceStatusTrash)
 }
 
 func (d *SpaceDao) FindCountByUserId(userId uint32) (count uint32, err error) {
-	sql := `SELECT COUNT(*) FROM t_space WHERE user_id = ? AND status != ?`
-	err = d.db.Get(&count, sql, userId, model.SpaceStatusDeleted)
+	sql := `SELECT COUNT(*) FROM t_space WHERE user_id = ? AND status NOT IN (?, ?)`
+	err = d.db.Get(&count, sql, userId, model.SpaceStatusDeleted, model.SpaceStatusTrash)
 
 	return
 }
 
 func (d *SpaceDao) FindAllSpaceByUserId(userId uint32) (spaces []model.Space, err error) {
-	sql := `SELECT id, tmpl_id, spec_id, sid, name, create_time, stop_time, total_time, git_repository FROM t_space WHERE status != ? AND user_id = ?`
-	err = d.db.Select(&spaces, sql, model.SpaceStatusDeleted, userId)
+	sql := `SELECT id, tmpl_id, tmpl_version, prev_version, spec_id, sid, name, create_time, stop_time, total_time, git_repository, git_ref, git_depth, repositories, hooks, schedule FROM t_space WHERE status NOT IN (?, ?) AND user_id = ?`
+	err = d.db.Select(&spaces, sql, model.SpaceStatusDeleted, model.SpaceStatusTrash, userId)
+	return
+}
+
+// FindTrashByUserId 查询用户回收站中的space, 按照删除时间倒序
+func (d *SpaceDao) FindTrashByUserId(userId uint32) (spaces []model.Space, err error) {
+	sql := `SELECT id, tmpl_id, spec_id, sid, name, create_time, delete_time, retain_until, total_time FROM t_space WHERE status = ? AND user_id = ? ORDER BY delete_time DESC`
+	err = d.db.Select(&spaces, sql, model.SpaceStatusTrash, userId)
+	return
+}
+
+// FindTrashBefore 查询保留截止时间早于deadline的回收站中的space
+func (d *SpaceDao) FindTrashBefore(deadline time.Time) (ids []uint32, err error) {
+	sql := `SELECT id FROM t_space WHERE status = ? AND retain_until < ?`
+	err = d.db.Select(&ids, sql, model.SpaceStatusTrash, deadline)
 	return
 }
 
+// MoveToTrashById 将space放入回收站, 并记录删除时间和保留截止时间
+func (d *SpaceDao) MoveToTrashById(id uint32, deleteTime, retainUntil time.Time) error {
+	sql := `UPDATE t_space SET status = ?, delete_time = ?, retain_until = ? WHERE id = ?`
+	_, err := d.db.Exec(sql, model.SpaceStatusTrash, deleteTime, retainUntil, id)
+
+	return err
+}
+
 func (d *SpaceDao) DeleteSpaceById(id uint32) error {
 	// 不真正的删除，给其状态设置为已删除，待以后再删除
 	sql := `UPDATE t_space SET status = ? WHERE id = ?`
@@ -60,7 +85,7 @@ func (d *SpaceDao) DeleteSpaceById(id uint32) error {
 }
 
 func (d *SpaceDao) FindByIdAndUserId(id, userId uint32) (space *model.Space, err error) {
-	sql := `SELECT tmpl_id, spec_id, sid, name, status, git_repository FROM t_space WHERE id = ? AND user_id = ?;`
+	sql := `SELECT tmpl_id, tmpl_version, prev_version, spec_id, sid, name, status, delete_time, retain_until, git_repository, git_ref, git_depth, repositories, hooks, schedule FROM t_space WHERE id = ? AND user_id = ?;`
 	space = &model.Space{}
 	err = d.db.Get(space, sql, id, userId)
 	return
@@ -72,8 +97,38 @@ func (d *SpaceDao) UpdateStatusById(id, status uint32) error {
 	return err
 }
 
+// UpdateScheduleById 更新工作空间的定时启动和停止
+func (d *SpaceDao) UpdateScheduleById(id uint32, schedule model.SpaceSchedule) error {
+	sql := `UPDATE t_space SET schedule = ? WHERE id = ?`
+	_, err := d.db.Exec(sql, schedule, id)
+	return err
+}
+
+// UpdateVersionById 升级或者回滚后更新space使用的模板版本
+func (d *SpaceDao) UpdateVersionById(id, version, prevVersion uint32) error {
+	sql := `UPDATE t_space SET tmpl_version = ?, prev_version = ? WHERE id = ?`
+	_, err := d.db.Exec(sql, version, prevVersion, id)
+	return err
+}
+
 func (d *SpaceDao) UpdateNameById(name string, id uint32) error {
 	sql := `UPDATE t_space SET name = ? WHERE id = ?`
 	_, err := d.db.Exec(sql, name, id)
 	return err
 }
+
+// FindCountByTmplId 查询使用了某个模板的未删除的工作空间数量
+func (d *SpaceDao) FindCountByTmplId(tmplId uint32) (count uint32, err error) {
+	sql := `SELECT COUNT(*) FROM t_space WHERE tmpl_id = ? AND status != ?`
+	err = d.db.Get(&count, sql, tmplId, model.SpaceStatusDeleted)
+
+	return
+}
+
+// FindCountBySpecId 查询使用了某个规格的未删除的工作空间数量
+func (d *SpaceDao) FindCountBySpecId(specId uint32) (count uint32, err error) {
+	sql := `SELECT COUNT(*) FROM t_space WHERE spec_id = ? AND status != ?`
+	err = d.db.Get(&count, sql, specId, model.SpaceStatusDeleted)
+
+	return
+}
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao/db"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+)
+
+type SpaceEnvDao struct {
+	db *sqlx.DB
+}
+
+func NewSpaceEnvDao() *SpaceEnvDao {
+	return &SpaceEnvDao{
+		db: db.DB(),
+	}
+}
+
+// FindByUserIdAndSpaceId 查询用户在某个工作空间下设置的环境变量, spaceId为0时查询用户的默认环境变量
+func (d *SpaceEnvDao) FindByUserIdAndSpaceId(userId, spaceId uint32) (envs []*model.SpaceEnv, err error) {
+	sql := `SELECT id, space_id, name, value, secret, create_time FROM t_space_env WHERE user_id = ? AND space_id = ?`
+	err = d.db.Select(&envs, sql, userId, spaceId)
+	return
+}
+
+// FindForSpace 查询启动工作空间时需要的环境变量, 包括用户的默认环境变量和该工作空间的环境变量
+func (d *SpaceEnvDao) FindForSpace(userId, spaceId uint32) (envs []*model.SpaceEnv, err error) {
+	sql := `SELECT id, space_id, name, value, secret FROM t_space_env WHERE user_id = ? AND space_id IN (0, ?)`
+	err = d.db.Select(&envs, sql, userId, spaceId)
+	return
+}
+
+func (d *SpaceEnvDao) FindCountByUserId(userId uint32) (count uint32, err error) {
+	sql := `SELECT COUNT(*) FROM t_space_env WHERE user_id = ?`
+	err = d.db.Get(&count, sql, userId)
+	return
+}
+
+// ExistsByName 查询工作空间下是否已经设置了同名的环境变量
+func (d *SpaceEnvDao) ExistsByName(userId, spaceId uint32, name string) (exist bool, err error) {
+	sql := `SELECT EXISTS(SELECT 1 FROM t_space_env WHERE user_id = ? AND space_id = ? AND name = ?)`
+	err = d.db.Get(&exist, sql, userId, spaceId, name)
+	return
+}
+
+// Upsert 添加环境变量, 同一个工作空间下的同名环境变量会被覆盖
+func (d *SpaceEnvDao) Upsert(env *model.SpaceEnv) error {
+	sql := `INSERT INTO t_space_env (user_id, space_id, name, value, secret, create_time) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE value = VALUES(value), secret = VALUES(secret)`
+	_, err := d.db.Exec(sql, env.UserId, env.SpaceId, env.Name, env.Value, env.Secret, env.CreateTime)
+	return err
+}
+
+func (d *SpaceEnvDao) DeleteByIdAndUserId(id, userId uint32) (int64, error) {
+	sql := `DELETE FROM t_space_env WHERE id = ? AND user_id = ?`
+	res, err := d.db.Exec(sql, id, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// DeleteBySpaceId 删除工作空间时删除其环境变量
+func (d *SpaceEnvDao) DeleteBySpaceId(spaceId uint32) error {
+	sql := `DELETE FROM t_space_env WHERE space_id = ?`
+	_, err := d.db.Exec(sql, spaceId)
+	return err
+}
@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao/db"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
@@ -19,6 +21,7 @@ func NewSpaceTemplateDao() *SpaceTemplateDao {
 const (
 	TmplUsing = iota
 	TmplDeleted
+	TmplDeprecated
 )
 
 func (s *SpaceTemplateDao) GetAllTmplKind() (kinds []model.TmplKind, err error) {
@@ -28,22 +31,176 @@ func (s *SpaceTemplateDao) GetAllTmplKind() (kinds []model.TmplKind, err error)
 }
 
 func (s *SpaceTemplateDao) GetAllUsingTmpl() (tmpls []model.SpaceTemplate, err error) {
-	sql := "SELECT id, kind_id, name, `desc`, tags, image, avatar FROM t_space_template WHERE status = ?"
+	sql := "SELECT id, kind_id, name, `desc`, tags, image, status, avatar, user_id, pull_secret, hooks, egress, version FROM t_space_template WHERE status = ?"
 	err = s.db.Select(&tmpls, sql, TmplUsing)
 
 	return
 }
 
+// GetAllAvailableTmpl 查询所有未删除的模板，包括已弃用的模板
+// 已弃用的模板不能用于创建新的工作空间，但是已创建的工作空间仍然需要使用
+func (s *SpaceTemplateDao) GetAllAvailableTmpl() (tmpls []model.SpaceTemplate, err error) {
+	sql := "SELECT id, kind_id, name, `desc`, tags, image, status, avatar, user_id, pull_secret, hooks, egress, version FROM t_space_template WHERE status != ?"
+	err = s.db.Select(&tmpls, sql, TmplDeleted)
+
+	return
+}
+
 func (s *SpaceTemplateDao) GetAllTmpl() (tmpls []model.SpaceTemplate, err error) {
-	sql := "SELECT id, kind_id, name, `desc`, tags, image, avatar FROM t_space_template"
+	sql := "SELECT id, kind_id, name, `desc`, tags, image, avatar, hooks, egress, version FROM t_space_template"
 	err = s.db.Select(&tmpls, sql)
 
 	return
 }
 
 func (s *SpaceTemplateDao) GetAllSpec() (specs []model.SpaceSpec, err error) {
-	sql := "SELECT id, cpu_spec, mem_spec, storage_spec, name, `desc` FROM t_spacespec"
+	sql := "SELECT id, cpu_spec, mem_spec, storage_spec, name, `desc`, scheduling FROM t_spacespec"
 	err = s.db.Select(&specs, sql)
 
 	return
 }
+
+// InsertTmpl 在同一个事务中插入模板和模板的第一个版本
+func (s *SpaceTemplateDao) InsertTmpl(tmpl *model.SpaceTemplate, version *model.TmplVersion) (uint32, error) {
+	var id int64
+	err := transaction(s.db, func(tx *sqlx.Tx) error {
+		sql := "INSERT INTO t_space_template (kind_id, name, `desc`, tags, image, status, avatar, user_id, pull_secret, hooks, egress, version, create_time, delete_time) " +
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		res, err := tx.Exec(sql, tmpl.KindId, tmpl.Name, tmpl.Desc, tmpl.Tags, tmpl.Image, tmpl.Status,
+			tmpl.Avatar, tmpl.UserId, tmpl.PullSecret, tmpl.Hooks, tmpl.Egress, tmpl.Version, tmpl.CreateTime, tmpl.DeleteTime)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+
+		version.TmplId = uint32(id)
+		return insertVersion(tx, version)
+	})
+
+	return uint32(id), err
+}
+
+// UpdateTmpl 在同一个事务中修改模板并记录新的版本, version为nil时不生成新的版本
+func (s *SpaceTemplateDao) UpdateTmpl(tmpl *model.SpaceTemplate, version *model.TmplVersion) error {
+	return transaction(s.db, func(tx *sqlx.Tx) error {
+		if version != nil {
+			if err := insertVersion(tx, version); err != nil {
+				return err
+			}
+		}
+
+		sql := "UPDATE t_space_template SET kind_id = ?, name = ?, `desc` = ?, tags = ?, image = ?, avatar = ?, hooks = ?, egress = ?, version = ? WHERE id = ?"
+		_, err := tx.Exec(sql, tmpl.KindId, tmpl.Name, tmpl.Desc, tmpl.Tags, tmpl.Image, tmpl.Avatar, tmpl.Hooks, tmpl.Egress, tmpl.Version, tmpl.Id)
+		return err
+	})
+}
+
+// 记录模板的版本, 模板id和版本号唯一
+func insertVersion(tx *sqlx.Tx, version *model.TmplVersion) error {
+	sql := `INSERT INTO t_space_template_version (tmpl_id, version, image, changelog, create_time) VALUES (?, ?, ?, ?, ?)`
+	_, err := tx.Exec(sql, version.TmplId, version.Version, version.Image, version.Changelog, version.CreateTime)
+	return err
+}
+
+// 在事务中执行fn, fn返回错误时回滚
+func transaction(db *sqlx.DB, fn func(tx *sqlx.Tx) error) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// FindVersionsByTmplId 查询模板的所有版本, 按照版本号倒序
+func (s *SpaceTemplateDao) FindVersionsByTmplId(tmplId uint32) (versions []model.TmplVersion, err error) {
+	sql := `SELECT id, tmpl_id, version, image, changelog, create_time FROM t_space_template_version WHERE tmpl_id = ? ORDER BY version DESC`
+	err = s.db.Select(&versions, sql, tmplId)
+	return
+}
+
+func (s *SpaceTemplateDao) FindVersion(tmplId, version uint32) (v *model.TmplVersion, err error) {
+	sql := `SELECT id, tmpl_id, version, image, changelog, create_time FROM t_space_template_version WHERE tmpl_id = ? AND version = ?`
+	v = &model.TmplVersion{}
+	err = s.db.Get(v, sql, tmplId, version)
+	return
+}
+
+func (s *SpaceTemplateDao) UpdateTmplStatus(id, status uint32) error {
+	sql := `UPDATE t_space_template SET status = ? WHERE id = ?`
+	_, err := s.db.Exec(sql, status, id)
+	return err
+}
+
+// DeleteTmplById 不真正的删除，将其状态设置为已删除
+func (s *SpaceTemplateDao) DeleteTmplById(id uint32) error {
+	sql := `UPDATE t_space_template SET status = ?, delete_time = ? WHERE id = ?`
+	_, err := s.db.Exec(sql, TmplDeleted, time.Now(), id)
+	return err
+}
+
+// FindTmplCountByKindId 查询某个类别下未删除的模板数量
+func (s *SpaceTemplateDao) FindTmplCountByKindId(kindId uint32) (count uint32, err error) {
+	sql := `SELECT COUNT(*) FROM t_space_template WHERE kind_id = ? AND status != ?`
+	err = s.db.Get(&count, sql, kindId, TmplDeleted)
+	return
+}
+
+// FindTmplCountByUserId 查询某个用户创建的未删除的自定义模板数量
+func (s *SpaceTemplateDao) FindTmplCountByUserId(userId uint32) (count uint32, err error) {
+	sql := `SELECT COUNT(*) FROM t_space_template WHERE user_id = ? AND status != ?`
+	err = s.db.Get(&count, sql, userId, TmplDeleted)
+	return
+}
+
+func (s *SpaceTemplateDao) InsertKind(kind *model.TmplKind) (uint32, error) {
+	sql := `INSERT INTO t_template_kind (name) VALUES (?)`
+	res, err := s.db.Exec(sql, kind.Name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+
+	return uint32(id), err
+}
+
+func (s *SpaceTemplateDao) UpdateKind(kind *model.TmplKind) error {
+	sql := `UPDATE t_template_kind SET name = ? WHERE id = ?`
+	_, err := s.db.Exec(sql, kind.Name, kind.Id)
+	return err
+}
+
+func (s *SpaceTemplateDao) DeleteKindById(id uint32) error {
+	sql := `DELETE FROM t_template_kind WHERE id = ?`
+	_, err := s.db.Exec(sql, id)
+	return err
+}
+
+func (s *SpaceTemplateDao) InsertSpec(spec *model.SpaceSpec) (uint32, error) {
+	sql := "INSERT INTO t_spacespec (cpu_spec, mem_spec, storage_spec, name, `desc`, scheduling) VALUES (?, ?, ?, ?, ?, ?)"
+	res, err := s.db.Exec(sql, spec.CpuSpec, spec.MemSpec, spec.StorageSpec, spec.Name, spec.Desc, spec.Scheduling)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+
+	return uint32(id), err
+}
+
+func (s *SpaceTemplateDao) UpdateSpec(spec *model.SpaceSpec) error {
+	sql := "UPDATE t_spacespec SET cpu_spec = ?, mem_spec = ?, storage_spec = ?, name = ?, `desc` = ?, scheduling = ? WHERE id = ?"
+	_, err := s.db.Exec(sql, spec.CpuSpec, spec.MemSpec, spec.StorageSpec, spec.Name, spec.Desc, spec.Scheduling, spec.Id)
+	return err
+}
+
+func (s *SpaceTemplateDao) DeleteSpecById(id uint32) error {
+	sql := `DELETE FROM t_spacespec WHERE id = ?`
+	_, err := s.db.Exec(sql, id)
+	return err
+}
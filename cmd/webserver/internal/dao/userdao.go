@@ -40,3 +40,16 @@ func (u *UserDao) AddUser(user *model.User) error {
 	_, err := u.db.Exec(sql, user.Uid, user.Username, user.Password, user.Nickname, user.Email, user.CreateTime, user.DeleteTime, user.Status)
 	return err
 }
+
+func (u *UserDao) FindDotfilesById(id uint32) (*model.Dotfiles, error) {
+	sql := `SELECT dotfiles_repository, dotfiles_ref FROM t_user WHERE id = ?`
+	dotfiles := &model.Dotfiles{}
+	err := u.db.Get(dotfiles, sql, id)
+	return dotfiles, err
+}
+
+func (u *UserDao) UpdateDotfilesById(id uint32, dotfiles *model.Dotfiles) error {
+	sql := `UPDATE t_user SET dotfiles_repository = ?, dotfiles_ref = ? WHERE id = ?`
+	_, err := u.db.Exec(sql, dotfiles.Repository, dotfiles.Ref, id)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/conf"
 	"github.com/mangohow/cloud-ide/pkg/logger"
 	"github.com/mangohow/cloud-ide/pkg/utils/encrypt"
 )
@@ -31,3 +32,20 @@ func Auth() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// AdminAuth 校验用户是否为管理员, 需要在Auth之后使用
+func AdminAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		username := ctx.GetString("username")
+		for _, admin := range conf.ServerConfig.Admins {
+			if admin == username {
+				ctx.Next()
+				return
+			}
+		}
+
+		logger.Logger().Warningf("非管理员用户访问, username:%s, ip:%s", username, ctx.Request.RemoteAddr)
+		ctx.Status(http.StatusForbidden)
+		ctx.Abort()
+	}
+}
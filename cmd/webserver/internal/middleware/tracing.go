@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/mangohow/cloud-ide/cmd/webserver/internal/middleware"
+
+// Tracing 从请求头中提取网关传递的链路追踪上下文, 为每个请求创建span
+// 之后的数据库操作和grpc调用通过ctx.Request.Context()关联到该span
+func Tracing() gin.HandlerFunc {
+	tracer := otel.Tracer(tracerName)
+	return func(ctx *gin.Context) {
+		parent := tracing.Propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		// 使用路由作为span的名称, 避免路径中的参数导致span名称过多
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unknown route"
+		}
+		c, span := tracer.Start(parent, ctx.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPMethod(ctx.Request.Method),
+				semconv.HTTPRoute(route),
+				attribute.String("http.client_ip", ctx.ClientIP()),
+			))
+		defer span.End()
+
+		ctx.Request = ctx.Request.WithContext(c)
+		ctx.Next()
+
+		// 认证之后才能获取到用户
+		if uid := ctx.GetString("uid"); uid != "" {
+			span.SetAttributes(attribute.String("uid", uid))
+		}
+		code := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPStatusCode(code))
+		if code >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("status %d", code))
+		}
+		if len(ctx.Errors) > 0 {
+			span.SetStatus(codes.Error, ctx.Errors.String())
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Tracing())
+	var got trace.SpanContext
+	engine.PUT("/api/workspace/start", func(ctx *gin.Context) {
+		got = trace.SpanContextFromContext(ctx.Request.Context())
+		ctx.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/workspace/start?id=1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "PUT /api/workspace/start" || span.SpanKind != trace.SpanKindServer {
+		t.Errorf("name = %s, kind = %s", span.Name, span.SpanKind)
+	}
+	// 网关传递的上下文作为父span, handler中可以获取到请求的span
+	if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span is not a child of the gateway, trace id %s, parent %s", span.SpanContext.TraceID(), span.Parent.SpanID())
+	}
+	if got.SpanID() != span.SpanContext.SpanID() {
+		t.Errorf("handler span = %s, want %s", got.SpanID(), span.SpanContext.SpanID())
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("status = %v, want Error", span.Status.Code)
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+const (
+	GitCredentialHttps = iota
+	GitCredentialSsh
+)
+
+// GitCredential 用户克隆私有git仓库使用的凭证, 每个git主机只能设置一个凭证
+type GitCredential struct {
+	Id         uint32    `json:"id" db:"id"`
+	UserId     uint32    `json:"-" db:"user_id"`
+	Host       string    `json:"host" db:"host"`               // git仓库的主机名, 例如github.com
+	Type       uint32    `json:"type" db:"type"`               // 0 https token 1 ssh私钥
+	Username   string    `json:"username" db:"username"`       // https使用的用户名
+	Secret     string    `json:"-" db:"secret"`                // token或者私钥, 加密后保存
+	KnownHosts string    `json:"known_hosts" db:"known_hosts"` // ssh主机的公钥, 克隆时校验主机的身份
+	CreateTime time.Time `json:"create_time" db:"create_time"`
+}
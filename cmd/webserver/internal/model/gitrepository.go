@@ -0,0 +1,30 @@
+package model
+
+import (
+	"database/sql/driver"
+)
+
+// GitRepository 工作空间中克隆的git仓库, Path为相对于工作目录的路径, 为空时使用仓库名称
+type GitRepository struct {
+	Url   string `json:"url"`
+	Path  string `json:"path"`
+	Ref   string `json:"ref"`
+	Depth int32  `json:"depth"`
+}
+
+// GitRepositories 以json格式保存在数据库中
+type GitRepositories []GitRepository
+
+func (r GitRepositories) Value() (driver.Value, error) {
+	return jsonValue(r, len(r) == 0)
+}
+
+func (r *GitRepositories) Scan(src any) error {
+	return scanJSON(src, r)
+}
+
+// Dotfiles 用户的dotfiles仓库, 在每次启动工作空间时安装
+type Dotfiles struct {
+	Repository string `json:"repository" db:"dotfiles_repository"`
+	Ref        string `json:"ref" db:"dotfiles_ref"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// 以json格式保存在数据库中的字段, 空值保存为空字符串
+func jsonValue(v any, empty bool) (driver.Value, error) {
+	if empty {
+		return "", nil
+	}
+
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// 从数据库中读取json格式的字段, 空字符串读取为零值
+func scanJSON[T any](src any, dst *T) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for %T", src, *dst)
+	}
+	if len(data) == 0 {
+		var zero T
+		*dst = zero
+		return nil
+	}
+
+	return json.Unmarshal(data, dst)
+}
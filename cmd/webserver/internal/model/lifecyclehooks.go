@@ -0,0 +1,47 @@
+package model
+
+import (
+	"database/sql/driver"
+)
+
+// MaxHookLength 每个生命周期钩子命令的最大长度
+const MaxHookLength = 4096
+
+// LifecycleHooks 工作空间的生命周期钩子, 以json格式保存在数据库中
+// 模板和工作空间都可以设置, 工作空间中设置的钩子会覆盖模板中的同名钩子
+type LifecycleHooks struct {
+	PostCreate string `json:"post_create"` // 第一次创建时执行, 例如安装依赖
+	PostStart  string `json:"post_start"`  // 每次启动时执行, 例如启动数据库
+	PreStop    string `json:"pre_stop"`    // 停止前执行, 例如保存数据
+}
+
+func (h LifecycleHooks) IsEmpty() bool {
+	return h.PostCreate == "" && h.PostStart == "" && h.PreStop == ""
+}
+
+func (h LifecycleHooks) Valid() bool {
+	return len(h.PostCreate) <= MaxHookLength && len(h.PostStart) <= MaxHookLength && len(h.PreStop) <= MaxHookLength
+}
+
+// Merge 使用override中不为空的钩子覆盖h中的钩子
+func (h LifecycleHooks) Merge(override LifecycleHooks) LifecycleHooks {
+	if override.PostCreate != "" {
+		h.PostCreate = override.PostCreate
+	}
+	if override.PostStart != "" {
+		h.PostStart = override.PostStart
+	}
+	if override.PreStop != "" {
+		h.PreStop = override.PreStop
+	}
+
+	return h
+}
+
+func (h LifecycleHooks) Value() (driver.Value, error) {
+	return jsonValue(h, h.IsEmpty())
+}
+
+func (h *LifecycleHooks) Scan(src any) error {
+	return scanJSON(src, h)
+}
@@ -1,13 +1,86 @@
 package reqtype
 
+import "github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+
 type SpaceCreateOption struct {
 	Name          string `json:"name"`
 	TmplId        uint32 `json:"tmpl_id"`
 	SpaceSpecId   uint32 `json:"space_spec_id"`
 	UserId        uint32 `json:"user_id"`
 	GitRepository string `json:"git_repository"`
+	GitRef        string `json:"git_ref"`   // 分支、标签或者commit, 为空时使用默认分支
+	GitDepth      int32  `json:"git_depth"` // 浅克隆深度, 0表示完整克隆
+	// 额外克隆的git仓库
+	Repositories []model.GitRepository `json:"repositories"`
+	// 生命周期钩子, 覆盖模板中的钩子
+	Hooks model.LifecycleHooks `json:"hooks"`
 }
 
 type SpaceId struct {
 	Id uint32 `json:"id"`
 }
+
+// SpaceStopOption 停止工作空间, DelayMinutes大于0时在指定的分钟数之后停止, 在此之前可以取消
+type SpaceStopOption struct {
+	Id           uint32 `json:"id"`
+	DelayMinutes uint32 `json:"delay_minutes"`
+}
+
+// SpaceScheduleOption 设置工作空间的定时启动和停止, Start和Stop为cron表达式
+type SpaceScheduleOption struct {
+	Id       uint32 `json:"id"`
+	Start    string `json:"start"`
+	Stop     string `json:"stop"`
+	Timezone string `json:"timezone"`
+}
+
+type SpaceScheduleQuery struct {
+	Id uint32 `form:"id"`
+}
+
+type Id struct {
+	Id uint32 `json:"id"`
+}
+
+type IdQuery struct {
+	Id uint32 `form:"id"`
+}
+
+// CustomTmplOption 用户自定义模板
+type CustomTmplOption struct {
+	KindId   uint32               `json:"kind_id"`
+	Name     string               `json:"name"`
+	Desc     string               `json:"desc"`
+	Tags     string               `json:"tags"`
+	Image    string               `json:"image"`
+	Avatar   string               `json:"avatar"`
+	Registry *RegistryAuth        `json:"registry"` // 私有镜像仓库的认证信息, 公开镜像不需要
+	Hooks    model.LifecycleHooks `json:"hooks"`
+}
+
+type RegistryAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// EnvOption 设置工作空间的环境变量, SpaceId为0时设置用户的默认环境变量
+type EnvOption struct {
+	SpaceId uint32 `json:"space_id"`
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Secret  bool   `json:"secret"`
+}
+
+type EnvQuery struct {
+	SpaceId uint32 `form:"space_id"`
+}
+
+// GitCredentialOption 克隆私有仓库使用的凭证, https使用用户名和token, ssh使用私钥
+// ssh凭证需要指定主机的公钥, 格式与known_hosts文件相同, 可以通过ssh-keyscan获取
+type GitCredentialOption struct {
+	Host       string `json:"host"`
+	Type       uint32 `json:"type"`
+	Username   string `json:"username"`
+	Secret     string `json:"secret"`
+	KnownHosts string `json:"known_hosts"`
+}
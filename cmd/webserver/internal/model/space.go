@@ -1,6 +1,14 @@
 package model
 
+import "time"
+
 type RunningSpace struct {
 	Sid  string `json:"sid"`
 	Host string `json:"host"`
 }
+
+// TrashSpace 回收站中的工作空间, 在过期时间之前可以恢复
+type TrashSpace struct {
+	Space
+	ExpireTime time.Time `json:"expire_time"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"database/sql/driver"
+	"net"
+)
+
+// SpaceEgress 模板的出站白名单, 以json格式保存在数据库中, 由管理员配置
+// Enabled为false时使用控制面的默认出站策略, 为true时只允许访问DNS和Rules中的地址
+type SpaceEgress struct {
+	Enabled bool         `json:"enabled"`
+	Rules   []EgressRule `json:"rules,omitempty"`
+}
+
+type EgressRule struct {
+	CIDR  string  `json:"cidr"`            // 允许访问的地址段, 例如软件包仓库的地址
+	Ports []int32 `json:"ports,omitempty"` // 允许访问的TCP端口, 为空表示所有端口
+}
+
+func (e SpaceEgress) IsEmpty() bool {
+	return !e.Enabled && len(e.Rules) == 0
+}
+
+// 规则数量的限制由control-plane的webhook校验
+func (e SpaceEgress) Valid() bool {
+	for _, rule := range e.Rules {
+		if _, _, err := net.ParseCIDR(rule.CIDR); err != nil {
+			return false
+		}
+		for _, port := range rule.Ports {
+			if port < 1 || port > 65535 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (e SpaceEgress) Value() (driver.Value, error) {
+	return jsonValue(e, e.IsEmpty())
+}
+
+func (e *SpaceEgress) Scan(src any) error {
+	return scanJSON(src, e)
+}
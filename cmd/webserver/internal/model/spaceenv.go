@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// SpaceEnv 工作空间的环境变量, SpaceId为0时表示用户的默认环境变量, 对该用户所有的工作空间生效
+type SpaceEnv struct {
+	Id         uint32    `json:"id" db:"id"`
+	UserId     uint32    `json:"-" db:"user_id"`
+	SpaceId    uint32    `json:"space_id" db:"space_id"`
+	Name       string    `json:"name" db:"name"`
+	Value      string    `json:"value" db:"value"`   // 加密后保存
+	Secret     bool      `json:"secret" db:"secret"` // 是否为敏感数据, 敏感数据不会返回给前端
+	CreateTime time.Time `json:"create_time" db:"create_time"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// SpaceEvent 工作空间在集群中的事件, 用于展示启动过程和失败原因
+type SpaceEvent struct {
+	Type    string    `json:"type"` // Normal或Warning
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+	Kind    string    `json:"kind"` // 事件所属的对象, WorkSpace、Pod或者PersistentVolumeClaim
+	Count   int32     `json:"count"`
+	Time    time.Time `json:"time"`
+}
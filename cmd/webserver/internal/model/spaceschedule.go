@@ -0,0 +1,35 @@
+package model
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// SpaceSchedule 工作空间的定时启动和停止, 以json格式保存在数据库中
+// Start和Stop为标准的5段cron表达式, 为空表示不定时执行
+type SpaceSchedule struct {
+	Start    string `json:"start"`    // 定时启动, 例如 "0 9 * * 1-5"
+	Stop     string `json:"stop"`     // 定时停止, 例如 "0 19 * * 1-5"
+	Timezone string `json:"timezone"` // 时区, 例如 "Asia/Shanghai", 为空时使用UTC
+}
+
+func (s SpaceSchedule) IsEmpty() bool {
+	return s.Start == "" && s.Stop == ""
+}
+
+func (s SpaceSchedule) Value() (driver.Value, error) {
+	return jsonValue(s, s.IsEmpty())
+}
+
+func (s *SpaceSchedule) Scan(src any) error {
+	return scanJSON(src, s)
+}
+
+// SpaceScheduleInfo 工作空间的定时设置以及下一次执行的时间
+type SpaceScheduleInfo struct {
+	SpaceSchedule
+	NextStart *time.Time `json:"next_start,omitempty"`
+	NextStop  *time.Time `json:"next_stop,omitempty"`
+	// 工作空间还没有启动过, 控制面中不存在对应的工作空间, 定时在第一次手动启动后生效
+	Pending bool `json:"pending,omitempty"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"database/sql/driver"
+)
+
+// SpaceScheduling 空间规格的调度约束, 以json格式保存在数据库中
+// 管理员可以为配置较高的规格指定节点池, 使其不会被调度到配置较低的节点上
+type SpaceScheduling struct {
+	NodeSelector      map[string]string `json:"node_selector,omitempty"`       // 节点需要具有的标签
+	Tolerations       []Toleration      `json:"tolerations,omitempty"`         // 容忍节点的污点
+	PriorityClassName string            `json:"priority_class_name,omitempty"` // Pod的优先级
+	TopologySpread    []TopologySpread  `json:"topology_spread,omitempty"`     // 在拓扑域之间均匀分布
+}
+
+type Toleration struct {
+	Key               string `json:"key"`
+	Operator          string `json:"operator"` // Exists或Equal
+	Value             string `json:"value"`
+	Effect            string `json:"effect"`             // NoSchedule、PreferNoSchedule或NoExecute, 为空表示所有effect
+	TolerationSeconds int64  `json:"toleration_seconds"` // 只对NoExecute有效, 0表示一直容忍
+}
+
+type TopologySpread struct {
+	TopologyKey    string `json:"topology_key"`    // 拓扑域的节点标签, 例如 topology.kubernetes.io/zone
+	MaxSkew        int32  `json:"max_skew"`        // 拓扑域之间工作空间数量的最大差值
+	ScheduleAnyway bool   `json:"schedule_anyway"` // 无法满足约束时是否仍然调度
+}
+
+func (s SpaceScheduling) IsEmpty() bool {
+	return len(s.NodeSelector) == 0 && len(s.Tolerations) == 0 && s.PriorityClassName == "" && len(s.TopologySpread) == 0
+}
+
+// Valid 只做基本的校验, 标签等格式由控制面校验
+func (s SpaceScheduling) Valid() bool {
+	for _, t := range s.Tolerations {
+		switch t.Operator {
+		case "Exists", "Equal", "":
+		default:
+			return false
+		}
+		switch t.Effect {
+		case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return false
+		}
+		if t.TolerationSeconds < 0 {
+			return false
+		}
+	}
+	for _, spread := range s.TopologySpread {
+		if spread.TopologyKey == "" || spread.MaxSkew < 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s SpaceScheduling) Value() (driver.Value, error) {
+	return jsonValue(s, s.IsEmpty())
+}
+
+func (s *SpaceScheduling) Scan(src any) error {
+	return scanJSON(src, s)
+}
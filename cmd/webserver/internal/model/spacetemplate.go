@@ -4,16 +4,22 @@ import "time"
 
 // SpaceTemplate 云开发空间模板
 type SpaceTemplate struct {
-	Id         uint32    `json:"id" db:"id"`
-	KindId     uint32    `json:"kind_id" db:"kind_id"` // 类别Id
-	Name       string    `json:"name" db:"name"`       // 空间模板名称
-	Desc       string    `json:"desc" db:"desc"`       // 描述
-	Tags       string    `json:"tags" db:"tags"`       // 标签，使用|隔开
-	Image      string    `json:"image" db:"image"`     // 镜像
-	Status     uint32    `json:"status" db:"status"`   // 0可用 1 已删除
-	Avatar     string    `json:"avatar" db:"avatar"`
-	CreateTime time.Time `json:"create_time" db:"create_time"`
-	DeleteTime time.Time `json:"delete_time" db:"delete_time"`
+	Id         uint32         `json:"id" db:"id"`
+	KindId     uint32         `json:"kind_id" db:"kind_id"` // 类别Id
+	Name       string         `json:"name" db:"name"`       // 空间模板名称
+	Desc       string         `json:"desc" db:"desc"`       // 描述
+	Tags       string         `json:"tags" db:"tags"`       // 标签，使用|隔开
+	Image      string         `json:"image" db:"image"`     // 镜像
+	Status     uint32         `json:"status" db:"status"`   // 0可用 1 已删除 2 已弃用
+	Avatar     string         `json:"avatar" db:"avatar"`
+	UserId     uint32         `json:"user_id" db:"user_id"`       // 创建该模板的用户id, 0表示公共模板
+	PullSecret string         `json:"-" db:"pull_secret"`         // 拉取私有镜像使用的Secret名称
+	Hooks      LifecycleHooks `json:"hooks" db:"hooks"`           // 生命周期钩子
+	Egress     SpaceEgress    `json:"egress" db:"egress"`         // 出站白名单, 由管理员配置
+	Version    uint32         `json:"version" db:"version"`       // 当前版本, 每次修改镜像时加1
+	Changelog  string         `json:"changelog,omitempty" db:"-"` // 修改镜像时的更新说明, 记录在版本中
+	CreateTime time.Time      `json:"create_time" db:"create_time"`
+	DeleteTime time.Time      `json:"delete_time" db:"delete_time"`
 }
 
 type TmplKind struct {
@@ -26,6 +32,7 @@ const (
 	SpaceStatusDeleted = iota
 	SpaceStatusAvailable
 	SpaceStatusUncreated
+	SpaceStatusTrash // 在回收站中, 保留时间内可以恢复
 )
 
 const (
@@ -35,22 +42,31 @@ const (
 
 // Space 用户根据模板创建的空间
 type Space struct {
-	Id            uint32        `json:"id" db:"id"`
-	UserId        uint32        `json:"user_id" db:"user_id"` // 所属用户的id
-	TmplId        uint32        `json:"tmpl_id" db:"tmpl_id"` // 模板的id
-	SpecId        uint32        `json:"spec_id" db:"spec_id"` // 规格id
-	Spec          SpaceSpec     `json:"spec"`
-	Sid           string        `json:"sid" db:"sid"`   // 工作空间Id，用于访问时的url中
-	Name          string        `json:"name" db:"name"` // 名称
-	Status        uint32        `json:"-" db:"status"`  // 0 已删除  1 可用 2 未创建
-	RunningStatus uint32        `json:"running_status"` // 0 停止  1 正在运行
-	GitRepository string        `json:"git_repository" db:"git_repository"`
-	CreateTime    time.Time     `json:"create_time" db:"create_time"`
-	DeleteTime    time.Time     `json:"delete_time" db:"delete_time"`
-	StopTime      time.Time     `json:"stop_time" db:"stop_time"`   // 停止时间
-	TotalTime     time.Duration `json:"total_time" db:"total_time"` // 总运行时间
-	Environment   string        `json:"environment"`
-	Avatar        string        `json:"avatar"`
+	Id            uint32          `json:"id" db:"id"`
+	UserId        uint32          `json:"user_id" db:"user_id"` // 所属用户的id
+	TmplId        uint32          `json:"tmpl_id" db:"tmpl_id"` // 模板的id
+	SpecId        uint32          `json:"spec_id" db:"spec_id"` // 规格id
+	Spec          SpaceSpec       `json:"spec"`
+	Sid           string          `json:"sid" db:"sid"`   // 工作空间Id，用于访问时的url中
+	Name          string          `json:"name" db:"name"` // 名称
+	Status        uint32          `json:"-" db:"status"`  // 0 已删除  1 可用 2 未创建 3 回收站
+	RunningStatus uint32          `json:"running_status"` // 0 停止  1 正在运行
+	GitRepository string          `json:"git_repository" db:"git_repository"`
+	GitRef        string          `json:"git_ref" db:"git_ref"`           // 克隆的分支、标签或者commit
+	GitDepth      int32           `json:"git_depth" db:"git_depth"`       // 浅克隆深度, 0表示完整克隆
+	Repositories  GitRepositories `json:"repositories" db:"repositories"` // 额外克隆的git仓库
+	Hooks         LifecycleHooks  `json:"hooks" db:"hooks"`               // 生命周期钩子, 覆盖模板中的钩子
+	Schedule      SpaceSchedule   `json:"schedule" db:"schedule"`         // 定时启动和停止
+	TmplVersion   uint32          `json:"tmpl_version" db:"tmpl_version"` // 使用的模板版本
+	PrevVersion   uint32          `json:"prev_version" db:"prev_version"` // 升级之前的模板版本, 0表示不能回滚
+	Upgradable    bool            `json:"upgradable"`                     // 模板有新的版本可以升级
+	CreateTime    time.Time       `json:"create_time" db:"create_time"`
+	DeleteTime    time.Time       `json:"delete_time" db:"delete_time"`
+	RetainUntil   time.Time       `json:"retain_until" db:"retain_until"` // 回收站中数据的保留截止时间
+	StopTime      time.Time       `json:"stop_time" db:"stop_time"`       // 停止时间
+	TotalTime     time.Duration   `json:"total_time" db:"total_time"`     // 总运行时间
+	Environment   string          `json:"environment"`
+	Avatar        string          `json:"avatar"`
 }
 
 // SpaceSpec 云空间的配置
@@ -61,4 +77,20 @@ type SpaceSpec struct {
 	StorageSpec string `json:"storage_spec" db:"storage_spec"` // 存储规格
 	Name        string `json:"name" db:"name"`
 	Desc        string `json:"desc" db:"desc"`
+	// 调度约束, 由管理员配置
+	Scheduling SpaceScheduling `json:"scheduling" db:"scheduling"`
+}
+
+// PrePullStatus 模板镜像预先拉取到各个节点上的状态
+type PrePullStatus struct {
+	Nodes  int32                `json:"nodes"` // 运行预拉取Pod的节点数量
+	Images []ImagePrePullStatus `json:"images"`
+}
+
+type ImagePrePullStatus struct {
+	Image        string   `json:"image"`
+	ReadyNodes   []string `json:"ready_nodes"`   // 已经拉取了镜像的节点
+	PendingNodes []string `json:"pending_nodes"` // 正在拉取镜像的节点
+	FailedNodes  []string `json:"failed_nodes"`  // 拉取镜像失败的节点
+	Message      string   `json:"message"`       // 最近一次拉取失败的原因
 }
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// TmplVersion 空间模板的版本, 每次修改模板的镜像时生成一个新的版本
+type TmplVersion struct {
+	Id         uint32    `json:"-" db:"id"`
+	TmplId     uint32    `json:"tmpl_id" db:"tmpl_id"`
+	Version    uint32    `json:"version" db:"version"`
+	Image      string    `json:"image,omitempty" db:"image"`
+	Changelog  string    `json:"changelog" db:"changelog"` // 更新说明
+	CreateTime time.Time `json:"create_time" db:"create_time"`
+}
+
+// SpaceVersions 工作空间使用的模板版本以及模板的所有版本
+type SpaceVersions struct {
+	Current  uint32        `json:"current"`
+	Previous uint32        `json:"previous"`
+	Versions []TmplVersion `json:"versions"`
+}
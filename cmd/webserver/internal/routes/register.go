@@ -8,6 +8,9 @@ import (
 )
 
 func Register(engine *gin.Engine) {
+	// 为每个请求创建span, 网关传递的链路追踪上下文作为父span
+	engine.Use(middleware.Tracing())
+
 	authGroup := engine.Group("/auth")
 	userController := controller.NewUserController()
 	{
@@ -22,6 +25,9 @@ func Register(engine *gin.Engine) {
 	{
 		apiGroup.GET("/template/list", router.HandlerAdapter(tmplController.SpaceTmpls))
 		apiGroup.GET("/spec/list", router.HandlerAdapter(tmplController.SpaceSpecs))
+		apiGroup.GET("/template/custom/list", router.HandlerAdapter(tmplController.CustomTmpls))
+		apiGroup.POST("/template/custom", router.HandlerAdapter(tmplController.CreateCustomTmpl))
+		apiGroup.DELETE("/template/custom", router.HandlerAdapter(tmplController.DeleteCustomTmpl))
 	}
 
 	spaceController := controller.NewCloudCodeController()
@@ -32,6 +38,52 @@ func Register(engine *gin.Engine) {
 		apiGroup.POST("/workspace/cas", router.HandlerAdapter(spaceController.CreateSpaceAndStart))
 		apiGroup.PUT("/workspace/start", router.HandlerAdapter(spaceController.StartSpace))
 		apiGroup.PUT("/workspace/stop", router.HandlerAdapter(spaceController.StopSpace))
+		apiGroup.PUT("/workspace/stop/cancel", router.HandlerAdapter(spaceController.CancelStop))
+		apiGroup.GET("/workspace/schedule", router.HandlerAdapter(spaceController.GetSchedule))
+		apiGroup.PUT("/workspace/schedule", router.HandlerAdapter(spaceController.SetSchedule))
 		apiGroup.PUT("/workspace/name", router.HandlerAdapter(spaceController.ModifySpaceName))
+		apiGroup.GET("/workspace/trash", router.HandlerAdapter(spaceController.ListTrash))
+		apiGroup.POST("/workspace/restore", router.HandlerAdapter(spaceController.RestoreSpace))
+		apiGroup.GET("/workspace/versions", router.HandlerAdapter(spaceController.SpaceVersions))
+		apiGroup.PUT("/workspace/upgrade", router.HandlerAdapter(spaceController.UpgradeSpace))
+		apiGroup.PUT("/workspace/rollback", router.HandlerAdapter(spaceController.RollbackSpace))
+		apiGroup.GET("/workspace/events", router.HandlerAdapter(spaceController.SpaceEvents))
+	}
+
+	envController := controller.NewSpaceEnvController()
+	{
+		apiGroup.GET("/env/list", router.HandlerAdapter(envController.ListEnvs))
+		apiGroup.PUT("/env", router.HandlerAdapter(envController.SetEnv))
+		apiGroup.DELETE("/env", router.HandlerAdapter(envController.DeleteEnv))
+	}
+
+	{
+		apiGroup.GET("/user/dotfiles", router.HandlerAdapter(userController.GetDotfiles))
+		apiGroup.PUT("/user/dotfiles", router.HandlerAdapter(userController.SetDotfiles))
+	}
+
+	gitController := controller.NewGitCredentialController()
+	{
+		apiGroup.GET("/git/credential/list", router.HandlerAdapter(gitController.ListCredentials))
+		apiGroup.PUT("/git/credential", router.HandlerAdapter(gitController.SetCredential))
+		apiGroup.DELETE("/git/credential", router.HandlerAdapter(gitController.DeleteCredential))
+	}
+
+	// 管理员接口, 用于管理空间模板、模板类别和空间规格
+	adminGroup := apiGroup.Group("/admin", middleware.AdminAuth())
+	{
+		adminGroup.GET("/template/list", router.HandlerAdapter(tmplController.AllTmpls))
+		adminGroup.GET("/template/prepull", router.HandlerAdapter(tmplController.PrePullStatus))
+		adminGroup.GET("/template/versions", router.HandlerAdapter(tmplController.TmplVersions))
+		adminGroup.POST("/template", router.HandlerAdapter(tmplController.CreateTmpl))
+		adminGroup.PUT("/template", router.HandlerAdapter(tmplController.UpdateTmpl))
+		adminGroup.PUT("/template/deprecate", router.HandlerAdapter(tmplController.DeprecateTmpl))
+		adminGroup.DELETE("/template", router.HandlerAdapter(tmplController.DeleteTmpl))
+		adminGroup.POST("/kind", router.HandlerAdapter(tmplController.CreateKind))
+		adminGroup.PUT("/kind", router.HandlerAdapter(tmplController.UpdateKind))
+		adminGroup.DELETE("/kind", router.HandlerAdapter(tmplController.DeleteKind))
+		adminGroup.POST("/spec", router.HandlerAdapter(tmplController.CreateSpec))
+		adminGroup.PUT("/spec", router.HandlerAdapter(tmplController.UpdateSpec))
+		adminGroup.DELETE("/spec", router.HandlerAdapter(tmplController.DeleteSpec))
 	}
 }
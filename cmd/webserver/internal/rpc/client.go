@@ -2,11 +2,18 @@ package rpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/conf"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -29,12 +36,71 @@ func GrpcClient(name string) *grpc.ClientConn {
 }
 
 func newClient() *grpc.ClientConn {
+	creds, err := transportCredentials()
+	if err != nil {
+		panic(err)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		// 将链路追踪上下文通过metadata传递给control-plane
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(otelgrpc.WithPropagators(tracing.Propagator))),
+		grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor(otelgrpc.WithPropagators(tracing.Propagator))),
+	}
+	if conf.GrpcConfig.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{
+			token:  conf.GrpcConfig.Token,
+			secure: conf.GrpcConfig.CAFile != "",
+		}))
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
-	conn, err := grpc.DialContext(ctx, conf.GrpcConfig.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.DialContext(ctx, conf.GrpcConfig.Addr, opts...)
 	if err != nil {
 		panic(err)
 	}
 
 	return conn
 }
+
+// 没有指定CA时使用明文, 指定了客户端证书时用于control-plane的mTLS认证
+func transportCredentials() (credentials.TransportCredentials, error) {
+	cfg := conf.GrpcConfig
+	if cfg.CAFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	data, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in %s", cfg.CAFile)
+	}
+	tlsConfig := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// tokenCredentials 在每个请求的metadata中携带control-plane的token
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+// 集群内部可以使用明文传递token, 使用TLS时要求加密传输
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.secure
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"time"
 
@@ -10,9 +11,13 @@ import (
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/rpc"
+	"github.com/mangohow/cloud-ide/pkg/cron"
 	"github.com/mangohow/cloud-ide/pkg/logger"
 	"github.com/mangohow/cloud-ide/pkg/pb"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -26,21 +31,32 @@ type CloudCodeService struct {
 	logger    *logrus.Logger
 	rpc       pb.CloudIdeServiceClient
 	dao       *dao.SpaceDao
+	tmplDao   *dao.SpaceTemplateDao
 	tmplCache *caches.TmplCache
 	specCache *caches.SpecCache
+	envs      *SpaceEnvService
+	gitCreds  *GitCredentialService
+	userDao   *dao.UserDao
 }
 
 func NewCloudCodeService() *CloudCodeService {
 	conn := rpc.GrpcClient("space-code")
 	factory := caches.CacheFactory()
 	d := dao.NewSpaceTemplateDao()
-	return &CloudCodeService{
+	s := &CloudCodeService{
 		logger:    logger.Logger(),
 		rpc:       pb.NewCloudIdeServiceClient(conn),
 		dao:       dao.NewSpaceDao(),
+		tmplDao:   d,
 		tmplCache: factory.TmplCache(d),
 		specCache: factory.SpecCache(d),
+		envs:      NewSpaceEnvService(),
+		gitCreds:  NewGitCredentialService(),
+		userDao:   dao.NewUserDao(),
 	}
+	s.startTrashPurge()
+
+	return s
 }
 
 var (
@@ -57,7 +73,10 @@ var (
 )
 
 // CreateWorkspace 创建云工作空间, 只在数据库中插入一条记录
-func (c *CloudCodeService) CreateWorkspace(req *reqtype.SpaceCreateOption, userId uint32) (*model.Space, error) {
+func (c *CloudCodeService) CreateWorkspace(ctx context.Context, req *reqtype.SpaceCreateOption, userId uint32) (_ *model.Space, err error) {
+	_, span := tracer.Start(ctx, "CloudCodeService.CreateWorkspace")
+	defer func() { endSpan(span, err) }()
+
 	// 1、验证创建的工作空间是否达到最大数量
 	count, err := c.dao.FindCountByUserId(userId)
 	if err != nil {
@@ -76,10 +95,15 @@ func (c *CloudCodeService) CreateWorkspace(req *reqtype.SpaceCreateOption, userI
 
 	// 3、从缓存中获取要创建的云空间的模板
 	tmpl := c.tmplCache.GetTmpl(req.TmplId)
-	if tmpl == nil {
+	// 用户自定义的模板只有创建者可以使用
+	if tmpl == nil || (tmpl.UserId != 0 && tmpl.UserId != userId) {
 		c.logger.Warnf("get tmpl cache error:%v", err)
 		return nil, ErrReqParamInvalid
 	}
+	// 已弃用的模板不能再用于创建工作空间
+	if tmpl.Status == dao.TmplDeprecated {
+		return nil, ErrTmplDeprecated
+	}
 
 	// 4、从缓存中获取要创建的云空间的规格
 	spec := c.specCache.Get(req.SpaceSpecId)
@@ -92,16 +116,22 @@ func (c *CloudCodeService) CreateWorkspace(req *reqtype.SpaceCreateOption, userI
 	space := &model.Space{
 		UserId:        userId,
 		TmplId:        tmpl.Id,
+		TmplVersion:   tmpl.Version,
 		SpecId:        spec.Id,
 		Spec:          *spec,
 		Name:          req.Name,
 		Status:        model.SpaceStatusUncreated,
 		CreateTime:    now,
 		DeleteTime:    now,
+		RetainUntil:   now,
 		StopTime:      now,
 		TotalTime:     0,
 		Sid:           generateSID(),
 		GitRepository: req.GitRepository,
+		GitRef:        req.GitRef,
+		GitDepth:      req.GitDepth,
+		Repositories:  req.Repositories,
+		Hooks:         req.Hooks,
 	}
 
 	// 6、 添加到数据库
@@ -117,9 +147,9 @@ func (c *CloudCodeService) CreateWorkspace(req *reqtype.SpaceCreateOption, userI
 
 var ErrOtherSpaceIsRunning = errors.New("there is other space running")
 
-func (c *CloudCodeService) checkHasRunningWorkspace(uid string) (bool, error) {
+func (c *CloudCodeService) checkHasRunningWorkspace(ctx context.Context, uid string) (bool, error) {
 	// 1、检查是否有其它工作空间正在运行, 同时只能有一个工作空间启动
-	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*10)
 	defer cancelFunc()
 	wss, err := c.rpc.RunningWorkspaces(ctx, &pb.RequestRunningWorkspaces{Uid: uid})
 	if err != nil {
@@ -135,9 +165,12 @@ func (c *CloudCodeService) checkHasRunningWorkspace(uid string) (bool, error) {
 }
 
 // CreateAndStartWorkspace 创建并且启动云工作空间
-func (c *CloudCodeService) CreateAndStartWorkspace(req *reqtype.SpaceCreateOption, userId uint32, uid string) (*model.Space, error) {
+func (c *CloudCodeService) CreateAndStartWorkspace(ctx context.Context, req *reqtype.SpaceCreateOption, userId uint32, uid string) (_ *model.Space, err error) {
+	ctx, span := tracer.Start(ctx, "CloudCodeService.CreateAndStartWorkspace", trace.WithAttributes(attribute.String("uid", uid)))
+	defer func() { endSpan(span, err) }()
+
 	// 1、检查是否有其它工作空间正在运行, 同时只能有一个工作空间启动
-	if ok, err := c.checkHasRunningWorkspace(uid); err != nil || ok {
+	if ok, err := c.checkHasRunningWorkspace(ctx, uid); err != nil || ok {
 		if err != nil {
 			return nil, ErrSpaceCreate
 		}
@@ -145,17 +178,17 @@ func (c *CloudCodeService) CreateAndStartWorkspace(req *reqtype.SpaceCreateOptio
 	}
 
 	// 2、创建工作空间
-	space, err := c.CreateWorkspace(req, userId)
+	space, err := c.CreateWorkspace(ctx, req, userId)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3、真正的创建并且启动工作空间
-	return c.createAndStartWorkspace(space, uid)
+	return c.createAndStartWorkspace(ctx, space, uid)
 }
 
 // 调用rpc来创建并且启动工作空间
-func (c *CloudCodeService) createAndStartWorkspace(space *model.Space, uid string) (*model.Space, error) {
+func (c *CloudCodeService) createAndStartWorkspace(ctx context.Context, space *model.Space, uid string) (*model.Space, error) {
 	// 1、获取空间模板
 	tmpl := c.tmplCache.GetTmpl(space.TmplId)
 	if tmpl == nil {
@@ -170,27 +203,63 @@ func (c *CloudCodeService) createAndStartWorkspace(space *model.Space, uid strin
 		return nil, ErrSpaceStart
 	}
 
-	// 3、生成Workspace信息
+	// 3、获取环境变量和git凭证
+	_, span := startSpan(ctx, "CloudCodeService.loadSpaceOptions", space.Id, uid)
+	envs, err := c.envs.SpaceEnvs(space.UserId, space.Id)
+	if err != nil {
+		endSpan(span, err)
+		c.logger.Errorf("get space envs error:%v", err)
+		return nil, ErrSpaceStart
+	}
+	dotfiles, creds, err := c.gitOptions(space)
+	if err != nil {
+		endSpan(span, err)
+		c.logger.Errorf("get git options error:%v", err)
+		return nil, ErrSpaceStart
+	}
+	image, err := c.versionImage(tmpl, space.TmplVersion)
+	endSpan(span, err)
+	if err != nil {
+		c.logger.Errorf("get tmpl version error:%v", err)
+		return nil, ErrSpaceStart
+	}
+	repositories := make([]*pb.GitRepository, 0, len(space.Repositories))
+	for _, repo := range space.Repositories {
+		repositories = append(repositories, &pb.GitRepository{Url: repo.Url, Path: repo.Path, Ref: repo.Ref, Depth: repo.Depth})
+	}
+
+	// 4、生成Workspace信息
 	ws := &pb.RequestCreate{
 		Sid:             space.Sid,
 		Uid:             uid,
-		Image:           tmpl.Image,
+		Image:           image,
 		Port:            DefaultPodPort,
 		GitRepository:   space.GitRepository,
+		GitRef:          space.GitRef,
+		GitDepth:        space.GitDepth,
+		GitCredentials:  creds,
+		Repositories:    repositories,
+		Dotfiles:        dotfiles,
+		Hooks:           lifecycleHooks(tmpl, space),
+		Schedule:        workspaceSchedule(space.Schedule),
+		Egress:          spaceEgress(tmpl.Egress),
 		VolumeMountPath: "/root/",
+		ImagePullSecret: tmpl.PullSecret,
+		Envs:            envs,
 		ResourceLimit: &pb.ResourceLimit{
-			Cpu:     spec.CpuSpec,
-			Memory:  spec.MemSpec,
-			Storage: spec.StorageSpec,
+			Cpu:        spec.CpuSpec,
+			Memory:     spec.MemSpec,
+			Storage:    spec.StorageSpec,
+			Scheduling: spaceScheduling(spec.Scheduling),
 		},
 	}
 
 	c.logger.Debug(ws.ResourceLimit)
 
 	var retErr error
-	// 4、请求k8s controller创建并启动云空间
+	// 5、请求k8s controller创建并启动云空间
 	// 设置90分钟的超时时间
-	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*90)
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*90)
 	defer cancelFunc()
 	resp, err := c.rpc.CreateSpace(ctx, ws)
 	if err != nil {
@@ -211,7 +280,7 @@ func (c *CloudCodeService) createAndStartWorkspace(space *model.Space, uid strin
 	}
 
 	space.RunningStatus = model.RunningStatusRunning
-	// 5、修改数据库中的状态信息
+	// 6、修改数据库中的状态信息
 	if space.Status == model.SpaceStatusUncreated {
 		// 更新数据库
 		err := c.dao.UpdateStatusById(space.Id, model.SpaceStatusAvailable)
@@ -230,9 +299,12 @@ func (c *CloudCodeService) createAndStartWorkspace(space *model.Space, uid strin
 var ErrWorkSpaceNotExist = errors.New("workspace is not exist")
 
 // StartWorkspace 启动云工作空间
-func (c *CloudCodeService) StartWorkspace(id, userId uint32, uid string) (*model.Space, error) {
+func (c *CloudCodeService) StartWorkspace(ctx context.Context, id, userId uint32, uid string) (_ *model.Space, err error) {
+	ctx, span := startSpan(ctx, "CloudCodeService.StartWorkspace", id, uid)
+	defer func() { endSpan(span, err) }()
+
 	// 1、检查是否有其它工作空间正在运行, 同时只能有一个工作空间启动
-	if ok, err := c.checkHasRunningWorkspace(uid); err != nil || ok {
+	if ok, err := c.checkHasRunningWorkspace(ctx, uid); err != nil || ok {
 		if err != nil {
 			return nil, ErrSpaceStart
 		}
@@ -250,21 +322,21 @@ func (c *CloudCodeService) StartWorkspace(id, userId uint32, uid string) (*model
 
 	// 3.该工作空间是否是第一次启动
 	switch space.Status {
-	case model.SpaceStatusDeleted:
+	case model.SpaceStatusDeleted, model.SpaceStatusTrash:
 		return nil, ErrWorkSpaceNotExist
 	case model.SpaceStatusUncreated:
 		// 这种情况是工作空间被创建时，只插入了数据库
 		// 并没有在workspace controller 创建
 		// 因此需要创建并且启动
-		return c.createAndStartWorkspace(space, uid)
+		return c.createAndStartWorkspace(ctx, space, uid)
 	}
 
 	// 4.启动工作空间
-	return c.startWorkspace(space, uid)
+	return c.startWorkspace(ctx, space, uid)
 }
 
 // startWorkspace 启动工作空间
-func (c *CloudCodeService) startWorkspace(space *model.Space, uid string) (*model.Space, error) {
+func (c *CloudCodeService) startWorkspace(ctx context.Context, space *model.Space, uid string) (*model.Space, error) {
 	// 1、获取空间模板
 	tmpl := c.tmplCache.GetTmpl(space.TmplId)
 	if tmpl == nil {
@@ -279,20 +351,41 @@ func (c *CloudCodeService) startWorkspace(space *model.Space, uid string) (*mode
 		return nil, ErrSpaceStart
 	}
 
-	// 3、生成请求信息
+	// 3、获取环境变量和git凭证
+	_, span := startSpan(ctx, "CloudCodeService.loadSpaceOptions", space.Id, uid)
+	envs, err := c.envs.SpaceEnvs(space.UserId, space.Id)
+	if err != nil {
+		endSpan(span, err)
+		c.logger.Errorf("get space envs error:%v", err)
+		return nil, ErrSpaceStart
+	}
+	dotfiles, creds, err := c.gitOptions(space)
+	endSpan(span, err)
+	if err != nil {
+		c.logger.Errorf("get git options error:%v", err)
+		return nil, ErrSpaceStart
+	}
+
+	// 4、生成请求信息
 	req := &pb.RequestStart{
-		Sid: space.Sid,
-		Uid: uid,
+		Sid:            space.Sid,
+		Uid:            uid,
+		Envs:           envs,
+		GitCredentials: creds,
+		Dotfiles:       dotfiles,
+		Hooks:          lifecycleHooks(tmpl, space),
+		Egress:         spaceEgress(tmpl.Egress),
 		ResourceLimit: &pb.ResourceLimit{
-			Cpu:     spec.CpuSpec,
-			Memory:  spec.MemSpec,
-			Storage: spec.StorageSpec,
+			Cpu:        spec.CpuSpec,
+			Memory:     spec.MemSpec,
+			Storage:    spec.StorageSpec,
+			Scheduling: spaceScheduling(spec.Scheduling),
 		},
 	}
 
-	// 4、请求k8s controller启动云空间
+	// 5、请求k8s controller启动云空间
 	// 设置90s的超时时间
-	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*90)
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*90)
 	defer cancelFunc()
 	resp, err := c.rpc.StartSpace(ctx, req)
 	if err != nil {
@@ -318,43 +411,74 @@ func (c *CloudCodeService) startWorkspace(space *model.Space, uid string) (*mode
 var (
 	ErrWorkSpaceIsRunning    = errors.New("workspace is running")
 	ErrWorkSpaceIsNotRunning = errors.New("workspace is not running")
+	ErrStopNotScheduled      = errors.New("workspace stop not scheduled")
+	ErrScheduleInvalid       = errors.New("workspace schedule invalid")
 )
 
 // DeleteWorkspace 删除云工作空间
-func (c *CloudCodeService) DeleteWorkspace(id, userId uint32, uid string) error {
+func (c *CloudCodeService) DeleteWorkspace(ctx context.Context, id, userId uint32, uid string) (err error) {
+	ctx, span := startSpan(ctx, "CloudCodeService.DeleteWorkspace", id, uid)
+	defer func() { endSpan(span, err) }()
+
 	// 1、先查询工作空间并确保该工作空间是属于该用户的
 	space, err := c.dao.FindByIdAndUserId(id, userId)
 	if err != nil {
 		c.logger.Warnf("find sid error:%v", err)
 		return err
 	}
+	if space.Status == model.SpaceStatusDeleted || space.Status == model.SpaceStatusTrash {
+		return ErrWorkSpaceNotExist
+	}
 
 	// 2.检测是否正在运行
-	if ok, err := c.checkHasRunningWorkspace(uid); err != nil || ok {
+	if ok, err := c.checkHasRunningWorkspace(ctx, uid); err != nil || ok {
 		if err != nil {
 			return ErrSpaceDelete
 		}
 		return ErrWorkSpaceIsRunning
 	}
 
-	// 3、通知controller删除该workspace关联的资源
-	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*30)
+	// 3、通知controller删除该workspace关联的资源, 根据保留策略保留存储卷或者快照
+	// controller和回收站使用相同的保留截止时间, 之后修改保留天数的配置不影响已经删除的工作空间
+	policy, days := retentionPolicy()
+	now := time.Now()
+	retainUntil := now.AddDate(0, 0, int(days)).Truncate(time.Second)
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*30)
 	defer cancelFunc()
 	_, err = c.rpc.DeleteSpace(ctx, &pb.RequestDelete{
-		Sid: space.Sid,
-		Uid: uid,
+		Sid:         space.Sid,
+		Uid:         uid,
+		Retention:   policy,
+		RetainDays:  days,
+		RetainUntil: retainUntil.Unix(),
 	})
 	if err != nil {
 		c.logger.Warnf("delete workspace err:%v", err)
 		return err
 	}
 
-	// 3、从mysql中删除记录
+	// 4、保留了数据时放入回收站, 在保留时间内可以恢复, 环境变量在回收站清理时删除
+	if policy != pb.RequestDelete_Delete {
+		return c.dao.MoveToTrashById(id, now, retainUntil)
+	}
+
+	// 5、删除工作空间的环境变量
+	if err := c.envs.DeleteSpaceEnvs(id); err != nil {
+		c.logger.Warnf("delete space envs error:%v", err)
+	}
+
+	// 6、从mysql中删除记录
 	return c.dao.DeleteSpaceById(id)
 }
 
-// StopWorkspace 停止云工作空间
-func (c *CloudCodeService) StopWorkspace(id, userId uint32, uid string) error {
+// MaxStopDelay 延迟停止的最大时间
+const MaxStopDelay = 24 * time.Hour
+
+// StopWorkspace 停止云工作空间, delay大于0时在delay之后停止, 返回计划的停止时间
+func (c *CloudCodeService) StopWorkspace(ctx context.Context, id, userId uint32, uid string, delay time.Duration) (_ *time.Time, err error) {
+	ctx, span := startSpan(ctx, "CloudCodeService.StopWorkspace", id, uid)
+	defer func() { endSpan(span, err) }()
+
 	c.logger.Debugf("StopWorkspace, sid: %d, uid: %s", id, uid)
 
 	// 1、检测该工作空间是否属于该用户
@@ -362,27 +486,116 @@ func (c *CloudCodeService) StopWorkspace(id, userId uint32, uid string) error {
 	space, err := c.dao.FindByIdAndUserId(id, userId)
 	if err != nil {
 		c.logger.Warnf("find sid error:%v", err)
-		return err
+		return nil, err
 	}
 
 	// 2、查询云工作空间是否正在运行
-	ok, err := c.checkHasRunningWorkspace(uid)
+	ok, err := c.checkHasRunningWorkspace(ctx, uid)
 	if err != nil {
 		c.logger.Errorf("get running workspace err=%v, sid=%d", err, id)
-		return ErrSpaceStop
+		return nil, ErrSpaceStop
 	}
 	if !ok {
 		c.logger.Debug("workspace is not running, sid:", id)
-		return ErrWorkSpaceIsNotRunning
+		return nil, ErrWorkSpaceIsNotRunning
+	}
+
+	// 3、停止workspace, 在停止之前IDE会收到通知
+	res, err := c.rpc.StopSpace(withoutCancel(ctx), &pb.RequestStop{
+		Sid:          space.Sid,
+		Uid:          uid,
+		DelaySeconds: int32(delay / time.Second),
+	})
+	if err != nil {
+		c.logger.Errorf("rpc delete space error:%v", err)
+		return nil, err
+	}
+	if res.ScheduledStopTime == 0 {
+		return nil, nil
+	}
+
+	stopTime := time.Unix(res.ScheduledStopTime, 0)
+	return &stopTime, nil
+}
+
+// CancelStop 取消计划的停止
+func (c *CloudCodeService) CancelStop(ctx context.Context, id, userId uint32, uid string) error {
+	space, err := c.dao.FindByIdAndUserId(id, userId)
+	if err != nil {
+		c.logger.Warnf("find sid error:%v", err)
+		return err
 	}
 
-	// 3、停止workspace
-	_, err = c.rpc.StopSpace(context.Background(), &pb.RequestStop{
+	_, err = c.rpc.CancelStop(withoutCancel(ctx), &pb.RequestCancelStop{
 		Sid: space.Sid,
 		Uid: uid,
 	})
 	if err != nil {
-		c.logger.Errorf("rpc delete space error:%v", err)
+		if status.Code(err) == codes.FailedPrecondition {
+			return ErrStopNotScheduled
+		}
+		c.logger.Errorf("rpc cancel stop error:%v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetSchedule 获取工作空间的定时启动和停止, 以及下一次启动和停止的时间
+func (c *CloudCodeService) GetSchedule(id, userId uint32) (*model.SpaceScheduleInfo, error) {
+	space, err := c.dao.FindByIdAndUserId(id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSpaceNotFound
+		}
+		c.logger.Warnf("find space error:%v", err)
+		return nil, err
+	}
+
+	info := &model.SpaceScheduleInfo{SpaceSchedule: space.Schedule, Pending: space.Status == model.SpaceStatusUncreated}
+	loc, err := time.LoadLocation(space.Schedule.Timezone)
+	if err != nil {
+		return info, nil
+	}
+	now := time.Now().In(loc)
+	info.NextStart = nextScheduleTime(space.Schedule.Start, now)
+	info.NextStop = nextScheduleTime(space.Schedule.Stop, now)
+
+	return info, nil
+}
+
+// SetSchedule 设置工作空间的定时启动和停止, Start和Stop都为空时取消定时
+// 工作空间已经启动过时同步到控制面, 否则在下次启动时生效
+func (c *CloudCodeService) SetSchedule(ctx context.Context, id, userId uint32, uid string, schedule model.SpaceSchedule) error {
+	if err := validateSchedule(schedule); err != nil {
+		c.logger.Warnf("schedule invalid:%v", err)
+		return ErrScheduleInvalid
+	}
+
+	space, err := c.dao.FindByIdAndUserId(id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSpaceNotFound
+		}
+		c.logger.Warnf("find space error:%v", err)
+		return err
+	}
+	if space.Status == model.SpaceStatusDeleted || space.Status == model.SpaceStatusTrash {
+		return ErrSpaceNotFound
+	}
+
+	if err := c.dao.UpdateScheduleById(id, schedule); err != nil {
+		c.logger.Errorf("update schedule error:%v", err)
+		return err
+	}
+
+	_, err = c.rpc.SetSchedule(withoutCancel(ctx), &pb.RequestSetSchedule{
+		Sid:      space.Sid,
+		Uid:      uid,
+		Schedule: workspaceSchedule(schedule),
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		c.logger.Errorf("rpc set schedule error:%v", err)
 		return err
 	}
 
@@ -390,7 +603,7 @@ func (c *CloudCodeService) StopWorkspace(id, userId uint32, uid string) error {
 }
 
 // ListWorkspace 列出云工作空间
-func (c *CloudCodeService) ListWorkspace(userId uint32, uid string) ([]model.Space, error) {
+func (c *CloudCodeService) ListWorkspace(ctx context.Context, userId uint32, uid string) ([]model.Space, error) {
 	spaces, err := c.dao.FindAllSpaceByUserId(userId)
 	if err != nil {
 		c.logger.Warnf("find spaces error:%v", err)
@@ -399,14 +612,18 @@ func (c *CloudCodeService) ListWorkspace(userId uint32, uid string) ([]model.Spa
 
 	// 填充environment字段和spec字段
 	for i := 0; i < len(spaces); i++ {
-		t := c.tmplCache.GetTmpl(spaces[i].TmplId)
-		spaces[i].Environment = t.Desc
-		spaces[i].Avatar = t.Avatar
-		spaces[i].Spec = *c.specCache.Get(spaces[i].SpecId)
-		spaces[i].Spec.Id = 0
+		if t := c.tmplCache.GetTmpl(spaces[i].TmplId); t != nil {
+			spaces[i].Environment = t.Desc
+			spaces[i].Avatar = t.Avatar
+			spaces[i].Upgradable = spaces[i].TmplVersion < t.Version
+		}
+		if spec := c.specCache.Get(spaces[i].SpecId); spec != nil {
+			spaces[i].Spec = *spec
+			spaces[i].Spec.Id = 0
+		}
 	}
 
-	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*30)
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*30)
 	defer cancelFunc()
 	wss, err := c.rpc.RunningWorkspaces(ctx, &pb.RequestRunningWorkspaces{Uid: uid})
 	if err != nil {
@@ -444,6 +661,128 @@ func (c *CloudCodeService) ModifyName(name string, id, userId uint32) error {
 	return nil
 }
 
+// 获取用户的dotfiles仓库, 以及克隆工作空间中所有仓库需要的凭证
+func (c *CloudCodeService) gitOptions(space *model.Space) (*pb.GitRepository, []*pb.GitCredential, error) {
+	var dotfiles *pb.GitRepository
+	d, err := c.userDao.FindDotfilesById(space.UserId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d.Repository != "" {
+		dotfiles = &pb.GitRepository{Url: d.Repository, Ref: d.Ref}
+	}
+
+	urls := []string{space.GitRepository, d.Repository}
+	for _, repo := range space.Repositories {
+		urls = append(urls, repo.Url)
+	}
+	creds, err := c.gitCreds.RepositoryCredentials(space.UserId, urls...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dotfiles, creds, nil
+}
+
+// 工作空间的钩子覆盖模板的钩子, 修改模板的钩子后在下次启动时生效
+func lifecycleHooks(tmpl *model.SpaceTemplate, space *model.Space) *pb.LifecycleHooks {
+	hooks := tmpl.Hooks.Merge(space.Hooks)
+	if hooks.IsEmpty() {
+		return nil
+	}
+
+	return &pb.LifecycleHooks{
+		PostCreate: hooks.PostCreate,
+		PostStart:  hooks.PostStart,
+		PreStop:    hooks.PreStop,
+	}
+}
+
+// 模板没有启用出站白名单时使用控制面的默认出站策略, 修改模板的白名单后在下次启动时生效
+func spaceEgress(egress model.SpaceEgress) *pb.EgressPolicy {
+	if !egress.Enabled {
+		return nil
+	}
+
+	res := &pb.EgressPolicy{}
+	for _, rule := range egress.Rules {
+		res.Rules = append(res.Rules, &pb.EgressRule{Cidr: rule.CIDR, Ports: rule.Ports})
+	}
+
+	return res
+}
+
+func workspaceSchedule(schedule model.SpaceSchedule) *pb.WorkspaceSchedule {
+	if schedule.IsEmpty() {
+		return nil
+	}
+
+	return &pb.WorkspaceSchedule{
+		Start:    schedule.Start,
+		Stop:     schedule.Stop,
+		Timezone: schedule.Timezone,
+	}
+}
+
+func validateSchedule(schedule model.SpaceSchedule) error {
+	for _, spec := range []string{schedule.Start, schedule.Stop} {
+		if spec == "" {
+			continue
+		}
+		if _, err := cron.Parse(spec); err != nil {
+			return err
+		}
+	}
+	_, err := time.LoadLocation(schedule.Timezone)
+
+	return err
+}
+
+func nextScheduleTime(spec string, now time.Time) *time.Time {
+	if spec == "" {
+		return nil
+	}
+	s, err := cron.Parse(spec)
+	if err != nil {
+		return nil
+	}
+	next := s.Next(now)
+	if next.IsZero() {
+		return nil
+	}
+
+	return &next
+}
+
+func spaceScheduling(scheduling model.SpaceScheduling) *pb.Scheduling {
+	if scheduling.IsEmpty() {
+		return nil
+	}
+
+	res := &pb.Scheduling{
+		NodeSelector:      scheduling.NodeSelector,
+		PriorityClassName: scheduling.PriorityClassName,
+	}
+	for _, t := range scheduling.Tolerations {
+		res.Tolerations = append(res.Tolerations, &pb.Toleration{
+			Key:               t.Key,
+			Operator:          t.Operator,
+			Value:             t.Value,
+			Effect:            t.Effect,
+			TolerationSeconds: t.TolerationSeconds,
+		})
+	}
+	for _, spread := range scheduling.TopologySpread {
+		res.TopologySpread = append(res.TopologySpread, &pb.TopologySpread{
+			TopologyKey:    spread.TopologyKey,
+			MaxSkew:        spread.MaxSkew,
+			ScheduleAnyway: spread.ScheduleAnyway,
+		})
+	}
+
+	return res
+}
+
 // generateSID 生成Space id
 func generateSID() string {
 	return bson.NewObjectId().Hex()
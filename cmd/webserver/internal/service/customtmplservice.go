@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/registry"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+)
+
+const MaxCustomTmplCount = 10
+
+var (
+	ErrImagePullCheck          = errors.New("image pull check failed")
+	ErrReachMaxCustomTmplCount = errors.New("reach max custom template count")
+)
+
+// GetCustomTmpls 获取用户自定义的模板, 只有创建者可见, 目前没有团队的概念, 不支持在团队内共享
+func (s *SpaceTmplService) GetCustomTmpls(userId uint32) []*model.SpaceTemplate {
+	return customTmpls(s.tmplCache.GetAllTmpl(), userId)
+}
+
+func customTmpls(all []*model.SpaceTemplate, userId uint32) []*model.SpaceTemplate {
+	tmpls := make([]*model.SpaceTemplate, 0)
+	for i := 0; i < len(all); i++ {
+		if all[i].UserId == userId {
+			tmpls = append(tmpls, all[i])
+		}
+	}
+
+	return tmpls
+}
+
+// registryAuth 未指定用户名时匿名访问镜像仓库
+func registryAuth(r *reqtype.RegistryAuth) *registry.Auth {
+	if r == nil || r.Username == "" {
+		return nil
+	}
+
+	return &registry.Auth{Username: r.Username, Password: r.Password}
+}
+
+// CreateCustomTmpl 使用用户自己的镜像创建模板
+// 1.校验镜像格式并通过镜像仓库检查镜像是否可以被拉取
+// 2.如果指定了镜像仓库的认证信息, 则在k8s中创建拉取镜像使用的Secret
+// 3.保存模板, 该模板只对创建者可见
+func (s *SpaceTmplService) CreateCustomTmpl(req *reqtype.CustomTmplOption, userId uint32, uid string) (*model.SpaceTemplate, error) {
+	if req.Name == "" {
+		return nil, ErrReqParamInvalid
+	}
+	if !utils.VerifyImageReference(req.Image) {
+		return nil, ErrImageInvalid
+	}
+	if !req.Hooks.Valid() {
+		return nil, ErrHooksInvalid
+	}
+	if s.tmplCache.GetKind(req.KindId) == nil {
+		return nil, ErrKindNotFound
+	}
+
+	count, err := s.dao.FindTmplCountByUserId(userId)
+	if err != nil {
+		s.logger.Errorf("find custom tmpl count error:%v", err)
+		return nil, ErrTmplModifyFailed
+	}
+	if count >= MaxCustomTmplCount {
+		return nil, ErrReachMaxCustomTmplCount
+	}
+
+	// 拉取检查, 只获取镜像的manifest
+	auth := registryAuth(req.Registry)
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancelFunc()
+	if err := s.checker.Check(ctx, req.Image, auth); err != nil {
+		s.logger.Warnf("image pull check error:%v, image:%s", err, req.Image)
+		return nil, ErrImagePullCheck
+	}
+
+	tmpl := &model.SpaceTemplate{
+		KindId: req.KindId,
+		Name:   req.Name,
+		Desc:   req.Desc,
+		Tags:   req.Tags,
+		Image:  req.Image,
+		Avatar: req.Avatar,
+		Hooks:  req.Hooks,
+		UserId: userId,
+		Status: dao.TmplUsing,
+	}
+
+	// 私有镜像, 创建拉取镜像使用的Secret
+	if auth != nil {
+		resp, err := s.rpc.CreateImagePullSecret(ctx, &pb.RequestImagePullSecret{
+			Uid:      uid,
+			Registry: registry.ParseReference(req.Image).AuthKey(),
+			Username: auth.Username,
+			Password: auth.Password,
+		})
+		if err != nil {
+			s.logger.Errorf("create image pull secret error:%v", err)
+			return nil, ErrTmplModifyFailed
+		}
+		tmpl.PullSecret = resp.Name
+	}
+
+	now := time.Now()
+	tmpl.CreateTime = now
+	tmpl.DeleteTime = now
+	if err := s.insertTmpl(tmpl); err != nil {
+		s.logger.Errorf("insert custom tmpl error:%v", err)
+		return nil, ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return tmpl, nil
+}
+
+// DeleteCustomTmpl 删除用户自定义的模板, 如果拉取镜像的Secret不再被该用户的其它模板使用, 则一并删除
+func (s *SpaceTmplService) DeleteCustomTmpl(id, userId uint32, uid string) error {
+	tmpl := s.tmplCache.GetTmpl(id)
+	if tmpl == nil || tmpl.UserId != userId {
+		return ErrTmplNotFound
+	}
+
+	if err := s.DeleteTmpl(id); err != nil {
+		return err
+	}
+
+	if tmpl.PullSecret == "" {
+		return nil
+	}
+	for _, t := range s.GetCustomTmpls(userId) {
+		if t.PullSecret == tmpl.PullSecret {
+			return nil
+		}
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFunc()
+	_, err := s.rpc.DeleteImagePullSecret(ctx, &pb.RequestDeleteImagePullSecret{
+		Uid:  uid,
+		Name: tmpl.PullSecret,
+	})
+	if err != nil {
+		// 模板已经删除, Secret删除失败只记录日志
+		s.logger.Warnf("delete image pull secret error:%v, name:%s", err, tmpl.PullSecret)
+	}
+
+	return nil
+}
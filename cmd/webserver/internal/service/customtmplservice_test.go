@@ -0,0 +1,74 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
+	"github.com/mangohow/cloud-ide/pkg/registry"
+)
+
+func TestCreateCustomTmplValidate(t *testing.T) {
+	// 名称、镜像和钩子在查询模板类别之前校验
+	s := &SpaceTmplService{}
+	tests := []struct {
+		name string
+		req  reqtype.CustomTmplOption
+		err  error
+	}{
+		{name: "empty name", req: reqtype.CustomTmplOption{Image: "golang:1.20"}, err: ErrReqParamInvalid},
+		{name: "invalid image", req: reqtype.CustomTmplOption{Name: "go", Image: "Golang:1.20"}, err: ErrImageInvalid},
+		{name: "hook too long", req: reqtype.CustomTmplOption{
+			Name:  "go",
+			Image: "golang:1.20",
+			Hooks: model.LifecycleHooks{PostStart: strings.Repeat("a", model.MaxHookLength+1)},
+		}, err: ErrHooksInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.CreateCustomTmpl(&tt.req, 1, "uid"); err != tt.err {
+				t.Errorf("CreateCustomTmpl() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCustomTmpls(t *testing.T) {
+	all := []*model.SpaceTemplate{
+		{Id: 1, Name: "public"},
+		{Id: 2, Name: "mine", UserId: 1},
+		{Id: 3, Name: "others", UserId: 2},
+	}
+
+	got := customTmpls(all, 1)
+	if len(got) != 1 || got[0].Id != 2 {
+		t.Errorf("customTmpls() = %v, want template 2 only", got)
+	}
+	if got := customTmpls(all, 3); len(got) != 0 {
+		t.Errorf("customTmpls() = %v, want empty", got)
+	}
+}
+
+func TestRegistryAuth(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *reqtype.RegistryAuth
+		want *registry.Auth
+	}{
+		{name: "public image"},
+		{name: "empty username", req: &reqtype.RegistryAuth{Password: "pass"}},
+		{name: "private image", req: &reqtype.RegistryAuth{Username: "user", Password: "pass"},
+			want: &registry.Auth{Username: "user", Password: "pass"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registryAuth(tt.req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("registryAuth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SpaceEvents 获取工作空间的事件, 从未启动过的工作空间在集群中不存在, 返回空列表
+func (c *CloudCodeService) SpaceEvents(ctx context.Context, id, userId uint32, uid string) ([]model.SpaceEvent, error) {
+	space, err := c.dao.FindByIdAndUserId(id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSpaceNotFound
+		}
+		c.logger.Warnf("find space error:%v", err)
+		return nil, err
+	}
+	if space.Status == model.SpaceStatusDeleted {
+		return nil, ErrSpaceNotFound
+	}
+
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*10)
+	defer cancelFunc()
+	res, err := c.rpc.GetWorkspaceEvents(ctx, &pb.RequestWorkspaceEvents{Sid: space.Sid, Uid: uid})
+	if status.Code(err) == codes.NotFound {
+		return []model.SpaceEvent{}, nil
+	}
+	if err != nil {
+		c.logger.Errorf("rpc get workspace events error:%v", err)
+		return nil, err
+	}
+
+	events := make([]model.SpaceEvent, 0, len(res.Events))
+	for _, e := range res.Events {
+		events = append(events, model.SpaceEvent{
+			Type:    e.Type,
+			Reason:  e.Reason,
+			Message: e.Message,
+			Kind:    e.Kind,
+			Count:   e.Count,
+			Time:    time.Unix(e.Timestamp, 0),
+		})
+	}
+
+	return events, nil
+}
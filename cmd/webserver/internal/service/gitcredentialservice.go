@@ -0,0 +1,177 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
+	"github.com/mangohow/cloud-ide/pkg/logger"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	"github.com/mangohow/cloud-ide/pkg/utils/encrypt"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrGitCredentialInvalid  = errors.New("git credential invalid")
+	ErrGitCredentialNotFound = errors.New("git credential not found")
+)
+
+var gitHostRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*(:[0-9]+)?$`)
+
+type GitCredentialService struct {
+	logger *logrus.Logger
+	dao    *dao.GitCredentialDao
+	cipher *encrypt.AesGcm
+}
+
+func NewGitCredentialService() *GitCredentialService {
+	return &GitCredentialService{
+		logger: logger.Logger(),
+		dao:    dao.NewGitCredentialDao(),
+		cipher: secretCipher,
+	}
+}
+
+// ListCredentials 获取用户的git凭证, token和私钥不会返回
+func (s *GitCredentialService) ListCredentials(userId uint32) ([]*model.GitCredential, error) {
+	creds, err := s.dao.FindAllByUserId(userId)
+	if err != nil {
+		s.logger.Errorf("find git credentials error:%v", err)
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// SetCredential 添加或修改git凭证, token和私钥加密后保存
+func (s *GitCredentialService) SetCredential(req *reqtype.GitCredentialOption, userId uint32) error {
+	if !gitHostRegexp.MatchString(req.Host) || req.Secret == "" || len(req.Secret) > 8192 {
+		return ErrGitCredentialInvalid
+	}
+	switch req.Type {
+	case model.GitCredentialHttps:
+		if req.Username == "" || strings.ContainsAny(req.Secret, "\r\n") {
+			return ErrGitCredentialInvalid
+		}
+	case model.GitCredentialSsh:
+		if !strings.HasPrefix(strings.TrimSpace(req.Secret), "-----BEGIN") || !validKnownHosts(req.KnownHosts) {
+			return ErrGitCredentialInvalid
+		}
+		req.Username = ""
+	default:
+		return ErrGitCredentialInvalid
+	}
+
+	secret, err := s.cipher.Encrypt(req.Secret)
+	if err != nil {
+		s.logger.Errorf("encrypt git credential error:%v", err)
+		return err
+	}
+
+	return s.dao.Upsert(&model.GitCredential{
+		UserId:     userId,
+		Host:       strings.ToLower(req.Host),
+		Type:       req.Type,
+		Username:   req.Username,
+		Secret:     secret,
+		KnownHosts: strings.TrimSpace(req.KnownHosts),
+		CreateTime: time.Now(),
+	})
+}
+
+// 校验ssh主机的公钥, 每行的格式为 <主机名> <密钥类型> <base64编码的公钥>, 忽略空行和注释
+// 至少需要一个公钥, 克隆时严格校验主机的身份
+func validKnownHosts(knownHosts string) bool {
+	if len(knownHosts) > 8192 || strings.ContainsRune(knownHosts, '\r') {
+		return false
+	}
+
+	count := 0
+	for _, line := range strings.Split(knownHosts, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		// 可能以@cert-authority或@revoked开头
+		if strings.HasPrefix(fields[0], "@") {
+			fields = fields[1:]
+		}
+		if len(fields) < 3 {
+			return false
+		}
+		if _, err := base64.StdEncoding.DecodeString(fields[2]); err != nil {
+			return false
+		}
+		count++
+	}
+
+	return count > 0
+}
+
+// DeleteCredential 删除git凭证
+func (s *GitCredentialService) DeleteCredential(id, userId uint32) error {
+	n, err := s.dao.DeleteByIdAndUserId(id, userId)
+	if err != nil {
+		s.logger.Errorf("delete git credential error:%v", err)
+		return err
+	}
+	if n == 0 {
+		return ErrGitCredentialNotFound
+	}
+
+	return nil
+}
+
+// RepositoryCredentials 获取克隆仓库使用的凭证, 凭证的类型需要与仓库地址的协议一致, 每个主机只返回一个凭证
+func (s *GitCredentialService) RepositoryCredentials(userId uint32, repositories ...string) ([]*pb.GitCredential, error) {
+	var creds []*pb.GitCredential
+	hosts := make(map[string]struct{})
+	for _, repository := range repositories {
+		if repository == "" {
+			continue
+		}
+		host := strings.ToLower(utils.GitRepositoryHost(repository))
+		if _, ok := hosts[host]; ok {
+			continue
+		}
+		hosts[host] = struct{}{}
+
+		cred, err := s.dao.FindByUserIdAndHost(userId, host)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+
+		credType := pb.GitCredential_Https
+		if utils.IsSshGitRepository(repository) {
+			credType = pb.GitCredential_Ssh
+		}
+		if uint32(credType) != cred.Type {
+			continue
+		}
+
+		secret, err := s.cipher.Decrypt(cred.Secret)
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, &pb.GitCredential{
+			Host:       cred.Host,
+			Type:       credType,
+			Username:   cred.Username,
+			Secret:     secret,
+			KnownHosts: cred.KnownHosts,
+		})
+	}
+
+	return creds, nil
+}
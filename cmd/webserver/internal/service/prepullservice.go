@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+)
+
+// 定时同步需要预拉取的镜像, 防止直接修改数据库或者控制面重建后镜像列表不一致
+const prePullSyncInterval = 5 * time.Minute
+
+// 定时将可用的公共模板的镜像同步到控制面, 由控制面将镜像预先拉取到所有节点上
+func (s *SpaceTmplService) startPrePullSync() {
+	go func() {
+		ticker := time.NewTicker(prePullSyncInterval)
+		defer ticker.Stop()
+		for {
+			s.syncPrePullImages()
+			<-ticker.C
+		}
+	}()
+}
+
+// 用户自定义模板只有创建者使用, 因此只预拉取公共模板的镜像, 已弃用的模板也不再预拉取
+func (s *SpaceTmplService) syncPrePullImages() {
+	var images []*pb.PrePullImage
+	for _, tmpl := range s.tmplCache.GetAllTmpl() {
+		if tmpl.Status != dao.TmplUsing || tmpl.UserId != 0 {
+			continue
+		}
+		images = append(images, &pb.PrePullImage{Image: tmpl.Image, PullSecret: tmpl.PullSecret})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	if _, err := s.rpc.SetPrePullImages(ctx, &pb.RequestSetPrePullImages{Images: images}); err != nil {
+		s.logger.Errorf("sync pre-pull images error:%v", err)
+	}
+}
+
+// PrePullStatus 获取模板镜像在各个节点上的拉取状态
+func (s *SpaceTmplService) PrePullStatus() (*model.PrePullStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	res, err := s.rpc.PrePullStatus(ctx, &pb.RequestPrePullStatus{})
+	if err != nil {
+		s.logger.Errorf("get pre-pull status error:%v", err)
+		return nil, err
+	}
+
+	status := &model.PrePullStatus{
+		Nodes:  res.Nodes,
+		Images: make([]model.ImagePrePullStatus, 0, len(res.Images)),
+	}
+	for _, image := range res.Images {
+		status.Images = append(status.Images, model.ImagePrePullStatus{
+			Image:        image.Image,
+			ReadyNodes:   image.ReadyNodes,
+			PendingNodes: image.PendingNodes,
+			FailedNodes:  image.FailedNodes,
+			Message:      image.Message,
+		})
+	}
+
+	return status, nil
+}
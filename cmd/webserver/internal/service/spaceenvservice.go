@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model/reqtype"
+	"github.com/mangohow/cloud-ide/pkg/logger"
+	"github.com/mangohow/cloud-ide/pkg/utils/encrypt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	MaxEnvCount    = 50
+	MaxEnvValueLen = 4096
+	// 敏感数据返回给前端时使用的掩码
+	secretMask = "******"
+)
+
+var (
+	ErrEnvNameInvalid   = errors.New("env name invalid")
+	ErrEnvValueTooLong  = errors.New("env value too long")
+	ErrReachMaxEnvCount = errors.New("reach max env count")
+	ErrEnvNotFound      = errors.New("env not found")
+	ErrEnvEncrypt       = errors.New("env encrypt failed")
+)
+
+var envNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// 加密环境变量和git凭证等用户敏感数据
+var secretCipher *encrypt.AesGcm
+
+// InitSecretCipher 使用配置的密钥初始化加密用户敏感数据的cipher, 需要在注册路由之前调用
+func InitSecretCipher(key string) error {
+	cipher, err := encrypt.NewAesGcm(key)
+	if err != nil {
+		return err
+	}
+	secretCipher = cipher
+
+	return nil
+}
+
+type SpaceEnvService struct {
+	logger   *logrus.Logger
+	dao      *dao.SpaceEnvDao
+	spaceDao *dao.SpaceDao
+	cipher   *encrypt.AesGcm
+}
+
+func NewSpaceEnvService() *SpaceEnvService {
+	return &SpaceEnvService{
+		logger:   logger.Logger(),
+		dao:      dao.NewSpaceEnvDao(),
+		spaceDao: dao.NewSpaceDao(),
+		cipher:   secretCipher,
+	}
+}
+
+// ListEnvs 获取用户在工作空间下设置的环境变量, 敏感数据的值不会返回
+func (s *SpaceEnvService) ListEnvs(userId, spaceId uint32) ([]*model.SpaceEnv, error) {
+	if err := s.checkSpace(userId, spaceId); err != nil {
+		return nil, err
+	}
+
+	envs, err := s.dao.FindByUserIdAndSpaceId(userId, spaceId)
+	if err != nil {
+		s.logger.Errorf("find envs error:%v", err)
+		return nil, err
+	}
+
+	for _, env := range envs {
+		if env.Secret {
+			env.Value = secretMask
+			continue
+		}
+		if env.Value, err = s.cipher.Decrypt(env.Value); err != nil {
+			s.logger.Errorf("decrypt env error:%v, id:%d", err, env.Id)
+			env.Value = ""
+		}
+	}
+
+	return envs, nil
+}
+
+// SetEnv 添加或修改环境变量, 值加密后保存
+func (s *SpaceEnvService) SetEnv(req *reqtype.EnvOption, userId uint32) error {
+	if !envNameRegexp.MatchString(req.Name) || len(req.Name) > 128 {
+		return ErrEnvNameInvalid
+	}
+	if len(req.Value) > MaxEnvValueLen {
+		return ErrEnvValueTooLong
+	}
+	if err := s.checkSpace(userId, req.SpaceId); err != nil {
+		return err
+	}
+
+	// 覆盖已有的环境变量不增加数量, 只有添加新的环境变量时才检查数量限制
+	exist, err := s.dao.ExistsByName(userId, req.SpaceId, req.Name)
+	if err != nil {
+		s.logger.Errorf("find env error:%v", err)
+		return err
+	}
+	if !exist {
+		count, err := s.dao.FindCountByUserId(userId)
+		if err != nil {
+			s.logger.Errorf("find env count error:%v", err)
+			return err
+		}
+		if count >= MaxEnvCount {
+			return ErrReachMaxEnvCount
+		}
+	}
+
+	value, err := s.cipher.Encrypt(req.Value)
+	if err != nil {
+		s.logger.Errorf("encrypt env error:%v", err)
+		return ErrEnvEncrypt
+	}
+
+	return s.dao.Upsert(&model.SpaceEnv{
+		UserId:     userId,
+		SpaceId:    req.SpaceId,
+		Name:       req.Name,
+		Value:      value,
+		Secret:     req.Secret,
+		CreateTime: time.Now(),
+	})
+}
+
+// DeleteEnv 删除环境变量
+func (s *SpaceEnvService) DeleteEnv(id, userId uint32) error {
+	n, err := s.dao.DeleteByIdAndUserId(id, userId)
+	if err != nil {
+		s.logger.Errorf("delete env error:%v", err)
+		return err
+	}
+	if n == 0 {
+		return ErrEnvNotFound
+	}
+
+	return nil
+}
+
+// SpaceEnvs 获取启动工作空间时注入的环境变量, 工作空间的环境变量会覆盖用户的默认环境变量
+func (s *SpaceEnvService) SpaceEnvs(userId, spaceId uint32) (map[string]string, error) {
+	envs, err := s.dao.FindForSpace(userId, spaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(envs))
+	for _, env := range envs {
+		if _, ok := res[env.Name]; ok && env.SpaceId == 0 {
+			continue
+		}
+		value, err := s.cipher.Decrypt(env.Value)
+		if err != nil {
+			return nil, err
+		}
+		res[env.Name] = value
+	}
+
+	return res, nil
+}
+
+// DeleteSpaceEnvs 删除工作空间的环境变量
+func (s *SpaceEnvService) DeleteSpaceEnvs(spaceId uint32) error {
+	return s.dao.DeleteBySpaceId(spaceId)
+}
+
+// 检查工作空间是否属于该用户
+func (s *SpaceEnvService) checkSpace(userId, spaceId uint32) error {
+	if spaceId == 0 {
+		return nil
+	}
+
+	space, err := s.spaceDao.FindByIdAndUserId(spaceId, userId)
+	if err != nil || space.Status == model.SpaceStatusDeleted || space.Status == model.SpaceStatusTrash {
+		return ErrWorkSpaceNotExist
+	}
+
+	return nil
+}
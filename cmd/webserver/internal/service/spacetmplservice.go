@@ -1,35 +1,395 @@
 package service
 
 import (
+	"errors"
+	"time"
+
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/caches"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/rpc"
+	"github.com/mangohow/cloud-ide/pkg/logger"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"github.com/mangohow/cloud-ide/pkg/registry"
+	"github.com/mangohow/cloud-ide/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type SpaceTmplService struct {
+	logger    *logrus.Logger
+	rpc       pb.CloudIdeServiceClient
 	dao       *dao.SpaceTemplateDao
+	spaceDao  *dao.SpaceDao
 	tmplCache *caches.TmplCache
 	specCache *caches.SpecCache
+	checker   *registry.Checker
 }
 
 func NewSpaceTmplService() *SpaceTmplService {
 	d := dao.NewSpaceTemplateDao()
-	return &SpaceTmplService{
+	s := &SpaceTmplService{
+		logger:    logger.Logger(),
+		rpc:       pb.NewCloudIdeServiceClient(rpc.GrpcClient("space-code")),
 		dao:       d,
+		spaceDao:  dao.NewSpaceDao(),
 		tmplCache: caches.CacheFactory().TmplCache(d),
 		specCache: caches.CacheFactory().SpecCache(d),
+		checker:   registry.NewChecker(time.Second * 10),
 	}
+	s.startPrePullSync()
+
+	return s
 }
 
+var (
+	ErrTmplNotFound     = errors.New("template not found")
+	ErrTmplInUse        = errors.New("template is in use")
+	ErrTmplDeprecated   = errors.New("template is deprecated")
+	ErrImageInvalid     = errors.New("image reference invalid")
+	ErrKindNotFound     = errors.New("template kind not found")
+	ErrKindInUse        = errors.New("template kind is in use")
+	ErrSpecNotFound     = errors.New("space spec not found")
+	ErrSpecInUse        = errors.New("space spec is in use")
+	ErrSpecInvalid      = errors.New("space spec invalid")
+	ErrTmplModifyFailed = errors.New("modify template failed")
+	ErrHooksInvalid     = errors.New("lifecycle hooks invalid")
+	ErrEgressInvalid    = errors.New("egress allowlist invalid")
+)
+
+// GetAllUsingTmpl 获取所有可用于创建工作空间的公共模板, 已弃用的模板和用户自定义的模板不会被返回
 func (s *SpaceTmplService) GetAllUsingTmpl() ([]*model.SpaceTemplate, []*model.TmplKind, error) {
-	tmpls := s.tmplCache.GetAllTmpl()
+	all := s.tmplCache.GetAllTmpl()
 	kinds := s.tmplCache.GetAllKinds()
-	for i := 0; i < len(tmpls); i++ {
-		tmpls[i].Image = ""
+	tmpls := make([]*model.SpaceTemplate, 0, len(all))
+	for i := 0; i < len(all); i++ {
+		if all[i].Status != dao.TmplUsing || all[i].UserId != 0 {
+			continue
+		}
+		all[i].Image = ""
+		tmpls = append(tmpls, all[i])
 	}
 	return tmpls, kinds, nil
 }
 
+// GetAllTmpl 获取所有未删除的模板, 包括已弃用的模板, 供管理员使用
+func (s *SpaceTmplService) GetAllTmpl() ([]*model.SpaceTemplate, []*model.TmplKind) {
+	return s.tmplCache.GetAllTmpl(), s.tmplCache.GetAllKinds()
+}
+
 func (s *SpaceTmplService) GetAllSpec() ([]*model.SpaceSpec, error) {
 	return s.specCache.GetAll(), nil
 }
+
+// CreateTmpl 创建空间模板
+func (s *SpaceTmplService) CreateTmpl(tmpl *model.SpaceTemplate) (*model.SpaceTemplate, error) {
+	if err := s.validateTmpl(tmpl); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl.Status = dao.TmplUsing
+	tmpl.CreateTime = now
+	tmpl.DeleteTime = now
+	if err := s.insertTmpl(tmpl); err != nil {
+		s.logger.Errorf("insert tmpl error:%v", err)
+		return nil, ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return tmpl, nil
+}
+
+// UpdateTmpl 修改空间模板, 修改镜像时生成一个新的版本
+// 已经创建的工作空间继续使用之前的版本, 由用户选择是否升级
+func (s *SpaceTmplService) UpdateTmpl(tmpl *model.SpaceTemplate) error {
+	old := s.tmplCache.GetTmpl(tmpl.Id)
+	if old == nil {
+		return ErrTmplNotFound
+	}
+	if err := s.validateTmpl(tmpl); err != nil {
+		return err
+	}
+
+	// 新的版本和模板在同一个事务中修改, 避免版本记录与模板的版本号不一致
+	version := nextTmplVersion(old, tmpl, time.Now())
+	if err := s.dao.UpdateTmpl(tmpl, version); err != nil {
+		s.logger.Errorf("update tmpl error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return nil
+}
+
+// 修改镜像时模板的版本号加1并返回新的版本, 否则沿用之前的版本号并返回nil
+func nextTmplVersion(old, tmpl *model.SpaceTemplate, now time.Time) *model.TmplVersion {
+	tmpl.Version = old.Version
+	if tmpl.Image == old.Image {
+		return nil
+	}
+
+	tmpl.Version++
+	return &model.TmplVersion{
+		TmplId:     tmpl.Id,
+		Version:    tmpl.Version,
+		Image:      tmpl.Image,
+		Changelog:  tmpl.Changelog,
+		CreateTime: now,
+	}
+}
+
+// DeprecateTmpl 弃用空间模板, 弃用后不能再用于创建工作空间, 但已有的工作空间不受影响
+func (s *SpaceTmplService) DeprecateTmpl(id uint32) error {
+	if s.tmplCache.GetTmpl(id) == nil {
+		return ErrTmplNotFound
+	}
+
+	if err := s.dao.UpdateTmplStatus(id, dao.TmplDeprecated); err != nil {
+		s.logger.Errorf("deprecate tmpl error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return nil
+}
+
+// DeleteTmpl 删除空间模板, 如果还有工作空间在使用该模板则不允许删除
+func (s *SpaceTmplService) DeleteTmpl(id uint32) error {
+	if s.tmplCache.GetTmpl(id) == nil {
+		return ErrTmplNotFound
+	}
+
+	count, err := s.spaceDao.FindCountByTmplId(id)
+	if err != nil {
+		s.logger.Errorf("find space count error:%v", err)
+		return ErrTmplModifyFailed
+	}
+	if count > 0 {
+		return ErrTmplInUse
+	}
+
+	if err := s.dao.DeleteTmplById(id); err != nil {
+		s.logger.Errorf("delete tmpl error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return nil
+}
+
+// 插入模板并记录模板的第一个版本
+func (s *SpaceTmplService) insertTmpl(tmpl *model.SpaceTemplate) error {
+	tmpl.Version = 1
+	id, err := s.dao.InsertTmpl(tmpl, &model.TmplVersion{
+		Version:    tmpl.Version,
+		Image:      tmpl.Image,
+		Changelog:  tmpl.Changelog,
+		CreateTime: tmpl.CreateTime,
+	})
+	if err != nil {
+		return err
+	}
+	tmpl.Id = id
+
+	return nil
+}
+
+// TmplVersions 获取模板的所有版本, 供管理员使用
+func (s *SpaceTmplService) TmplVersions(id uint32) ([]model.TmplVersion, error) {
+	if s.tmplCache.GetTmpl(id) == nil {
+		return nil, ErrTmplNotFound
+	}
+
+	versions, err := s.dao.FindVersionsByTmplId(id)
+	if err != nil {
+		s.logger.Errorf("find tmpl versions error:%v", err)
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (s *SpaceTmplService) validateTmpl(tmpl *model.SpaceTemplate) error {
+	if tmpl.Name == "" {
+		return ErrReqParamInvalid
+	}
+	if !utils.VerifyImageReference(tmpl.Image) {
+		return ErrImageInvalid
+	}
+	if s.tmplCache.GetKind(tmpl.KindId) == nil {
+		return ErrKindNotFound
+	}
+	if !tmpl.Hooks.Valid() {
+		return ErrHooksInvalid
+	}
+	if !tmpl.Egress.Valid() {
+		return ErrEgressInvalid
+	}
+
+	return nil
+}
+
+// CreateKind 创建模板类别
+func (s *SpaceTmplService) CreateKind(kind *model.TmplKind) (*model.TmplKind, error) {
+	if kind.Name == "" {
+		return nil, ErrReqParamInvalid
+	}
+
+	id, err := s.dao.InsertKind(kind)
+	if err != nil {
+		s.logger.Errorf("insert kind error:%v", err)
+		return nil, ErrTmplModifyFailed
+	}
+	kind.Id = id
+
+	s.refreshTmplCache()
+
+	return kind, nil
+}
+
+// UpdateKind 修改模板类别名称
+func (s *SpaceTmplService) UpdateKind(kind *model.TmplKind) error {
+	if kind.Name == "" {
+		return ErrReqParamInvalid
+	}
+	if s.tmplCache.GetKind(kind.Id) == nil {
+		return ErrKindNotFound
+	}
+
+	if err := s.dao.UpdateKind(kind); err != nil {
+		s.logger.Errorf("update kind error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return nil
+}
+
+// DeleteKind 删除模板类别, 如果该类别下还有模板则不允许删除
+func (s *SpaceTmplService) DeleteKind(id uint32) error {
+	if s.tmplCache.GetKind(id) == nil {
+		return ErrKindNotFound
+	}
+
+	count, err := s.dao.FindTmplCountByKindId(id)
+	if err != nil {
+		s.logger.Errorf("find tmpl count error:%v", err)
+		return ErrTmplModifyFailed
+	}
+	if count > 0 {
+		return ErrKindInUse
+	}
+
+	if err := s.dao.DeleteKindById(id); err != nil {
+		s.logger.Errorf("delete kind error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshTmplCache()
+
+	return nil
+}
+
+// CreateSpec 创建空间规格
+func (s *SpaceTmplService) CreateSpec(spec *model.SpaceSpec) (*model.SpaceSpec, error) {
+	if err := validateSpec(spec); err != nil {
+		return nil, err
+	}
+
+	id, err := s.dao.InsertSpec(spec)
+	if err != nil {
+		s.logger.Errorf("insert spec error:%v", err)
+		return nil, ErrTmplModifyFailed
+	}
+	spec.Id = id
+
+	s.refreshSpecCache()
+
+	return spec, nil
+}
+
+// UpdateSpec 修改空间规格, 运行中的工作空间在下次启动时才会使用新的规格
+func (s *SpaceTmplService) UpdateSpec(spec *model.SpaceSpec) error {
+	if s.specCache.Get(spec.Id) == nil {
+		return ErrSpecNotFound
+	}
+	if err := validateSpec(spec); err != nil {
+		return err
+	}
+
+	if err := s.dao.UpdateSpec(spec); err != nil {
+		s.logger.Errorf("update spec error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshSpecCache()
+
+	return nil
+}
+
+// DeleteSpec 删除空间规格, 如果还有工作空间在使用该规格则不允许删除
+func (s *SpaceTmplService) DeleteSpec(id uint32) error {
+	if s.specCache.Get(id) == nil {
+		return ErrSpecNotFound
+	}
+
+	count, err := s.spaceDao.FindCountBySpecId(id)
+	if err != nil {
+		s.logger.Errorf("find space count error:%v", err)
+		return ErrTmplModifyFailed
+	}
+	if count > 0 {
+		return ErrSpecInUse
+	}
+
+	if err := s.dao.DeleteSpecById(id); err != nil {
+		s.logger.Errorf("delete spec error:%v", err)
+		return ErrTmplModifyFailed
+	}
+
+	s.refreshSpecCache()
+
+	return nil
+}
+
+// validateSpec 校验规格中的cpu、内存和存储是否为合法的k8s资源数量
+func validateSpec(spec *model.SpaceSpec) error {
+	if spec.Name == "" {
+		return ErrReqParamInvalid
+	}
+
+	for _, q := range []string{spec.CpuSpec, spec.MemSpec, spec.StorageSpec} {
+		quantity, err := resource.ParseQuantity(q)
+		if err != nil || quantity.Sign() <= 0 {
+			return ErrSpecInvalid
+		}
+	}
+	if !spec.Scheduling.Valid() {
+		return ErrSpecInvalid
+	}
+
+	return nil
+}
+
+// 修改数据库后立即刷新缓存, 而不是等待下一次定时刷新
+func (s *SpaceTmplService) refreshTmplCache() {
+	if err := s.tmplCache.Refresh(); err != nil {
+		s.logger.Errorf("refresh tmpl cache error:%v", err)
+		return
+	}
+
+	// 模板的镜像可能发生了变化, 同步需要预拉取的镜像
+	go s.syncPrePullImages()
+}
+
+func (s *SpaceTmplService) refreshSpecCache() {
+	if err := s.specCache.Refresh(); err != nil {
+		s.logger.Errorf("refresh spec cache error:%v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+)
+
+func TestValidateSpec(t *testing.T) {
+	valid := func() *model.SpaceSpec {
+		return &model.SpaceSpec{Name: "2C4G", CpuSpec: "2", MemSpec: "4Gi", StorageSpec: "8Gi"}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(spec *model.SpaceSpec)
+		err    error
+	}{
+		{name: "valid", modify: func(spec *model.SpaceSpec) {}},
+		{name: "millicpu", modify: func(spec *model.SpaceSpec) { spec.CpuSpec = "500m" }},
+		{name: "empty name", modify: func(spec *model.SpaceSpec) { spec.Name = "" }, err: ErrReqParamInvalid},
+		{name: "invalid cpu", modify: func(spec *model.SpaceSpec) { spec.CpuSpec = "two" }, err: ErrSpecInvalid},
+		{name: "zero cpu", modify: func(spec *model.SpaceSpec) { spec.CpuSpec = "0" }, err: ErrSpecInvalid},
+		{name: "negative memory", modify: func(spec *model.SpaceSpec) { spec.MemSpec = "-4Gi" }, err: ErrSpecInvalid},
+		{name: "invalid memory", modify: func(spec *model.SpaceSpec) { spec.MemSpec = "4G-" }, err: ErrSpecInvalid},
+		{name: "empty storage", modify: func(spec *model.SpaceSpec) { spec.StorageSpec = "" }, err: ErrSpecInvalid},
+		{name: "invalid toleration", modify: func(spec *model.SpaceSpec) {
+			spec.Scheduling.Tolerations = []model.Toleration{{Key: "gpu", Operator: "In"}}
+		}, err: ErrSpecInvalid},
+		{name: "invalid topology spread", modify: func(spec *model.SpaceSpec) {
+			spec.Scheduling.TopologySpread = []model.TopologySpread{{TopologyKey: "topology.kubernetes.io/zone"}}
+		}, err: ErrSpecInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := valid()
+			tt.modify(spec)
+			if err := validateSpec(spec); err != tt.err {
+				t.Errorf("validateSpec() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidateTmplImage(t *testing.T) {
+	s := &SpaceTmplService{}
+	tests := []struct {
+		name  string
+		image string
+		err   error
+	}{
+		{name: "", image: "code-server:go", err: ErrReqParamInvalid},
+		{name: "go", image: "", err: ErrImageInvalid},
+		{name: "go", image: "Code Server", err: ErrImageInvalid},
+		{name: "go", image: "registry.example.com/ide/code-server:go:1.20", err: ErrImageInvalid},
+	}
+
+	// 名称和镜像在查询模板类别之前校验
+	for _, tt := range tests {
+		if err := s.validateTmpl(&model.SpaceTemplate{Name: tt.name, Image: tt.image}); err != tt.err {
+			t.Errorf("validateTmpl(%q, %q) = %v, want %v", tt.name, tt.image, err, tt.err)
+		}
+	}
+}
+
+func TestNextTmplVersion(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	old := &model.SpaceTemplate{Id: 3, Image: "code-server:go1.19", Version: 2}
+
+	// 只修改描述时沿用之前的版本
+	tmpl := &model.SpaceTemplate{Id: 3, Image: "code-server:go1.19", Desc: "Go 1.19"}
+	if v := nextTmplVersion(old, tmpl, now); v != nil || tmpl.Version != 2 {
+		t.Errorf("unchanged image: version %+v, tmpl version %d", v, tmpl.Version)
+	}
+
+	// 修改镜像时生成新的版本
+	tmpl = &model.SpaceTemplate{Id: 3, Image: "code-server:go1.20", Changelog: "upgrade to go1.20"}
+	v := nextTmplVersion(old, tmpl, now)
+	if v == nil || tmpl.Version != 3 {
+		t.Fatalf("changed image: version %+v, tmpl version %d", v, tmpl.Version)
+	}
+	want := model.TmplVersion{TmplId: 3, Version: 3, Image: "code-server:go1.20", Changelog: "upgrade to go1.20", CreateTime: now}
+	if *v != want {
+		t.Errorf("version = %+v, want %+v", *v, want)
+	}
+}
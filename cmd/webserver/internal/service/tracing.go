@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/mangohow/cloud-ide/cmd/webserver/internal/service")
+
+// 为工作空间的操作创建span, 父span为http请求的span
+func startSpan(ctx context.Context, name string, id uint32, uid string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attribute.Int64("space.id", int64(id)), attribute.String("uid", uid)))
+}
+
+// 根据err设置span的状态并结束span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// 调用control-plane时只保留链路追踪上下文, 用户断开连接时不取消正在进行的创建或者启动
+func withoutCancel(ctx context.Context) context.Context {
+	c := trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+	return baggage.ContextWithBaggage(c, baggage.FromContext(ctx))
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/conf"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+)
+
+// 定时清理回收站中超过保留时间的工作空间
+const trashPurgeInterval = time.Hour
+
+var ErrTrashExpired = errors.New("workspace retention expired")
+
+// 根据配置获取删除工作空间时的保留策略, 保留天数小于等于0时直接删除
+func retentionPolicy() (pb.RequestDelete_Retention, int32) {
+	days := conf.RetentionConfig.Days
+	if days <= 0 {
+		return pb.RequestDelete_Delete, 0
+	}
+
+	switch strings.ToLower(conf.RetentionConfig.Policy) {
+	case "retain":
+		return pb.RequestDelete_Retain, int32(days)
+	case "snapshot":
+		return pb.RequestDelete_Snapshot, int32(days)
+	}
+
+	return pb.RequestDelete_Delete, 0
+}
+
+// ListTrash 列出用户回收站中的工作空间
+func (c *CloudCodeService) ListTrash(userId uint32) ([]model.TrashSpace, error) {
+	spaces, err := c.dao.FindTrashByUserId(userId)
+	if err != nil {
+		c.logger.Warnf("find trash spaces error:%v", err)
+		return nil, err
+	}
+
+	trash := make([]model.TrashSpace, 0, len(spaces))
+	for _, space := range spaces {
+		if t := c.tmplCache.GetTmpl(space.TmplId); t != nil {
+			space.Environment = t.Desc
+			space.Avatar = t.Avatar
+		}
+		if spec := c.specCache.Get(space.SpecId); spec != nil {
+			space.Spec = *spec
+			space.Spec.Id = 0
+		}
+		trash = append(trash, model.TrashSpace{Space: space, ExpireTime: space.RetainUntil})
+	}
+
+	return trash, nil
+}
+
+// RestoreWorkspace 从回收站中恢复工作空间
+// 恢复后工作空间的状态为未创建, 下一次启动时controller会使用保留的存储卷或者快照创建工作空间
+func (c *CloudCodeService) RestoreWorkspace(id, userId uint32) error {
+	// 1、查询工作空间并确保在回收站中
+	space, err := c.dao.FindByIdAndUserId(id, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrWorkSpaceNotExist
+		}
+		c.logger.Warnf("find space error:%v", err)
+		return err
+	}
+	if space.Status != model.SpaceStatusTrash {
+		return ErrWorkSpaceNotExist
+	}
+	// 使用放入回收站时记录的保留截止时间, 与controller删除保留数据的时间一致
+	if !time.Now().Before(space.RetainUntil) {
+		return ErrTrashExpired
+	}
+
+	// 2、验证工作空间的数量和名称
+	count, err := c.dao.FindCountByUserId(userId)
+	if err != nil {
+		c.logger.Warnf("get space count error:%v", err)
+		return err
+	}
+	if count >= MaxSpaceCount {
+		return ErrReachMaxSpaceCount
+	}
+	if err := c.dao.FindByUserIdAndName(userId, space.Name); err == nil {
+		return ErrNameDuplicate
+	}
+
+	// 3、修改状态为未创建
+	if err := c.dao.UpdateStatusById(id, model.SpaceStatusUncreated); err != nil {
+		c.logger.Errorf("restore space error:%v", err)
+		return err
+	}
+
+	return nil
+}
+
+// 定时将回收站中超过保留时间的工作空间标记为已删除, 保留的存储卷和快照由controller删除
+func (c *CloudCodeService) startTrashPurge() {
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for {
+			c.purgeTrash(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+func (c *CloudCodeService) purgeTrash(now time.Time) {
+	ids, err := c.dao.FindTrashBefore(now)
+	if err != nil {
+		c.logger.Errorf("find expired trash error:%v", err)
+		return
+	}
+
+	for _, id := range ids {
+		if err := c.envs.DeleteSpaceEnvs(id); err != nil {
+			c.logger.Warnf("delete space envs error:%v", err)
+		}
+		if err := c.dao.DeleteSpaceById(id); err != nil {
+			c.logger.Errorf("purge trash space error:%v", err)
+		}
+	}
+}
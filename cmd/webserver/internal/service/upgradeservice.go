@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
+	"github.com/mangohow/cloud-ide/pkg/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNoUpgrade  = errors.New("workspace is already the latest version")
+	ErrNoRollback = errors.New("workspace has no previous version")
+	ErrSpaceImage = errors.New("update workspace image failed")
+)
+
+// SpaceVersions 获取工作空间使用的模板版本以及模板所有版本的更新说明
+func (c *CloudCodeService) SpaceVersions(id, userId uint32) (*model.SpaceVersions, error) {
+	space, tmpl, err := c.findSpaceTmpl(id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := c.tmplDao.FindVersionsByTmplId(tmpl.Id)
+	if err != nil {
+		c.logger.Errorf("find tmpl versions error:%v", err)
+		return nil, err
+	}
+	// 公共模板的镜像不返回给用户
+	for i := range versions {
+		versions[i].Image = ""
+	}
+
+	return &model.SpaceVersions{
+		Current:  space.TmplVersion,
+		Previous: space.PrevVersion,
+		Versions: versions,
+	}, nil
+}
+
+// UpgradeWorkspace 将工作空间升级到模板的最新版本, 升级后可以回滚到之前的版本
+func (c *CloudCodeService) UpgradeWorkspace(ctx context.Context, id, userId uint32, uid string) error {
+	space, tmpl, err := c.findSpaceTmpl(id, userId)
+	if err != nil {
+		return err
+	}
+	if space.TmplVersion >= tmpl.Version {
+		return ErrNoUpgrade
+	}
+
+	if err := c.updateImage(ctx, space, uid, tmpl.Image); err != nil {
+		return err
+	}
+
+	return c.dao.UpdateVersionById(id, tmpl.Version, space.TmplVersion)
+}
+
+// RollbackWorkspace 将工作空间回滚到升级之前的版本
+func (c *CloudCodeService) RollbackWorkspace(ctx context.Context, id, userId uint32, uid string) error {
+	space, tmpl, err := c.findSpaceTmpl(id, userId)
+	if err != nil {
+		return err
+	}
+	if space.PrevVersion == 0 {
+		return ErrNoRollback
+	}
+
+	image, err := c.versionImage(tmpl, space.PrevVersion)
+	if err != nil {
+		c.logger.Errorf("get tmpl version error:%v", err)
+		return ErrNoRollback
+	}
+	if err := c.updateImage(ctx, space, uid, image); err != nil {
+		return err
+	}
+
+	return c.dao.UpdateVersionById(id, space.PrevVersion, 0)
+}
+
+func (c *CloudCodeService) findSpaceTmpl(id, userId uint32) (*model.Space, *model.SpaceTemplate, error) {
+	space, err := c.dao.FindByIdAndUserId(id, userId)
+	if err != nil || space.Status == model.SpaceStatusDeleted || space.Status == model.SpaceStatusTrash {
+		return nil, nil, ErrWorkSpaceNotExist
+	}
+	tmpl := c.tmplCache.GetTmpl(space.TmplId)
+	if tmpl == nil {
+		return nil, nil, ErrTmplNotFound
+	}
+
+	return space, tmpl, nil
+}
+
+// 获取模板某个版本的镜像, 版本为当前版本时直接使用模板的镜像
+func (c *CloudCodeService) versionImage(tmpl *model.SpaceTemplate, version uint32) (string, error) {
+	if version == 0 || version == tmpl.Version {
+		return tmpl.Image, nil
+	}
+
+	v, err := c.tmplDao.FindVersion(tmpl.Id, version)
+	if err != nil {
+		return "", err
+	}
+
+	return v.Image, nil
+}
+
+// 修改工作空间的镜像, 未创建的工作空间在创建时使用对应版本的镜像
+// 运行中的工作空间会在宽限时间之后使用新的镜像重新启动
+func (c *CloudCodeService) updateImage(ctx context.Context, space *model.Space, uid, image string) error {
+	if space.Status == model.SpaceStatusUncreated {
+		return nil
+	}
+
+	ctx, cancelFunc := context.WithTimeout(withoutCancel(ctx), time.Second*30)
+	defer cancelFunc()
+	_, err := c.rpc.UpdateImage(ctx, &pb.RequestUpdateImage{
+		Sid:   space.Sid,
+		Uid:   uid,
+		Image: image,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return ErrSpaceNotFound
+		}
+		c.logger.Errorf("update workspace image error:%v", err)
+		return ErrSpaceImage
+	}
+
+	return nil
+}
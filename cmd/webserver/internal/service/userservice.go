@@ -8,6 +8,7 @@ import (
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/model"
 	"github.com/mangohow/cloud-ide/pkg/logger"
+	"github.com/mangohow/cloud-ide/pkg/utils"
 	"github.com/mangohow/cloud-ide/pkg/utils/encrypt"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/mgo.v2/bson"
@@ -110,3 +111,36 @@ func (u *UserService) UserRegister(info *model.RegisterInfo) error {
 
 	return nil
 }
+
+var ErrDotfilesInvalid = errors.New("dotfiles invalid")
+
+// GetDotfiles 获取用户的dotfiles仓库
+func (u *UserService) GetDotfiles(userId uint32) (*model.Dotfiles, error) {
+	dotfiles, err := u.dao.FindDotfilesById(userId)
+	if err != nil {
+		u.logger.Errorf("find dotfiles error:%v", err)
+		return nil, err
+	}
+
+	return dotfiles, nil
+}
+
+// SetDotfiles 设置用户的dotfiles仓库, 仓库地址为空时表示不使用dotfiles
+func (u *UserService) SetDotfiles(userId uint32, dotfiles *model.Dotfiles) error {
+	if dotfiles.Repository != "" && !utils.VerifyGitRepository(dotfiles.Repository) {
+		return ErrDotfilesInvalid
+	}
+	if dotfiles.Ref != "" && !utils.VerifyGitRef(dotfiles.Ref) {
+		return ErrDotfilesInvalid
+	}
+	if dotfiles.Repository == "" {
+		dotfiles.Ref = ""
+	}
+
+	if err := u.dao.UpdateDotfilesById(userId, dotfiles); err != nil {
+		u.logger.Errorf("update dotfiles error:%v", err)
+		return err
+	}
+
+	return nil
+}
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"syscall"
+	"time"
 
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/conf"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao/db"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/dao/rdis"
 	"github.com/mangohow/cloud-ide/cmd/webserver/internal/routes"
+	"github.com/mangohow/cloud-ide/cmd/webserver/internal/service"
 	"github.com/mangohow/cloud-ide/pkg/httpserver"
 	"github.com/mangohow/cloud-ide/pkg/logger"
 	"github.com/mangohow/cloud-ide/pkg/router"
+	"github.com/mangohow/cloud-ide/pkg/tracing"
 )
 
 func main() {
@@ -24,11 +28,26 @@ func main() {
 		panic(fmt.Errorf("init logger error, reason:%v", err))
 	}
 
+	// 初始化链路追踪, 需要在创建grpc客户端之前设置全局的Propagator
+	shutdownTracing, err := tracing.Init(tracing.Options{
+		ServiceName: "webserver",
+		Endpoint:    conf.TracingConfig.Endpoint,
+		SampleRatio: conf.TracingConfig.SampleRatio,
+	})
+	if err != nil {
+		panic(fmt.Errorf("init tracing failed, reason:%v", err))
+	}
+
 	// 初始化数据库
 	if err := db.InitMysql(); err != nil {
 		panic(fmt.Errorf("init mysql failed, reason:%s", err.Error()))
 	}
 
+	// 初始化加密用户敏感数据的密钥
+	if err := service.InitSecretCipher(conf.ServerConfig.SecretKey); err != nil {
+		panic(fmt.Errorf("init secret cipher failed, reason:%s", err.Error()))
+	}
+
 	// 创建gin路由
 	engine := router.NewGinRouter(conf.ServerConfig.Mode)
 	// 注册路由
@@ -48,5 +67,11 @@ func main() {
 		if conf.EmailConfig.Enabled {
 			rdis.CloseRedisConn()
 		}
+		// 导出剩余的span
+		ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancelFunc()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Logger().Errorf("shutdown tracing error:%v", err)
+		}
 	})
 }
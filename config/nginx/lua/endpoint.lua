@@ -37,9 +37,12 @@ if method == "POST" then
         ngx.log(ngx.ERR, "Failed to save data to shared memory:", err)
         return ngx.exit(ngx.HTTP_BAD_REQUEST)
     end
+    -- 工作空间重新启动后, 之前的通知已经失效
+    ngx.shared.notices:delete(req.sid)
 elseif method == "DELETE" then    
     if not req.sid then
         return ngx.exit(ngx.HTTP_BAD_REQUEST)
     end  
     eps:delete(req.sid)
+    ngx.shared.notices:delete(req.sid)
 end
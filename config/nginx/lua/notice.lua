@@ -0,0 +1,50 @@
+-- 判断method
+local method = ngx.req.get_method()
+if method ~= "PUT" and method ~= "DELETE" then
+    return ngx.exit(ngx.HTTP_BAD_REQUEST)
+end
+
+-- 验证Token
+local token = ngx.req.get_headers()["token"]
+if not token then
+    ngx.exit(ngx.HTTP_UNAUTHORIZED)
+end
+
+if token ~= ngx.var.token then
+    ngx.exit(ngx.HTTP_UNAUTHORIZED)
+end
+
+-- 获取body
+ngx.req.read_body()
+local body = ngx.req.get_body_data()
+if not body then
+    return ngx.exit(ngx.HTTP_BAD_REQUEST)
+end
+
+local cjson = require("cjson")
+local req = cjson.decode(body)
+if not req.sid then
+    return ngx.exit(ngx.HTTP_BAD_REQUEST)
+end
+
+local notices = ngx.shared.notices
+
+if method == "PUT" then
+    if not req.notice then
+        return ngx.exit(ngx.HTTP_BAD_REQUEST)
+    end
+
+    -- 通知在截止时间之后再保留一段时间, 然后自动删除
+    local ttl = 3600
+    if req.notice.deadline and req.notice.deadline > 0 then
+        ttl = math.max(req.notice.deadline - ngx.time(), 0) + 300
+    end
+
+    local success, err = notices:set(req.sid, cjson.encode(req.notice), ttl)
+    if not success then
+        ngx.log(ngx.ERR, "Failed to save data to shared memory:", err)
+        return ngx.exit(ngx.HTTP_BAD_REQUEST)
+    end
+elseif method == "DELETE" then
+    notices:delete(req.sid)
+end
@@ -0,0 +1,44 @@
+--[[
+    IDE会话通过 GET /notice/sid?wait=秒数 获取工作空间的通知
+    没有通知时, 最多等待wait秒(不超过30秒), 仍然没有通知则返回204
+    请求头token为使用gateway token对sid计算的HMAC-SHA1的base64编码, 由control-plane通过环境变量传递给IDE
+--]]
+
+if ngx.req.get_method() ~= "GET" then
+    return ngx.exit(ngx.HTTP_BAD_REQUEST)
+end
+
+local sid = string.match(ngx.var.uri, "^/notice/([%w-]+)/?$")
+if not sid then
+    return ngx.exit(ngx.HTTP_NOT_FOUND)
+end
+
+-- 验证Token, 每个工作空间只能获取自己的通知
+local token = ngx.req.get_headers()["token"]
+if not token or token ~= ngx.encode_base64(ngx.hmac_sha1(ngx.var.token, sid)) then
+    return ngx.exit(ngx.HTTP_UNAUTHORIZED)
+end
+
+-- 只有正在运行的工作空间才有通知
+if not ngx.shared.endpoints:get(sid) then
+    return ngx.exit(ngx.HTTP_NOT_FOUND)
+end
+
+local wait = tonumber(ngx.var.arg_wait) or 0
+wait = math.min(math.max(wait, 0), 30)
+
+local notices = ngx.shared.notices
+local notice = notices:get(sid)
+while not notice and wait > 0 do
+    ngx.sleep(1)
+    wait = wait - 1
+    notice = notices:get(sid)
+end
+
+if not notice then
+    return ngx.exit(ngx.HTTP_NO_CONTENT)
+end
+
+ngx.header["Content-Type"] = "application/json"
+ngx.header["Cache-Control"] = "no-store"
+ngx.say(notice)
@@ -0,0 +1,27 @@
+--[[
+    为没有链路追踪上下文的请求生成W3C traceparent, webserver以此作为请求span的父span
+    格式为 00-<32位十六进制trace id>-<16位十六进制span id>-<flags>, flags为01时表示采样
+--]]
+
+local traceparent = ngx.var.http_traceparent
+-- 客户端已经传递了合法的traceparent时直接使用
+if traceparent and string.match(traceparent, '^%x%x%-%x+%-%x+%-%x%x$') and #traceparent == 55 then
+    return
+end
+
+local random = require("resty.random")
+local str = require("resty.string")
+
+local trace_id = str.to_hex(random.bytes(16))
+local span_id = str.to_hex(random.bytes(8))
+
+-- 根据采样比例决定是否采样, 下游服务跟随网关的采样决定
+local ratio = tonumber(ngx.var.trace_sample_ratio) or 1
+local b = random.bytes(2)
+local n = (string.byte(b, 1) * 256 + string.byte(b, 2)) / 65536
+local flags = '00'
+if n < ratio then
+    flags = '01'
+end
+
+ngx.req.set_header('traceparent', '00-' .. trace_id .. '-' .. span_id .. '-' .. flags)
@@ -21,6 +21,23 @@ USE cloudide;
 SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
+-- ----------------------------
+-- Table structure for t_git_credential
+-- ----------------------------
+DROP TABLE IF EXISTS `t_git_credential`;
+CREATE TABLE `t_git_credential`  (
+  `id` int(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键id',
+  `user_id` int(0) UNSIGNED NOT NULL COMMENT '用户id',
+  `host` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'git仓库的主机名',
+  `type` int(0) NOT NULL DEFAULT 0 COMMENT '凭证类型 0 https token 1 ssh私钥',
+  `username` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'https使用的用户名',
+  `secret` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '加密后的token或者私钥',
+  `known_hosts` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'ssh主机的公钥, 格式与known_hosts文件相同',
+  `create_time` datetime(0) NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_user_id_host`(`user_id`, `host`) USING BTREE COMMENT '每个主机只能设置一个凭证'
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for t_space
 -- ----------------------------
@@ -29,13 +46,21 @@ CREATE TABLE `t_space`  (
   `id` int(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键id',
   `user_id` int(0) UNSIGNED NOT NULL COMMENT '用户id',
   `tmpl_id` int(0) UNSIGNED NOT NULL COMMENT '模板id',
+  `tmpl_version` int(0) UNSIGNED NOT NULL DEFAULT 1 COMMENT '使用的模板版本',
+  `prev_version` int(0) UNSIGNED NOT NULL DEFAULT 0 COMMENT '升级之前的模板版本 0表示不能回滚',
   `spec_id` int(0) UNSIGNED NOT NULL COMMENT '空间规格id',
   `sid` char(24) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT 'space id',
   `name` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '空间名称',
-  `git_repository` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '要克隆的git仓库',
-  `status` int(0) NOT NULL COMMENT '空间状态 0 已删除 1 可用 2 未创建',
+  `git_repository` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '要克隆的git仓库',
+  `git_ref` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '要克隆的分支、标签或者commit',
+  `git_depth` int(0) NOT NULL DEFAULT 0 COMMENT '浅克隆深度 0表示完整克隆',
+  `repositories` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '额外克隆的git仓库, json格式',
+  `hooks` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '生命周期钩子, json格式',
+  `schedule` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '定时启动和停止, json格式',
+  `status` int(0) NOT NULL COMMENT '空间状态 0 已删除 1 可用 2 未创建 3 回收站',
   `create_time` datetime(0) NOT NULL COMMENT '创建时间',
   `delete_time` datetime(0) NOT NULL COMMENT '删除时间',
+  `retain_until` datetime(0) NOT NULL COMMENT '回收站中数据的保留截止时间',
   `stop_time` datetime(0) NOT NULL COMMENT '停止时间',
   `total_time` bigint(0) NOT NULL COMMENT '总运行时间',
   PRIMARY KEY (`id`) USING BTREE,
@@ -46,6 +71,22 @@ CREATE TABLE `t_space`  (
 -- Records of t_space
 -- ----------------------------
 
+-- ----------------------------
+-- Table structure for t_space_env
+-- ----------------------------
+DROP TABLE IF EXISTS `t_space_env`;
+CREATE TABLE `t_space_env`  (
+  `id` int(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键id',
+  `user_id` int(0) UNSIGNED NOT NULL COMMENT '用户id',
+  `space_id` int(0) UNSIGNED NOT NULL DEFAULT 0 COMMENT '空间id 0表示用户的默认环境变量',
+  `name` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '环境变量名称',
+  `value` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '加密后的环境变量值',
+  `secret` tinyint(1) NOT NULL DEFAULT 0 COMMENT '是否为敏感数据',
+  `create_time` datetime(0) NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_user_id_space_id_name`(`user_id`, `space_id`, `name`) USING BTREE COMMENT '同一空间下环境变量名称唯一'
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = Dynamic;
+
 -- ----------------------------
 -- Table structure for t_space_template
 -- ----------------------------
@@ -57,22 +98,53 @@ CREATE TABLE `t_space_template`  (
   `desc` varchar(256) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '描述',
   `tags` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '标签，使用|隔开',
   `image` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '镜像名称',
-  `status` int(0) NOT NULL DEFAULT 0 COMMENT '状态 0可用 1已删除',
+  `status` int(0) NOT NULL DEFAULT 0 COMMENT '状态 0可用 1已删除 2已弃用',
   `avatar` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '头像',
+  `user_id` int(0) UNSIGNED NOT NULL DEFAULT 0 COMMENT '创建该模板的用户id, 0为公共模板',
+  `pull_secret` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '拉取私有镜像使用的Secret名称',
+  `hooks` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '生命周期钩子, json格式',
+  `egress` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '出站白名单, json格式',
+  `version` int(0) UNSIGNED NOT NULL DEFAULT 1 COMMENT '当前版本, 每次修改镜像时加1',
   `create_time` datetime(0) NOT NULL COMMENT '创建时间',
   `delete_time` datetime(0) NOT NULL COMMENT '删除时间',
-  PRIMARY KEY (`id`) USING BTREE
+  PRIMARY KEY (`id`) USING BTREE,
+  INDEX `idx_user_id`(`user_id`) USING BTREE COMMENT '用户id索引'
 ) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of t_space_template
 -- ----------------------------
-INSERT INTO `t_space_template` VALUES (1, 1, 'Go', 'go workspace with go 1.21.3, make', 'Go,Make,Git', 'registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-go:v1.21', 0, 'images/go.png', '2022-12-08 16:53:45', '2022-12-08 16:53:47');
-INSERT INTO `t_space_template` VALUES (2, 1, 'Node.js', 'js workspace', 'Node.js', 'node.js', 0, 'images/nodejs.png', '2022-12-11 21:18:22', '2022-12-11 21:18:24');
-INSERT INTO `t_space_template` VALUES (3, 1, 'C/C++', 'c/c++ workspace with gcc g++ make cmake git', 'C,CPP,Make,Git', 'registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-cxx:v1.0', 0, 'images/cpp.png', '2022-12-11 22:40:28', '2022-12-11 22:40:30');
-INSERT INTO `t_space_template` VALUES (4, 1, 'Java', 'java workspace', 'Java', 'java', 0, 'images/java.png', '2023-02-26 16:56:43', '2023-02-26 16:57:33');
-INSERT INTO `t_space_template` VALUES (5, 1, 'Vue', 'Vue workspace', 'Vue,Yarn', 'Vue', 0, 'images/vue.png', '2023-02-26 17:05:18', '2023-02-26 17:05:20');
-INSERT INTO `t_space_template` VALUES (6, 1, 'Python', 'python workspace', 'Python', 'Python', 0, 'images/python.png', '2023-02-26 17:05:45', '2023-02-26 17:05:48');
+INSERT INTO `t_space_template` VALUES (1, 1, 'Go', 'go workspace with go 1.21.3, make', 'Go,Make,Git', 'registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-go:v1.21', 0, 'images/go.png', 0, '', '', '', 1, '2022-12-08 16:53:45', '2022-12-08 16:53:47');
+INSERT INTO `t_space_template` VALUES (2, 1, 'Node.js', 'js workspace', 'Node.js', 'node.js', 0, 'images/nodejs.png', 0, '', '', '', 1, '2022-12-11 21:18:22', '2022-12-11 21:18:24');
+INSERT INTO `t_space_template` VALUES (3, 1, 'C/C++', 'c/c++ workspace with gcc g++ make cmake git', 'C,CPP,Make,Git', 'registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-cxx:v1.0', 0, 'images/cpp.png', 0, '', '', '', 1, '2022-12-11 22:40:28', '2022-12-11 22:40:30');
+INSERT INTO `t_space_template` VALUES (4, 1, 'Java', 'java workspace', 'Java', 'java', 0, 'images/java.png', 0, '', '', '', 1, '2023-02-26 16:56:43', '2023-02-26 16:57:33');
+INSERT INTO `t_space_template` VALUES (5, 1, 'Vue', 'Vue workspace', 'Vue,Yarn', 'Vue', 0, 'images/vue.png', 0, '', '', '', 1, '2023-02-26 17:05:18', '2023-02-26 17:05:20');
+INSERT INTO `t_space_template` VALUES (6, 1, 'Python', 'python workspace', 'Python', 'Python', 0, 'images/python.png', 0, '', '', '', 1, '2023-02-26 17:05:45', '2023-02-26 17:05:48');
+
+-- ----------------------------
+-- Table structure for t_space_template_version
+-- ----------------------------
+DROP TABLE IF EXISTS `t_space_template_version`;
+CREATE TABLE `t_space_template_version`  (
+  `id` int(0) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键id',
+  `tmpl_id` int(0) UNSIGNED NOT NULL COMMENT '模板id',
+  `version` int(0) UNSIGNED NOT NULL COMMENT '版本号',
+  `image` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '镜像名称',
+  `changelog` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '更新说明',
+  `create_time` datetime(0) NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`) USING BTREE,
+  UNIQUE INDEX `idx_tmpl_id_version`(`tmpl_id`, `version`) USING BTREE COMMENT '模板id和版本号唯一'
+) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = Dynamic;
+
+-- ----------------------------
+-- Records of t_space_template_version
+-- ----------------------------
+INSERT INTO `t_space_template_version` VALUES (1, 1, 1, 'registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-go:v1.21', '初始版本', '2022-12-08 16:53:45');
+INSERT INTO `t_space_template_version` VALUES (2, 2, 1, 'node.js', '初始版本', '2022-12-11 21:18:22');
+INSERT INTO `t_space_template_version` VALUES (3, 3, 1, 'registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-cxx:v1.0', '初始版本', '2022-12-11 22:40:28');
+INSERT INTO `t_space_template_version` VALUES (4, 4, 1, 'java', '初始版本', '2023-02-26 16:56:43');
+INSERT INTO `t_space_template_version` VALUES (5, 5, 1, 'Vue', '初始版本', '2023-02-26 17:05:18');
+INSERT INTO `t_space_template_version` VALUES (6, 6, 1, 'Python', '初始版本', '2023-02-26 17:05:45');
 
 -- ----------------------------
 -- Table structure for t_spacespec
@@ -85,16 +157,17 @@ CREATE TABLE `t_spacespec`  (
   `storage_spec` varchar(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '存储规格',
   `name` varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '名称',
   `desc` varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '描述',
+  `scheduling` text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NULL COMMENT '调度约束, json格式',
   PRIMARY KEY (`id`) USING BTREE
 ) ENGINE = InnoDB CHARACTER SET = utf8mb4 COLLATE = utf8mb4_0900_ai_ci ROW_FORMAT = Dynamic;
 
 -- ----------------------------
 -- Records of t_spacespec
 -- ----------------------------
-INSERT INTO `t_spacespec` VALUES (1, '2', '4Gi', '8Gi', '标准型', '标准型 2CPU 4GB / 8GB存储 ');
-INSERT INTO `t_spacespec` VALUES (2, '4', '8Gi', '16Gi', '增强型', '计算型 4CPU 4GB内存 / 16GB存储');
-INSERT INTO `t_spacespec` VALUES (3, '8', '16Gi', '32Gi', '专业型', '专业型 8CPU 16GB内存 / 32GB存储');
-INSERT INTO `t_spacespec` VALUES (4, '2', '2Gi', '4Gi', '测试型', '测试型 2CPU 2GB内存 / 4GB存储');
+INSERT INTO `t_spacespec` VALUES (1, '2', '4Gi', '8Gi', '标准型', '标准型 2CPU 4GB / 8GB存储 ', '');
+INSERT INTO `t_spacespec` VALUES (2, '4', '8Gi', '16Gi', '增强型', '计算型 4CPU 4GB内存 / 16GB存储', '');
+INSERT INTO `t_spacespec` VALUES (3, '8', '16Gi', '32Gi', '专业型', '专业型 8CPU 16GB内存 / 32GB存储', '');
+INSERT INTO `t_spacespec` VALUES (4, '2', '2Gi', '4Gi', '测试型', '测试型 2CPU 2GB内存 / 4GB存储', '');
 
 -- ----------------------------
 -- Table structure for t_template_kind
@@ -124,6 +197,8 @@ CREATE TABLE `t_user`  (
   `email` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '邮箱',
   `phone` char(11) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '手机号',
   `avatar` varchar(128) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT '头像',
+  `dotfiles_repository` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'dotfiles仓库',
+  `dotfiles_ref` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL DEFAULT '' COMMENT 'dotfiles仓库的分支或者标签',
   `create_time` datetime(0) NOT NULL COMMENT '创建时间',
   `delete_time` datetime(0) NOT NULL COMMENT '删除时间',
   `status` int(0) NOT NULL COMMENT '状态 0 可用 1 已注销',
@@ -1,10 +1,13 @@
 package conf
 
 type ServerConf struct {
-	Host string
-	Port int
-	Name string
-	Mode string
+	Host   string
+	Port   int
+	Name   string
+	Mode   string
+	Admins []string // 管理员用户名
+	// 加密用户数据(例如工作空间的环境变量)使用的密钥
+	SecretKey string
 }
 
 type MysqlConf struct {
@@ -38,5 +41,23 @@ type EmailConf struct {
 }
 
 type GrpcConf struct {
-	Addr string
+	Addr  string
+	Token string // 调用control-plane时携带的token, 与control-plane的-grpc-token相同
+	// 校验control-plane服务端证书的CA, 为空时使用明文
+	CAFile string
+	// 客户端证书和私钥, control-plane开启mTLS时需要
+	CertFile string
+	KeyFile  string
+}
+
+// RetentionConf 删除工作空间时数据的保留策略
+type RetentionConf struct {
+	Policy string // delete: 直接删除  retain: 保留存储卷  snapshot: 创建快照后删除存储卷
+	Days   int    // 保留天数, 在保留时间内可以从回收站中恢复
+}
+
+// TracingConf 链路追踪的配置
+type TracingConf struct {
+	Endpoint    string  // OTLP/HTTP collector的地址, 例如 http://otel-collector:4318, 为空时不导出span
+	SampleRatio float64 // 采样比例, 小于等于0或者大于等于1时全部采样
 }
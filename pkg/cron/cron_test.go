@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skip("timezone data not found")
+	}
+
+	tests := []struct {
+		spec string
+		from string
+		next string
+	}{
+		{"0 9 * * 1-5", "2023-06-02 08:59", "2023-06-02 09:00"},
+		{"0 9 * * 1-5", "2023-06-02 09:00", "2023-06-05 09:00"},
+		{"0 20 * * mon-fri", "2023-06-03 10:00", "2023-06-05 20:00"},
+		{"*/15 * * * *", "2023-06-02 10:07", "2023-06-02 10:15"},
+		{"30 8 1 * *", "2023-06-02 10:00", "2023-07-01 08:30"},
+		{"0 0 * * 7", "2023-06-02 10:00", "2023-06-04 00:00"},
+		{"0 0 29 2 *", "2023-03-01 00:00", "2024-02-29 00:00"},
+		// 日和周都指定时满足其中一个即可
+		{"0 12 15 * 1", "2023-06-06 00:00", "2023-06-12 12:00"},
+		{"5/20 10 * * *", "2023-06-02 10:30", "2023-06-02 10:45"},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.spec)
+		if err != nil {
+			t.Fatalf("parse %s: %v", test.spec, err)
+		}
+		from, _ := time.ParseInLocation("2006-01-02 15:04", test.from, shanghai)
+		next := s.Next(from).Format("2006-01-02 15:04")
+		if next != test.next {
+			t.Errorf("%s from %s: got %s, want %s", test.spec, test.from, next, test.next)
+		}
+	}
+
+	s, _ := Parse("0 0 30 2 *")
+	if next := s.Next(time.Now()); !next.IsZero() {
+		t.Errorf("0 0 30 2 * should never match, got %s", next)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"* * * * * *",
+	}
+	for _, spec := range invalid {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%q should be invalid", spec)
+		}
+	}
+}
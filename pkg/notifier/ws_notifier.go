@@ -3,13 +3,17 @@ package notifier
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/net/http2"
 	"k8s.io/client-go/util/workqueue"
 )
@@ -17,11 +21,37 @@ import (
 type Request struct {
 	Sid      string `json:"sid,omitempty"`
 	Endpoint string `json:"endpoint,omitempty"`
+	// 推送给IDE会话的通知
+	Notice *Notice `json:"notice,omitempty"`
 }
 
+const (
+	// NoticeStop 工作空间即将停止, IDE需要在Deadline之前保存未保存的文件
+	NoticeStop = "stop"
+)
+
+// Notice 通过网关推送给IDE会话的通知, IDE通过网关的/notice/{sid}接口获取
+// 获取时需要在请求头token中携带NoticeSource返回的token
+type Notice struct {
+	Type    string `json:"type"`
+	Message string `json:"message,omitempty"`
+	// unix时间戳, 单位为秒
+	Deadline int64 `json:"deadline,omitempty"`
+}
+
+// QueueName 通知队列的名称, 队列的长度等指标通过workqueue的指标暴露
+const QueueName = "workspace-notifier"
+
+// RequestFailures 请求网关失败的次数, 由使用者注册到prometheus中
+var RequestFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cloud_ide_notifier_request_failures_total",
+	Help: "Number of failed requests from the notifier to the gateway.",
+}, []string{"method"})
+
 type task struct {
 	req    Request
 	method string
+	url    string
 }
 
 // Waiter 用于等待一个Workspace的Pod处于Ready状态
@@ -37,13 +67,22 @@ type Notifier interface {
 	Logout(sid string)
 
 	Notify(sid string)
+
+	// Notice 推送通知给Workspace的IDE会话, notice为nil时撤销之前的通知
+	Notice(sid string, notice *Notice)
+
+	// NoticeSource IDE会话获取通知的地址和token
+	NoticeSource(sid string) (url, token string)
 }
 
 type WorkspaceNotifier struct {
 	logger logr.Logger
 	// 通过HTTP请求来从网关中注册或注销Workspace
-	clients []*http.Client
-	Url     string
+	clients   []*http.Client
+	Url       string
+	NoticeUrl string
+	// IDE会话获取通知的地址, 工作空间与网关不在同一个命名空间中, 需要使用完整的service名称
+	PollUrl string
 	Token   string
 
 	ctx   context.Context
@@ -54,15 +93,17 @@ type WorkspaceNotifier struct {
 	wsc map[string]chan struct{}
 }
 
-func NewWorkspaceNotifier(ctx context.Context, logger logr.Logger, svcName, path, token string, workers int) (*WorkspaceNotifier, error) {
+func NewWorkspaceNotifier(ctx context.Context, logger logr.Logger, svcName, namespace, path, noticePath, token string, workers int) (*WorkspaceNotifier, error) {
 	// https://servicename/internal/endpoint
 	w := &WorkspaceNotifier{
-		logger: logger,
-		Url:    fmt.Sprintf("https://%s%s", svcName, path),
-		Token:  token,
-		ctx:    ctx,
-		queue:  workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
-		wsc:    make(map[string]chan struct{}),
+		logger:    logger,
+		Url:       fmt.Sprintf("https://%s%s", svcName, path),
+		NoticeUrl: fmt.Sprintf("https://%s%s", svcName, noticePath),
+		PollUrl:   fmt.Sprintf("https://%s.%s/notice/", svcName, namespace),
+		Token:     token,
+		ctx:       ctx,
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter(), QueueName),
+		wsc:       make(map[string]chan struct{}),
 	}
 
 	if workers <= 0 {
@@ -100,6 +141,7 @@ func (w *WorkspaceNotifier) Login(sid, endpoint string) {
 	w.queue.Add(task{
 		req:    Request{Sid: sid, Endpoint: endpoint},
 		method: http.MethodPost,
+		url:    w.Url,
 	})
 }
 
@@ -108,9 +150,36 @@ func (w *WorkspaceNotifier) Logout(sid string) {
 	w.queue.Add(task{
 		req:    Request{Sid: sid},
 		method: http.MethodDelete,
+		url:    w.Url,
+	})
+}
+
+// Notice 将通知保存到网关中, 由IDE会话从网关获取
+func (w *WorkspaceNotifier) Notice(sid string, notice *Notice) {
+	method := http.MethodPut
+	if notice == nil {
+		method = http.MethodDelete
+	}
+
+	w.queue.Add(task{
+		req:    Request{Sid: sid, Notice: notice},
+		method: method,
+		url:    w.NoticeUrl,
 	})
 }
 
+// NoticeSource token为使用网关token对sid计算的HMAC-SHA1, 网关使用相同的方式校验, 不需要保存每个工作空间的token
+func (w *WorkspaceNotifier) NoticeSource(sid string) (string, string) {
+	return w.PollUrl + sid, NoticeToken(w.Token, sid)
+}
+
+// NoticeToken 计算IDE会话获取通知使用的token, 与网关中的ngx.encode_base64(ngx.hmac_sha1(token, sid))相同
+func NoticeToken(token, sid string) string {
+	mac := hmac.New(sha1.New, []byte(token))
+	mac.Write([]byte(sid))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
 func (w *WorkspaceNotifier) worker(i int) {
 	client := w.clients[i]
 	for {
@@ -120,22 +189,23 @@ func (w *WorkspaceNotifier) worker(i int) {
 		}
 
 		tsk := item.(task)
-		err := w.doRequest(client, tsk.req, tsk.method)
+		err := w.doRequest(client, tsk.url, tsk.req, tsk.method)
 		if err != nil {
 			w.logger.Error(err, "do request", "method", tsk.method, "sid", tsk.req.Sid)
+			RequestFailures.WithLabelValues(tsk.method).Inc()
 		} else {
 			w.queue.Done(item)
 		}
 	}
 }
 
-func (w *WorkspaceNotifier) doRequest(client *http.Client, req Request, method string) error {
+func (w *WorkspaceNotifier) doRequest(client *http.Client, url string, req Request, method string) error {
 	data, err := json.Marshal(req)
 	if err != nil {
 		return err
 	}
 	reader := bytes.NewReader(data)
-	request, err := http.NewRequest(method, w.Url, reader)
+	request, err := http.NewRequest(method, url, reader)
 	if err != nil {
 		return err
 	}
@@ -20,6 +20,150 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ResponseSetSchedule_Status int32
+
+const (
+	ResponseSetSchedule_Success  ResponseSetSchedule_Status = 0
+	ResponseSetSchedule_NotFound ResponseSetSchedule_Status = 1
+	ResponseSetSchedule_Error    ResponseSetSchedule_Status = 2
+)
+
+// Enum value maps for ResponseSetSchedule_Status.
+var (
+	ResponseSetSchedule_Status_name = map[int32]string{
+		0: "Success",
+		1: "NotFound",
+		2: "Error",
+	}
+	ResponseSetSchedule_Status_value = map[string]int32{
+		"Success":  0,
+		"NotFound": 1,
+		"Error":    2,
+	}
+)
+
+func (x ResponseSetSchedule_Status) Enum() *ResponseSetSchedule_Status {
+	p := new(ResponseSetSchedule_Status)
+	*p = x
+	return p
+}
+
+func (x ResponseSetSchedule_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResponseSetSchedule_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[0].Descriptor()
+}
+
+func (ResponseSetSchedule_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[0]
+}
+
+func (x ResponseSetSchedule_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResponseSetSchedule_Status.Descriptor instead.
+func (ResponseSetSchedule_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type ResponseUpdateImage_Status int32
+
+const (
+	ResponseUpdateImage_Success  ResponseUpdateImage_Status = 0
+	ResponseUpdateImage_NotFound ResponseUpdateImage_Status = 1
+	ResponseUpdateImage_Error    ResponseUpdateImage_Status = 2
+)
+
+// Enum value maps for ResponseUpdateImage_Status.
+var (
+	ResponseUpdateImage_Status_name = map[int32]string{
+		0: "Success",
+		1: "NotFound",
+		2: "Error",
+	}
+	ResponseUpdateImage_Status_value = map[string]int32{
+		"Success":  0,
+		"NotFound": 1,
+		"Error":    2,
+	}
+)
+
+func (x ResponseUpdateImage_Status) Enum() *ResponseUpdateImage_Status {
+	p := new(ResponseUpdateImage_Status)
+	*p = x
+	return p
+}
+
+func (x ResponseUpdateImage_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResponseUpdateImage_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[1].Descriptor()
+}
+
+func (ResponseUpdateImage_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[1]
+}
+
+func (x ResponseUpdateImage_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResponseUpdateImage_Status.Descriptor instead.
+func (ResponseUpdateImage_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{11, 0}
+}
+
+type GitCredential_Type int32
+
+const (
+	GitCredential_Https GitCredential_Type = 0
+	GitCredential_Ssh   GitCredential_Type = 1
+)
+
+// Enum value maps for GitCredential_Type.
+var (
+	GitCredential_Type_name = map[int32]string{
+		0: "Https",
+		1: "Ssh",
+	}
+	GitCredential_Type_value = map[string]int32{
+		"Https": 0,
+		"Ssh":   1,
+	}
+)
+
+func (x GitCredential_Type) Enum() *GitCredential_Type {
+	p := new(GitCredential_Type)
+	*p = x
+	return p
+}
+
+func (x GitCredential_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GitCredential_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[2].Descriptor()
+}
+
+func (GitCredential_Type) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[2]
+}
+
+func (x GitCredential_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GitCredential_Type.Descriptor instead.
+func (GitCredential_Type) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{14, 0}
+}
+
 type ResponseCreate_Status int32
 
 const (
@@ -53,11 +197,11 @@ func (x ResponseCreate_Status) String() string {
 }
 
 func (ResponseCreate_Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_pb_proto_service_proto_enumTypes[0].Descriptor()
+	return file_pb_proto_service_proto_enumTypes[3].Descriptor()
 }
 
 func (ResponseCreate_Status) Type() protoreflect.EnumType {
-	return &file_pb_proto_service_proto_enumTypes[0]
+	return &file_pb_proto_service_proto_enumTypes[3]
 }
 
 func (x ResponseCreate_Status) Number() protoreflect.EnumNumber {
@@ -66,7 +210,7 @@ func (x ResponseCreate_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ResponseCreate_Status.Descriptor instead.
 func (ResponseCreate_Status) EnumDescriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{2, 0}
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{15, 0}
 }
 
 type ResponseStart_Status int32
@@ -102,11 +246,11 @@ func (x ResponseStart_Status) String() string {
 }
 
 func (ResponseStart_Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_pb_proto_service_proto_enumTypes[1].Descriptor()
+	return file_pb_proto_service_proto_enumTypes[4].Descriptor()
 }
 
 func (ResponseStart_Status) Type() protoreflect.EnumType {
-	return &file_pb_proto_service_proto_enumTypes[1]
+	return &file_pb_proto_service_proto_enumTypes[4]
 }
 
 func (x ResponseStart_Status) Number() protoreflect.EnumNumber {
@@ -115,7 +259,7 @@ func (x ResponseStart_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ResponseStart_Status.Descriptor instead.
 func (ResponseStart_Status) EnumDescriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{4, 0}
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{17, 0}
 }
 
 type ResponseStop_Status int32
@@ -151,11 +295,11 @@ func (x ResponseStop_Status) String() string {
 }
 
 func (ResponseStop_Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_pb_proto_service_proto_enumTypes[2].Descriptor()
+	return file_pb_proto_service_proto_enumTypes[5].Descriptor()
 }
 
 func (ResponseStop_Status) Type() protoreflect.EnumType {
-	return &file_pb_proto_service_proto_enumTypes[2]
+	return &file_pb_proto_service_proto_enumTypes[5]
 }
 
 func (x ResponseStop_Status) Number() protoreflect.EnumNumber {
@@ -164,7 +308,112 @@ func (x ResponseStop_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ResponseStop_Status.Descriptor instead.
 func (ResponseStop_Status) EnumDescriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{6, 0}
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{19, 0}
+}
+
+type ResponseCancelStop_Status int32
+
+const (
+	ResponseCancelStop_Success      ResponseCancelStop_Status = 0
+	ResponseCancelStop_NotFound     ResponseCancelStop_Status = 1
+	ResponseCancelStop_NotScheduled ResponseCancelStop_Status = 2
+	ResponseCancelStop_Error        ResponseCancelStop_Status = 3
+)
+
+// Enum value maps for ResponseCancelStop_Status.
+var (
+	ResponseCancelStop_Status_name = map[int32]string{
+		0: "Success",
+		1: "NotFound",
+		2: "NotScheduled",
+		3: "Error",
+	}
+	ResponseCancelStop_Status_value = map[string]int32{
+		"Success":      0,
+		"NotFound":     1,
+		"NotScheduled": 2,
+		"Error":        3,
+	}
+)
+
+func (x ResponseCancelStop_Status) Enum() *ResponseCancelStop_Status {
+	p := new(ResponseCancelStop_Status)
+	*p = x
+	return p
+}
+
+func (x ResponseCancelStop_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResponseCancelStop_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[6].Descriptor()
+}
+
+func (ResponseCancelStop_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[6]
+}
+
+func (x ResponseCancelStop_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResponseCancelStop_Status.Descriptor instead.
+func (ResponseCancelStop_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{21, 0}
+}
+
+// 工作空间数据的保留策略
+type RequestDelete_Retention int32
+
+const (
+	// 立即删除数据
+	RequestDelete_Delete RequestDelete_Retention = 0
+	// 保留存储卷,在保留时间之后删除
+	RequestDelete_Retain RequestDelete_Retention = 1
+	// 为存储卷创建快照后删除存储卷,在保留时间之后删除快照
+	RequestDelete_Snapshot RequestDelete_Retention = 2
+)
+
+// Enum value maps for RequestDelete_Retention.
+var (
+	RequestDelete_Retention_name = map[int32]string{
+		0: "Delete",
+		1: "Retain",
+		2: "Snapshot",
+	}
+	RequestDelete_Retention_value = map[string]int32{
+		"Delete":   0,
+		"Retain":   1,
+		"Snapshot": 2,
+	}
+)
+
+func (x RequestDelete_Retention) Enum() *RequestDelete_Retention {
+	p := new(RequestDelete_Retention)
+	*p = x
+	return p
+}
+
+func (x RequestDelete_Retention) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RequestDelete_Retention) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[7].Descriptor()
+}
+
+func (RequestDelete_Retention) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[7]
+}
+
+func (x RequestDelete_Retention) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RequestDelete_Retention.Descriptor instead.
+func (RequestDelete_Retention) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{22, 0}
 }
 
 type ResponseDelete_Status int32
@@ -197,11 +446,11 @@ func (x ResponseDelete_Status) String() string {
 }
 
 func (ResponseDelete_Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_pb_proto_service_proto_enumTypes[3].Descriptor()
+	return file_pb_proto_service_proto_enumTypes[8].Descriptor()
 }
 
 func (ResponseDelete_Status) Type() protoreflect.EnumType {
-	return &file_pb_proto_service_proto_enumTypes[3]
+	return &file_pb_proto_service_proto_enumTypes[8]
 }
 
 func (x ResponseDelete_Status) Number() protoreflect.EnumNumber {
@@ -210,7 +459,7 @@ func (x ResponseDelete_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ResponseDelete_Status.Descriptor instead.
 func (ResponseDelete_Status) EnumDescriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{8, 0}
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{23, 0}
 }
 
 type ResponseRunningWorkspace_Status int32
@@ -243,11 +492,11 @@ func (x ResponseRunningWorkspace_Status) String() string {
 }
 
 func (ResponseRunningWorkspace_Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_pb_proto_service_proto_enumTypes[4].Descriptor()
+	return file_pb_proto_service_proto_enumTypes[9].Descriptor()
 }
 
 func (ResponseRunningWorkspace_Status) Type() protoreflect.EnumType {
-	return &file_pb_proto_service_proto_enumTypes[4]
+	return &file_pb_proto_service_proto_enumTypes[9]
 }
 
 func (x ResponseRunningWorkspace_Status) Number() protoreflect.EnumNumber {
@@ -256,195 +505,226 @@ func (x ResponseRunningWorkspace_Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ResponseRunningWorkspace_Status.Descriptor instead.
 func (ResponseRunningWorkspace_Status) EnumDescriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{10, 0}
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{25, 0}
 }
 
-// 工作空间的资源限制
-type ResourceLimit struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+type ResponseImagePullSecret_Status int32
 
-	Cpu     string `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
-	Memory  string `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
-	Storage string `protobuf:"bytes,3,opt,name=storage,proto3" json:"storage,omitempty"`
-}
+const (
+	ResponseImagePullSecret_Success ResponseImagePullSecret_Status = 0
+	ResponseImagePullSecret_Error   ResponseImagePullSecret_Status = 1
+)
 
-func (x *ResourceLimit) Reset() {
-	*x = ResourceLimit{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+// Enum value maps for ResponseImagePullSecret_Status.
+var (
+	ResponseImagePullSecret_Status_name = map[int32]string{
+		0: "Success",
+		1: "Error",
 	}
+	ResponseImagePullSecret_Status_value = map[string]int32{
+		"Success": 0,
+		"Error":   1,
+	}
+)
+
+func (x ResponseImagePullSecret_Status) Enum() *ResponseImagePullSecret_Status {
+	p := new(ResponseImagePullSecret_Status)
+	*p = x
+	return p
 }
 
-func (x *ResourceLimit) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x ResponseImagePullSecret_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-func (*ResourceLimit) ProtoMessage() {}
+func (ResponseImagePullSecret_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[10].Descriptor()
+}
 
-func (x *ResourceLimit) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (ResponseImagePullSecret_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[10]
 }
 
-// Deprecated: Use ResourceLimit.ProtoReflect.Descriptor instead.
-func (*ResourceLimit) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{0}
+func (x ResponseImagePullSecret_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
 
-func (x *ResourceLimit) GetCpu() string {
-	if x != nil {
-		return x.Cpu
-	}
-	return ""
+// Deprecated: Use ResponseImagePullSecret_Status.Descriptor instead.
+func (ResponseImagePullSecret_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{27, 0}
 }
 
-func (x *ResourceLimit) GetMemory() string {
-	if x != nil {
-		return x.Memory
+type ResponseDeleteImagePullSecret_Status int32
+
+const (
+	ResponseDeleteImagePullSecret_Success ResponseDeleteImagePullSecret_Status = 0
+	ResponseDeleteImagePullSecret_Error   ResponseDeleteImagePullSecret_Status = 1
+)
+
+// Enum value maps for ResponseDeleteImagePullSecret_Status.
+var (
+	ResponseDeleteImagePullSecret_Status_name = map[int32]string{
+		0: "Success",
+		1: "Error",
 	}
-	return ""
+	ResponseDeleteImagePullSecret_Status_value = map[string]int32{
+		"Success": 0,
+		"Error":   1,
+	}
+)
+
+func (x ResponseDeleteImagePullSecret_Status) Enum() *ResponseDeleteImagePullSecret_Status {
+	p := new(ResponseDeleteImagePullSecret_Status)
+	*p = x
+	return p
 }
 
-func (x *ResourceLimit) GetStorage() string {
-	if x != nil {
-		return x.Storage
-	}
-	return ""
+func (x ResponseDeleteImagePullSecret_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-// 创建请求
-type RequestCreate struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (ResponseDeleteImagePullSecret_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[11].Descriptor()
+}
 
-	Sid             string         `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
-	Uid             string         `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
-	Image           string         `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Port            int32          `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
-	GitRepository   string         `protobuf:"bytes,5,opt,name=gitRepository,proto3" json:"gitRepository,omitempty"`
-	VolumeMountPath string         `protobuf:"bytes,6,opt,name=volumeMountPath,proto3" json:"volumeMountPath,omitempty"`
-	ResourceLimit   *ResourceLimit `protobuf:"bytes,7,opt,name=resourceLimit,proto3" json:"resourceLimit,omitempty"`
+func (ResponseDeleteImagePullSecret_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[11]
 }
 
-func (x *RequestCreate) Reset() {
-	*x = RequestCreate{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x ResponseDeleteImagePullSecret_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
 
-func (x *RequestCreate) String() string {
-	return protoimpl.X.MessageStringOf(x)
+// Deprecated: Use ResponseDeleteImagePullSecret_Status.Descriptor instead.
+func (ResponseDeleteImagePullSecret_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{29, 0}
 }
 
-func (*RequestCreate) ProtoMessage() {}
+type ResponseSetPrePullImages_Status int32
 
-func (x *RequestCreate) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+const (
+	ResponseSetPrePullImages_Success ResponseSetPrePullImages_Status = 0
+	ResponseSetPrePullImages_Error   ResponseSetPrePullImages_Status = 1
+)
+
+// Enum value maps for ResponseSetPrePullImages_Status.
+var (
+	ResponseSetPrePullImages_Status_name = map[int32]string{
+		0: "Success",
+		1: "Error",
 	}
-	return mi.MessageOf(x)
+	ResponseSetPrePullImages_Status_value = map[string]int32{
+		"Success": 0,
+		"Error":   1,
+	}
+)
+
+func (x ResponseSetPrePullImages_Status) Enum() *ResponseSetPrePullImages_Status {
+	p := new(ResponseSetPrePullImages_Status)
+	*p = x
+	return p
 }
 
-// Deprecated: Use RequestCreate.ProtoReflect.Descriptor instead.
-func (*RequestCreate) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{1}
+func (x ResponseSetPrePullImages_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-func (x *RequestCreate) GetSid() string {
-	if x != nil {
-		return x.Sid
-	}
-	return ""
+func (ResponseSetPrePullImages_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[12].Descriptor()
 }
 
-func (x *RequestCreate) GetUid() string {
-	if x != nil {
-		return x.Uid
-	}
-	return ""
+func (ResponseSetPrePullImages_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[12]
 }
 
-func (x *RequestCreate) GetImage() string {
-	if x != nil {
-		return x.Image
-	}
-	return ""
+func (x ResponseSetPrePullImages_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
 
-func (x *RequestCreate) GetPort() int32 {
-	if x != nil {
-		return x.Port
-	}
-	return 0
+// Deprecated: Use ResponseSetPrePullImages_Status.Descriptor instead.
+func (ResponseSetPrePullImages_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{32, 0}
 }
 
-func (x *RequestCreate) GetGitRepository() string {
-	if x != nil {
-		return x.GitRepository
+type ResponseWorkspaceEvents_Status int32
+
+const (
+	ResponseWorkspaceEvents_Success  ResponseWorkspaceEvents_Status = 0
+	ResponseWorkspaceEvents_NotFound ResponseWorkspaceEvents_Status = 1
+	ResponseWorkspaceEvents_Error    ResponseWorkspaceEvents_Status = 2
+)
+
+// Enum value maps for ResponseWorkspaceEvents_Status.
+var (
+	ResponseWorkspaceEvents_Status_name = map[int32]string{
+		0: "Success",
+		1: "NotFound",
+		2: "Error",
 	}
-	return ""
+	ResponseWorkspaceEvents_Status_value = map[string]int32{
+		"Success":  0,
+		"NotFound": 1,
+		"Error":    2,
+	}
+)
+
+func (x ResponseWorkspaceEvents_Status) Enum() *ResponseWorkspaceEvents_Status {
+	p := new(ResponseWorkspaceEvents_Status)
+	*p = x
+	return p
 }
 
-func (x *RequestCreate) GetVolumeMountPath() string {
-	if x != nil {
-		return x.VolumeMountPath
-	}
-	return ""
+func (x ResponseWorkspaceEvents_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-func (x *RequestCreate) GetResourceLimit() *ResourceLimit {
-	if x != nil {
-		return x.ResourceLimit
-	}
-	return nil
+func (ResponseWorkspaceEvents_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_pb_proto_service_proto_enumTypes[13].Descriptor()
 }
 
-type ResponseCreate struct {
+func (ResponseWorkspaceEvents_Status) Type() protoreflect.EnumType {
+	return &file_pb_proto_service_proto_enumTypes[13]
+}
+
+func (x ResponseWorkspaceEvents_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResponseWorkspaceEvents_Status.Descriptor instead.
+func (ResponseWorkspaceEvents_Status) EnumDescriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{38, 0}
+}
+
+// 工作空间的资源限制
+type ResourceLimit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status  ResponseCreate_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseCreate_Status" json:"status,omitempty"`
-	Message string                `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Cpu     string `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory  string `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Storage string `protobuf:"bytes,3,opt,name=storage,proto3" json:"storage,omitempty"`
+	// 规格的调度约束
+	Scheduling *Scheduling `protobuf:"bytes,4,opt,name=scheduling,proto3" json:"scheduling,omitempty"`
 }
 
-func (x *ResponseCreate) Reset() {
-	*x = ResponseCreate{}
+func (x *ResourceLimit) Reset() {
+	*x = ResourceLimit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[2]
+		mi := &file_pb_proto_service_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseCreate) String() string {
+func (x *ResourceLimit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseCreate) ProtoMessage() {}
+func (*ResourceLimit) ProtoMessage() {}
 
-func (x *ResponseCreate) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[2]
+func (x *ResourceLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -455,52 +735,69 @@ func (x *ResponseCreate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseCreate.ProtoReflect.Descriptor instead.
-func (*ResponseCreate) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ResourceLimit.ProtoReflect.Descriptor instead.
+func (*ResourceLimit) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{0}
 }
 
-func (x *ResponseCreate) GetStatus() ResponseCreate_Status {
+func (x *ResourceLimit) GetCpu() string {
 	if x != nil {
-		return x.Status
+		return x.Cpu
 	}
-	return ResponseCreate_Success
+	return ""
 }
 
-func (x *ResponseCreate) GetMessage() string {
+func (x *ResourceLimit) GetMemory() string {
 	if x != nil {
-		return x.Message
+		return x.Memory
 	}
 	return ""
 }
 
-type RequestStart struct {
+func (x *ResourceLimit) GetStorage() string {
+	if x != nil {
+		return x.Storage
+	}
+	return ""
+}
+
+func (x *ResourceLimit) GetScheduling() *Scheduling {
+	if x != nil {
+		return x.Scheduling
+	}
+	return nil
+}
+
+// 调度约束,由管理员在空间规格中配置
+type Scheduling struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sid           string         `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
-	Uid           string         `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
-	ResourceLimit *ResourceLimit `protobuf:"bytes,3,opt,name=resourceLimit,proto3" json:"resourceLimit,omitempty"`
+	// 节点需要具有的标签,例如节点池的标签
+	NodeSelector      map[string]string `protobuf:"bytes,1,rep,name=nodeSelector,proto3" json:"nodeSelector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Tolerations       []*Toleration     `protobuf:"bytes,2,rep,name=tolerations,proto3" json:"tolerations,omitempty"`
+	PriorityClassName string            `protobuf:"bytes,3,opt,name=priorityClassName,proto3" json:"priorityClassName,omitempty"`
+	TopologySpread    []*TopologySpread `protobuf:"bytes,4,rep,name=topologySpread,proto3" json:"topologySpread,omitempty"`
 }
 
-func (x *RequestStart) Reset() {
-	*x = RequestStart{}
+func (x *Scheduling) Reset() {
+	*x = Scheduling{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[3]
+		mi := &file_pb_proto_service_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestStart) String() string {
+func (x *Scheduling) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestStart) ProtoMessage() {}
+func (*Scheduling) ProtoMessage() {}
 
-func (x *RequestStart) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[3]
+func (x *Scheduling) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,59 +808,71 @@ func (x *RequestStart) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestStart.ProtoReflect.Descriptor instead.
-func (*RequestStart) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Scheduling.ProtoReflect.Descriptor instead.
+func (*Scheduling) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *RequestStart) GetSid() string {
+func (x *Scheduling) GetNodeSelector() map[string]string {
 	if x != nil {
-		return x.Sid
+		return x.NodeSelector
 	}
-	return ""
+	return nil
 }
 
-func (x *RequestStart) GetUid() string {
+func (x *Scheduling) GetTolerations() []*Toleration {
 	if x != nil {
-		return x.Uid
+		return x.Tolerations
+	}
+	return nil
+}
+
+func (x *Scheduling) GetPriorityClassName() string {
+	if x != nil {
+		return x.PriorityClassName
 	}
 	return ""
 }
 
-func (x *RequestStart) GetResourceLimit() *ResourceLimit {
+func (x *Scheduling) GetTopologySpread() []*TopologySpread {
 	if x != nil {
-		return x.ResourceLimit
+		return x.TopologySpread
 	}
 	return nil
 }
 
-// 工作空间运行信息
-type ResponseStart struct {
+type Toleration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status  ResponseStart_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseStart_Status" json:"status,omitempty"`
-	Message string               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Exists或Equal
+	Operator string `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Value    string `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	// NoSchedule、PreferNoSchedule或NoExecute, 为空表示所有effect
+	Effect string `protobuf:"bytes,4,opt,name=effect,proto3" json:"effect,omitempty"`
+	// 只对NoExecute有效, 0表示一直容忍
+	TolerationSeconds int64 `protobuf:"varint,5,opt,name=tolerationSeconds,proto3" json:"tolerationSeconds,omitempty"`
 }
 
-func (x *ResponseStart) Reset() {
-	*x = ResponseStart{}
+func (x *Toleration) Reset() {
+	*x = Toleration{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[4]
+		mi := &file_pb_proto_service_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseStart) String() string {
+func (x *Toleration) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseStart) ProtoMessage() {}
+func (*Toleration) ProtoMessage() {}
 
-func (x *ResponseStart) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[4]
+func (x *Toleration) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -574,51 +883,75 @@ func (x *ResponseStart) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseStart.ProtoReflect.Descriptor instead.
-func (*ResponseStart) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Toleration.ProtoReflect.Descriptor instead.
+func (*Toleration) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ResponseStart) GetStatus() ResponseStart_Status {
+func (x *Toleration) GetKey() string {
 	if x != nil {
-		return x.Status
+		return x.Key
 	}
-	return ResponseStart_Success
+	return ""
 }
 
-func (x *ResponseStart) GetMessage() string {
+func (x *Toleration) GetOperator() string {
 	if x != nil {
-		return x.Message
+		return x.Operator
 	}
 	return ""
 }
 
-type RequestStop struct {
+func (x *Toleration) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Toleration) GetEffect() string {
+	if x != nil {
+		return x.Effect
+	}
+	return ""
+}
+
+func (x *Toleration) GetTolerationSeconds() int64 {
+	if x != nil {
+		return x.TolerationSeconds
+	}
+	return 0
+}
+
+// 工作空间在拓扑域之间均匀分布
+type TopologySpread struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sid string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
-	Uid string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	TopologyKey string `protobuf:"bytes,1,opt,name=topologyKey,proto3" json:"topologyKey,omitempty"`
+	MaxSkew     int32  `protobuf:"varint,2,opt,name=maxSkew,proto3" json:"maxSkew,omitempty"`
+	// 为true时无法满足约束也会调度, 否则不调度
+	ScheduleAnyway bool `protobuf:"varint,3,opt,name=scheduleAnyway,proto3" json:"scheduleAnyway,omitempty"`
 }
 
-func (x *RequestStop) Reset() {
-	*x = RequestStop{}
+func (x *TopologySpread) Reset() {
+	*x = TopologySpread{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[5]
+		mi := &file_pb_proto_service_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestStop) String() string {
+func (x *TopologySpread) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestStop) ProtoMessage() {}
+func (*TopologySpread) ProtoMessage() {}
 
-func (x *RequestStop) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[5]
+func (x *TopologySpread) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -629,51 +962,84 @@ func (x *RequestStop) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestStop.ProtoReflect.Descriptor instead.
-func (*RequestStop) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use TopologySpread.ProtoReflect.Descriptor instead.
+func (*TopologySpread) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RequestStop) GetSid() string {
+func (x *TopologySpread) GetTopologyKey() string {
 	if x != nil {
-		return x.Sid
+		return x.TopologyKey
 	}
 	return ""
 }
 
-func (x *RequestStop) GetUid() string {
+func (x *TopologySpread) GetMaxSkew() int32 {
 	if x != nil {
-		return x.Uid
+		return x.MaxSkew
 	}
-	return ""
+	return 0
 }
 
-type ResponseStop struct {
+func (x *TopologySpread) GetScheduleAnyway() bool {
+	if x != nil {
+		return x.ScheduleAnyway
+	}
+	return false
+}
+
+// 创建请求
+type RequestCreate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status  ResponseStop_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseStop_Status" json:"status,omitempty"`
-	Message string              `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Sid             string         `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid             string         `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Image           string         `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Port            int32          `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	GitRepository   string         `protobuf:"bytes,5,opt,name=gitRepository,proto3" json:"gitRepository,omitempty"`
+	VolumeMountPath string         `protobuf:"bytes,6,opt,name=volumeMountPath,proto3" json:"volumeMountPath,omitempty"`
+	ResourceLimit   *ResourceLimit `protobuf:"bytes,7,opt,name=resourceLimit,proto3" json:"resourceLimit,omitempty"`
+	// 拉取私有镜像使用的Secret名称
+	ImagePullSecret string `protobuf:"bytes,8,opt,name=imagePullSecret,proto3" json:"imagePullSecret,omitempty"`
+	// 工作空间的环境变量,以Secret的形式保存并注入到工作空间中
+	Envs map[string]string `protobuf:"bytes,9,rep,name=envs,proto3" json:"envs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// 要克隆的分支、标签或者commit
+	GitRef string `protobuf:"bytes,10,opt,name=gitRef,proto3" json:"gitRef,omitempty"`
+	// 浅克隆的深度,0表示完整克隆
+	GitDepth int32 `protobuf:"varint,11,opt,name=gitDepth,proto3" json:"gitDepth,omitempty"`
+	// 克隆私有仓库使用的凭证,每个git主机一个
+	GitCredentials []*GitCredential `protobuf:"bytes,12,rep,name=gitCredentials,proto3" json:"gitCredentials,omitempty"`
+	// 额外克隆的git仓库
+	Repositories []*GitRepository `protobuf:"bytes,13,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	// 用户的dotfiles仓库
+	Dotfiles *GitRepository `protobuf:"bytes,14,opt,name=dotfiles,proto3" json:"dotfiles,omitempty"`
+	// 生命周期钩子,工作空间的钩子会覆盖模板的钩子
+	Hooks *LifecycleHooks `protobuf:"bytes,15,opt,name=hooks,proto3" json:"hooks,omitempty"`
+	// 定时启动和停止
+	Schedule *WorkspaceSchedule `protobuf:"bytes,16,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// 模板的出站白名单,为空时使用控制面的默认出站策略
+	Egress *EgressPolicy `protobuf:"bytes,17,opt,name=egress,proto3" json:"egress,omitempty"`
 }
 
-func (x *ResponseStop) Reset() {
-	*x = ResponseStop{}
+func (x *RequestCreate) Reset() {
+	*x = RequestCreate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[6]
+		mi := &file_pb_proto_service_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseStop) String() string {
+func (x *RequestCreate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseStop) ProtoMessage() {}
+func (*RequestCreate) ProtoMessage() {}
 
-func (x *ResponseStop) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[6]
+func (x *RequestCreate) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -684,51 +1050,156 @@ func (x *ResponseStop) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseStop.ProtoReflect.Descriptor instead.
-func (*ResponseStop) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RequestCreate.ProtoReflect.Descriptor instead.
+func (*RequestCreate) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ResponseStop) GetStatus() ResponseStop_Status {
+func (x *RequestCreate) GetSid() string {
 	if x != nil {
-		return x.Status
+		return x.Sid
 	}
-	return ResponseStop_Success
+	return ""
 }
 
-func (x *ResponseStop) GetMessage() string {
+func (x *RequestCreate) GetUid() string {
 	if x != nil {
-		return x.Message
+		return x.Uid
 	}
 	return ""
 }
 
-type RequestDelete struct {
+func (x *RequestCreate) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *RequestCreate) GetPort() int32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *RequestCreate) GetGitRepository() string {
+	if x != nil {
+		return x.GitRepository
+	}
+	return ""
+}
+
+func (x *RequestCreate) GetVolumeMountPath() string {
+	if x != nil {
+		return x.VolumeMountPath
+	}
+	return ""
+}
+
+func (x *RequestCreate) GetResourceLimit() *ResourceLimit {
+	if x != nil {
+		return x.ResourceLimit
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetImagePullSecret() string {
+	if x != nil {
+		return x.ImagePullSecret
+	}
+	return ""
+}
+
+func (x *RequestCreate) GetEnvs() map[string]string {
+	if x != nil {
+		return x.Envs
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetGitRef() string {
+	if x != nil {
+		return x.GitRef
+	}
+	return ""
+}
+
+func (x *RequestCreate) GetGitDepth() int32 {
+	if x != nil {
+		return x.GitDepth
+	}
+	return 0
+}
+
+func (x *RequestCreate) GetGitCredentials() []*GitCredential {
+	if x != nil {
+		return x.GitCredentials
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetRepositories() []*GitRepository {
+	if x != nil {
+		return x.Repositories
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetDotfiles() *GitRepository {
+	if x != nil {
+		return x.Dotfiles
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetHooks() *LifecycleHooks {
+	if x != nil {
+		return x.Hooks
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetSchedule() *WorkspaceSchedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+func (x *RequestCreate) GetEgress() *EgressPolicy {
+	if x != nil {
+		return x.Egress
+	}
+	return nil
+}
+
+// 出站白名单,只允许访问rules中的地址,rules为空时只允许访问DNS
+type EgressPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sid string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
-	Uid string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Rules []*EgressRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
 }
 
-func (x *RequestDelete) Reset() {
-	*x = RequestDelete{}
+func (x *EgressPolicy) Reset() {
+	*x = EgressPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[7]
+		mi := &file_pb_proto_service_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestDelete) String() string {
+func (x *EgressPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestDelete) ProtoMessage() {}
+func (*EgressPolicy) ProtoMessage() {}
 
-func (x *RequestDelete) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[7]
+func (x *EgressPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,51 +1210,45 @@ func (x *RequestDelete) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestDelete.ProtoReflect.Descriptor instead.
-func (*RequestDelete) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *RequestDelete) GetSid() string {
-	if x != nil {
-		return x.Sid
-	}
-	return ""
+// Deprecated: Use EgressPolicy.ProtoReflect.Descriptor instead.
+func (*EgressPolicy) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *RequestDelete) GetUid() string {
+func (x *EgressPolicy) GetRules() []*EgressRule {
 	if x != nil {
-		return x.Uid
+		return x.Rules
 	}
-	return ""
+	return nil
 }
 
-type ResponseDelete struct {
+// cidr为允许访问的地址段,ports为允许访问的TCP端口,为空时允许所有端口
+type EgressRule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status  ResponseDelete_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseDelete_Status" json:"status,omitempty"`
-	Message string                `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Cidr  string  `protobuf:"bytes,1,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Ports []int32 `protobuf:"varint,2,rep,packed,name=ports,proto3" json:"ports,omitempty"`
 }
 
-func (x *ResponseDelete) Reset() {
-	*x = ResponseDelete{}
+func (x *EgressRule) Reset() {
+	*x = EgressRule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[8]
+		mi := &file_pb_proto_service_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseDelete) String() string {
+func (x *EgressRule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseDelete) ProtoMessage() {}
+func (*EgressRule) ProtoMessage() {}
 
-func (x *ResponseDelete) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[8]
+func (x *EgressRule) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -794,50 +1259,53 @@ func (x *ResponseDelete) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseDelete.ProtoReflect.Descriptor instead.
-func (*ResponseDelete) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use EgressRule.ProtoReflect.Descriptor instead.
+func (*EgressRule) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ResponseDelete) GetStatus() ResponseDelete_Status {
+func (x *EgressRule) GetCidr() string {
 	if x != nil {
-		return x.Status
+		return x.Cidr
 	}
-	return ResponseDelete_Success
+	return ""
 }
 
-func (x *ResponseDelete) GetMessage() string {
+func (x *EgressRule) GetPorts() []int32 {
 	if x != nil {
-		return x.Message
+		return x.Ports
 	}
-	return ""
+	return nil
 }
 
-type RequestRunningWorkspaces struct {
+// 定时启动和停止工作空间,start和stop为5段cron表达式,timezone为IANA时区,默认为UTC
+type WorkspaceSchedule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Start    string `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Stop     string `protobuf:"bytes,2,opt,name=stop,proto3" json:"stop,omitempty"`
+	Timezone string `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
 }
 
-func (x *RequestRunningWorkspaces) Reset() {
-	*x = RequestRunningWorkspaces{}
+func (x *WorkspaceSchedule) Reset() {
+	*x = WorkspaceSchedule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[9]
+		mi := &file_pb_proto_service_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RequestRunningWorkspaces) String() string {
+func (x *WorkspaceSchedule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestRunningWorkspaces) ProtoMessage() {}
+func (*WorkspaceSchedule) ProtoMessage() {}
 
-func (x *RequestRunningWorkspaces) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[9]
+func (x *WorkspaceSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -848,43 +1316,60 @@ func (x *RequestRunningWorkspaces) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestRunningWorkspaces.ProtoReflect.Descriptor instead.
-func (*RequestRunningWorkspaces) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use WorkspaceSchedule.ProtoReflect.Descriptor instead.
+func (*WorkspaceSchedule) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *RequestRunningWorkspaces) GetUid() string {
+func (x *WorkspaceSchedule) GetStart() string {
 	if x != nil {
-		return x.Uid
+		return x.Start
 	}
 	return ""
 }
 
-type ResponseRunningWorkspace struct {
+func (x *WorkspaceSchedule) GetStop() string {
+	if x != nil {
+		return x.Stop
+	}
+	return ""
+}
+
+func (x *WorkspaceSchedule) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+type RequestSetSchedule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Workspaces []*ResponseRunningWorkspace_WorkspaceBasicInfo `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	Sid string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	// 为空时删除定时
+	Schedule *WorkspaceSchedule `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
 }
 
-func (x *ResponseRunningWorkspace) Reset() {
-	*x = ResponseRunningWorkspace{}
+func (x *RequestSetSchedule) Reset() {
+	*x = RequestSetSchedule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[10]
+		mi := &file_pb_proto_service_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseRunningWorkspace) String() string {
+func (x *RequestSetSchedule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseRunningWorkspace) ProtoMessage() {}
+func (*RequestSetSchedule) ProtoMessage() {}
 
-func (x *ResponseRunningWorkspace) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[10]
+func (x *RequestSetSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -895,44 +1380,58 @@ func (x *ResponseRunningWorkspace) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseRunningWorkspace.ProtoReflect.Descriptor instead.
-func (*ResponseRunningWorkspace) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RequestSetSchedule.ProtoReflect.Descriptor instead.
+func (*RequestSetSchedule) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ResponseRunningWorkspace) GetWorkspaces() []*ResponseRunningWorkspace_WorkspaceBasicInfo {
+func (x *RequestSetSchedule) GetSid() string {
 	if x != nil {
-		return x.Workspaces
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestSetSchedule) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestSetSchedule) GetSchedule() *WorkspaceSchedule {
+	if x != nil {
+		return x.Schedule
 	}
 	return nil
 }
 
-type ResponseRunningWorkspace_WorkspaceBasicInfo struct {
+type ResponseSetSchedule struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sid  string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Status  ResponseSetSchedule_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseSetSchedule_Status" json:"status,omitempty"`
+	Message string                     `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 }
 
-func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) Reset() {
-	*x = ResponseRunningWorkspace_WorkspaceBasicInfo{}
+func (x *ResponseSetSchedule) Reset() {
+	*x = ResponseSetSchedule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pb_proto_service_proto_msgTypes[11]
+		mi := &file_pb_proto_service_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) String() string {
+func (x *ResponseSetSchedule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResponseRunningWorkspace_WorkspaceBasicInfo) ProtoMessage() {}
+func (*ResponseSetSchedule) ProtoMessage() {}
 
-func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_pb_proto_service_proto_msgTypes[11]
+func (x *ResponseSetSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -943,192 +1442,2486 @@ func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) ProtoReflect() protoreflec
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResponseRunningWorkspace_WorkspaceBasicInfo.ProtoReflect.Descriptor instead.
-func (*ResponseRunningWorkspace_WorkspaceBasicInfo) Descriptor() ([]byte, []int) {
-	return file_pb_proto_service_proto_rawDescGZIP(), []int{10, 0}
+// Deprecated: Use ResponseSetSchedule.ProtoReflect.Descriptor instead.
+func (*ResponseSetSchedule) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) GetSid() string {
+func (x *ResponseSetSchedule) GetStatus() ResponseSetSchedule_Status {
 	if x != nil {
-		return x.Sid
+		return x.Status
 	}
-	return ""
+	return ResponseSetSchedule_Success
 }
 
-func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) GetName() string {
+func (x *ResponseSetSchedule) GetMessage() string {
 	if x != nil {
-		return x.Name
+		return x.Message
 	}
 	return ""
 }
 
-var File_pb_proto_service_proto protoreflect.FileDescriptor
+// 升级或者回滚工作空间的镜像, 运行中的工作空间会重新启动
+type RequestUpdateImage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_pb_proto_service_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x70, 0x62, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x53, 0x0a, 0x0d,
-	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x10, 0x0a,
-	0x03, 0x63, 0x70, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x70, 0x75, 0x12,
-	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61,
-	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67,
-	0x65, 0x22, 0xe6, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a,
-	0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72,
-	0x74, 0x12, 0x24, 0x0a, 0x0d, 0x67, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
-	0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x67, 0x69, 0x74, 0x52, 0x65, 0x70,
-	0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
-	0x65, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x74,
-	0x68, 0x12, 0x37, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d,
-	0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x0d, 0x72, 0x65, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x91, 0x01, 0x0a, 0x0e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e,
-	0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x32, 0x0a, 0x06, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10,
-	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x6c, 0x72, 0x65, 0x61, 0x64, 0x79, 0x45, 0x78, 0x69, 0x73,
-	0x74, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x6b,
-	0x0a, 0x0c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10,
-	0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64,
-	0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
-	0x69, 0x64, 0x12, 0x37, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69,
-	0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x0d, 0x72, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x8b, 0x01, 0x0a, 0x0d,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x30, 0x0a,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e,
-	0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74,
-	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2e, 0x0a, 0x06, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00,
-	0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01, 0x12, 0x09,
-	0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x31, 0x0a, 0x0b, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69,
-	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x89, 0x01, 0x0a,
-	0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x2f, 0x0a,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e,
-	0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x6f, 0x70, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18,
-	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2e, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12,
-	0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01, 0x12, 0x09, 0x0a,
-	0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x33, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0x7f, 0x0a,
-	0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12,
-	0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x19, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x20, 0x0a, 0x06,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73,
-	0x73, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x01, 0x22, 0x2c,
-	0x0a, 0x18, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67,
-	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x22, 0xcc, 0x01, 0x0a,
-	0x18, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67,
-	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x77, 0x6f, 0x72,
-	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
-	0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x75, 0x6e, 0x6e, 0x69,
-	0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x42, 0x61, 0x73, 0x69, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a,
-	0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x1a, 0x3a, 0x0a, 0x12, 0x57, 0x6f,
-	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42, 0x61, 0x73, 0x69, 0x63, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73,
-	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x23, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x0c, 0x0a,
-	0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01, 0x32, 0xb1, 0x02, 0x0a, 0x0f,
-	0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x34, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x11,
-	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x53, 0x70,
-	0x61, 0x63, 0x65, 0x12, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x34, 0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2e,
-	0x0a, 0x09, 0x73, 0x74, 0x6f, 0x70, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x0f, 0x2e, 0x70, 0x62,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x70, 0x1a, 0x10, 0x2e, 0x70,
-	0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x4f,
-	0x0a, 0x11, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
-	0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
-	0x73, 0x1a, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52,
-	0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42,
-	0x07, 0x5a, 0x05, 0x2e, 0x2f, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	Sid   string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid   string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Image string `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
 }
 
-var (
-	file_pb_proto_service_proto_rawDescOnce sync.Once
-	file_pb_proto_service_proto_rawDescData = file_pb_proto_service_proto_rawDesc
-)
-
-func file_pb_proto_service_proto_rawDescGZIP() []byte {
-	file_pb_proto_service_proto_rawDescOnce.Do(func() {
+func (x *RequestUpdateImage) Reset() {
+	*x = RequestUpdateImage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestUpdateImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestUpdateImage) ProtoMessage() {}
+
+func (x *RequestUpdateImage) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestUpdateImage.ProtoReflect.Descriptor instead.
+func (*RequestUpdateImage) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RequestUpdateImage) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestUpdateImage) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestUpdateImage) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+type ResponseUpdateImage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseUpdateImage_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseUpdateImage_Status" json:"status,omitempty"`
+	Message string                     `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseUpdateImage) Reset() {
+	*x = ResponseUpdateImage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseUpdateImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseUpdateImage) ProtoMessage() {}
+
+func (x *ResponseUpdateImage) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseUpdateImage.ProtoReflect.Descriptor instead.
+func (*ResponseUpdateImage) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ResponseUpdateImage) GetStatus() ResponseUpdateImage_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseUpdateImage_Success
+}
+
+func (x *ResponseUpdateImage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 生命周期钩子,每个钩子是一条shell命令
+type LifecycleHooks struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 第一次创建时执行
+	PostCreate string `protobuf:"bytes,1,opt,name=postCreate,proto3" json:"postCreate,omitempty"`
+	// 每次启动时执行
+	PostStart string `protobuf:"bytes,2,opt,name=postStart,proto3" json:"postStart,omitempty"`
+	// 停止前执行
+	PreStop string `protobuf:"bytes,3,opt,name=preStop,proto3" json:"preStop,omitempty"`
+}
+
+func (x *LifecycleHooks) Reset() {
+	*x = LifecycleHooks{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LifecycleHooks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LifecycleHooks) ProtoMessage() {}
+
+func (x *LifecycleHooks) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LifecycleHooks.ProtoReflect.Descriptor instead.
+func (*LifecycleHooks) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LifecycleHooks) GetPostCreate() string {
+	if x != nil {
+		return x.PostCreate
+	}
+	return ""
+}
+
+func (x *LifecycleHooks) GetPostStart() string {
+	if x != nil {
+		return x.PostStart
+	}
+	return ""
+}
+
+func (x *LifecycleHooks) GetPreStop() string {
+	if x != nil {
+		return x.PreStop
+	}
+	return ""
+}
+
+// git仓库,path为相对于工作目录的路径,为空时使用仓库名称
+type GitRepository struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url   string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Path  string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Ref   string `protobuf:"bytes,3,opt,name=ref,proto3" json:"ref,omitempty"`
+	Depth int32  `protobuf:"varint,4,opt,name=depth,proto3" json:"depth,omitempty"`
+}
+
+func (x *GitRepository) Reset() {
+	*x = GitRepository{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GitRepository) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GitRepository) ProtoMessage() {}
+
+func (x *GitRepository) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitRepository.ProtoReflect.Descriptor instead.
+func (*GitRepository) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GitRepository) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GitRepository) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GitRepository) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (x *GitRepository) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+// git凭证,https使用用户名和token,ssh使用私钥
+type GitCredential struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Host     string             `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Type     GitCredential_Type `protobuf:"varint,2,opt,name=type,proto3,enum=pb.GitCredential_Type" json:"type,omitempty"`
+	Username string             `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Secret   string             `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	// ssh主机的公钥,格式与known_hosts文件相同
+	KnownHosts string `protobuf:"bytes,5,opt,name=knownHosts,proto3" json:"knownHosts,omitempty"`
+}
+
+func (x *GitCredential) Reset() {
+	*x = GitCredential{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GitCredential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GitCredential) ProtoMessage() {}
+
+func (x *GitCredential) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GitCredential.ProtoReflect.Descriptor instead.
+func (*GitCredential) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GitCredential) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *GitCredential) GetType() GitCredential_Type {
+	if x != nil {
+		return x.Type
+	}
+	return GitCredential_Https
+}
+
+func (x *GitCredential) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *GitCredential) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *GitCredential) GetKnownHosts() string {
+	if x != nil {
+		return x.KnownHosts
+	}
+	return ""
+}
+
+type ResponseCreate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseCreate_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseCreate_Status" json:"status,omitempty"`
+	Message string                `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseCreate) Reset() {
+	*x = ResponseCreate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseCreate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseCreate) ProtoMessage() {}
+
+func (x *ResponseCreate) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseCreate.ProtoReflect.Descriptor instead.
+func (*ResponseCreate) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ResponseCreate) GetStatus() ResponseCreate_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseCreate_Success
+}
+
+func (x *ResponseCreate) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RequestStart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid           string         `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid           string         `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	ResourceLimit *ResourceLimit `protobuf:"bytes,3,opt,name=resourceLimit,proto3" json:"resourceLimit,omitempty"`
+	// 工作空间的环境变量,每次启动时更新
+	Envs map[string]string `protobuf:"bytes,4,rep,name=envs,proto3" json:"envs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// 克隆私有仓库使用的凭证,每次启动时更新
+	GitCredentials []*GitCredential `protobuf:"bytes,5,rep,name=gitCredentials,proto3" json:"gitCredentials,omitempty"`
+	// 用户的dotfiles仓库,每次启动时更新
+	Dotfiles *GitRepository `protobuf:"bytes,6,opt,name=dotfiles,proto3" json:"dotfiles,omitempty"`
+	// 生命周期钩子,每次启动时更新
+	Hooks *LifecycleHooks `protobuf:"bytes,7,opt,name=hooks,proto3" json:"hooks,omitempty"`
+	// 模板的出站白名单,每次启动时更新
+	Egress *EgressPolicy `protobuf:"bytes,8,opt,name=egress,proto3" json:"egress,omitempty"`
+	// 使用上次启动时的配置启动,不更新以上的规格、环境变量、git凭证等配置,用于控制面的定时启动
+	ReuseConfig bool `protobuf:"varint,9,opt,name=reuseConfig,proto3" json:"reuseConfig,omitempty"`
+}
+
+func (x *RequestStart) Reset() {
+	*x = RequestStart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestStart) ProtoMessage() {}
+
+func (x *RequestStart) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestStart.ProtoReflect.Descriptor instead.
+func (*RequestStart) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RequestStart) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestStart) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestStart) GetResourceLimit() *ResourceLimit {
+	if x != nil {
+		return x.ResourceLimit
+	}
+	return nil
+}
+
+func (x *RequestStart) GetEnvs() map[string]string {
+	if x != nil {
+		return x.Envs
+	}
+	return nil
+}
+
+func (x *RequestStart) GetGitCredentials() []*GitCredential {
+	if x != nil {
+		return x.GitCredentials
+	}
+	return nil
+}
+
+func (x *RequestStart) GetDotfiles() *GitRepository {
+	if x != nil {
+		return x.Dotfiles
+	}
+	return nil
+}
+
+func (x *RequestStart) GetHooks() *LifecycleHooks {
+	if x != nil {
+		return x.Hooks
+	}
+	return nil
+}
+
+func (x *RequestStart) GetEgress() *EgressPolicy {
+	if x != nil {
+		return x.Egress
+	}
+	return nil
+}
+
+func (x *RequestStart) GetReuseConfig() bool {
+	if x != nil {
+		return x.ReuseConfig
+	}
+	return false
+}
+
+// 工作空间运行信息
+type ResponseStart struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseStart_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseStart_Status" json:"status,omitempty"`
+	Message string               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseStart) Reset() {
+	*x = ResponseStart{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseStart) ProtoMessage() {}
+
+func (x *ResponseStart) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseStart.ProtoReflect.Descriptor instead.
+func (*ResponseStart) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResponseStart) GetStatus() ResponseStart_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseStart_Success
+}
+
+func (x *ResponseStart) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RequestStop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	// 延迟停止的秒数,0表示立即停止,延迟停止在到达停止时间之前可以取消
+	DelaySeconds int32 `protobuf:"varint,3,opt,name=delaySeconds,proto3" json:"delaySeconds,omitempty"`
+}
+
+func (x *RequestStop) Reset() {
+	*x = RequestStop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestStop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestStop) ProtoMessage() {}
+
+func (x *RequestStop) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestStop.ProtoReflect.Descriptor instead.
+func (*RequestStop) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RequestStop) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestStop) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestStop) GetDelaySeconds() int32 {
+	if x != nil {
+		return x.DelaySeconds
+	}
+	return 0
+}
+
+type ResponseStop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseStop_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseStop_Status" json:"status,omitempty"`
+	Message string              `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// 计划停止的时间,unix时间戳,立即停止时为0
+	ScheduledStopTime int64 `protobuf:"varint,3,opt,name=scheduledStopTime,proto3" json:"scheduledStopTime,omitempty"`
+}
+
+func (x *ResponseStop) Reset() {
+	*x = ResponseStop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseStop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseStop) ProtoMessage() {}
+
+func (x *ResponseStop) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseStop.ProtoReflect.Descriptor instead.
+func (*ResponseStop) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ResponseStop) GetStatus() ResponseStop_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseStop_Success
+}
+
+func (x *ResponseStop) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ResponseStop) GetScheduledStopTime() int64 {
+	if x != nil {
+		return x.ScheduledStopTime
+	}
+	return 0
+}
+
+type RequestCancelStop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *RequestCancelStop) Reset() {
+	*x = RequestCancelStop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestCancelStop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestCancelStop) ProtoMessage() {}
+
+func (x *RequestCancelStop) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestCancelStop.ProtoReflect.Descriptor instead.
+func (*RequestCancelStop) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RequestCancelStop) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestCancelStop) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ResponseCancelStop struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseCancelStop_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseCancelStop_Status" json:"status,omitempty"`
+	Message string                    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseCancelStop) Reset() {
+	*x = ResponseCancelStop{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseCancelStop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseCancelStop) ProtoMessage() {}
+
+func (x *ResponseCancelStop) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseCancelStop.ProtoReflect.Descriptor instead.
+func (*ResponseCancelStop) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ResponseCancelStop) GetStatus() ResponseCancelStop_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseCancelStop_Success
+}
+
+func (x *ResponseCancelStop) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RequestDelete struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid       string                  `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid       string                  `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+	Retention RequestDelete_Retention `protobuf:"varint,3,opt,name=retention,proto3,enum=pb.RequestDelete_Retention" json:"retention,omitempty"`
+	// 数据保留的天数,retention为Retain或Snapshot时有效
+	RetainDays int32 `protobuf:"varint,4,opt,name=retainDays,proto3" json:"retainDays,omitempty"`
+	// 数据保留的截止时间,unix时间戳,与webserver回收站中记录的时间一致,不为0时优先于retainDays
+	RetainUntil int64 `protobuf:"varint,5,opt,name=retainUntil,proto3" json:"retainUntil,omitempty"`
+}
+
+func (x *RequestDelete) Reset() {
+	*x = RequestDelete{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestDelete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestDelete) ProtoMessage() {}
+
+func (x *RequestDelete) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestDelete.ProtoReflect.Descriptor instead.
+func (*RequestDelete) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RequestDelete) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestDelete) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestDelete) GetRetention() RequestDelete_Retention {
+	if x != nil {
+		return x.Retention
+	}
+	return RequestDelete_Delete
+}
+
+func (x *RequestDelete) GetRetainDays() int32 {
+	if x != nil {
+		return x.RetainDays
+	}
+	return 0
+}
+
+func (x *RequestDelete) GetRetainUntil() int64 {
+	if x != nil {
+		return x.RetainUntil
+	}
+	return 0
+}
+
+type ResponseDelete struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseDelete_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseDelete_Status" json:"status,omitempty"`
+	Message string                `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseDelete) Reset() {
+	*x = ResponseDelete{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseDelete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseDelete) ProtoMessage() {}
+
+func (x *ResponseDelete) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseDelete.ProtoReflect.Descriptor instead.
+func (*ResponseDelete) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ResponseDelete) GetStatus() ResponseDelete_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseDelete_Success
+}
+
+func (x *ResponseDelete) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RequestRunningWorkspaces struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *RequestRunningWorkspaces) Reset() {
+	*x = RequestRunningWorkspaces{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestRunningWorkspaces) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestRunningWorkspaces) ProtoMessage() {}
+
+func (x *RequestRunningWorkspaces) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestRunningWorkspaces.ProtoReflect.Descriptor instead.
+func (*RequestRunningWorkspaces) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RequestRunningWorkspaces) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ResponseRunningWorkspace struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workspaces []*ResponseRunningWorkspace_WorkspaceBasicInfo `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+}
+
+func (x *ResponseRunningWorkspace) Reset() {
+	*x = ResponseRunningWorkspace{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseRunningWorkspace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseRunningWorkspace) ProtoMessage() {}
+
+func (x *ResponseRunningWorkspace) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseRunningWorkspace.ProtoReflect.Descriptor instead.
+func (*ResponseRunningWorkspace) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ResponseRunningWorkspace) GetWorkspaces() []*ResponseRunningWorkspace_WorkspaceBasicInfo {
+	if x != nil {
+		return x.Workspaces
+	}
+	return nil
+}
+
+// 创建或更新拉取私有镜像使用的Secret
+type RequestImagePullSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid      string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Registry string `protobuf:"bytes,2,opt,name=registry,proto3" json:"registry,omitempty"`
+	Username string `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *RequestImagePullSecret) Reset() {
+	*x = RequestImagePullSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestImagePullSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestImagePullSecret) ProtoMessage() {}
+
+func (x *RequestImagePullSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestImagePullSecret.ProtoReflect.Descriptor instead.
+func (*RequestImagePullSecret) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RequestImagePullSecret) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestImagePullSecret) GetRegistry() string {
+	if x != nil {
+		return x.Registry
+	}
+	return ""
+}
+
+func (x *RequestImagePullSecret) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RequestImagePullSecret) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type ResponseImagePullSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseImagePullSecret_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseImagePullSecret_Status" json:"status,omitempty"`
+	Message string                         `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// 创建的Secret名称
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *ResponseImagePullSecret) Reset() {
+	*x = ResponseImagePullSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseImagePullSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseImagePullSecret) ProtoMessage() {}
+
+func (x *ResponseImagePullSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseImagePullSecret.ProtoReflect.Descriptor instead.
+func (*ResponseImagePullSecret) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ResponseImagePullSecret) GetStatus() ResponseImagePullSecret_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseImagePullSecret_Success
+}
+
+func (x *ResponseImagePullSecret) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ResponseImagePullSecret) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RequestDeleteImagePullSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid  string `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *RequestDeleteImagePullSecret) Reset() {
+	*x = RequestDeleteImagePullSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestDeleteImagePullSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestDeleteImagePullSecret) ProtoMessage() {}
+
+func (x *RequestDeleteImagePullSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestDeleteImagePullSecret.ProtoReflect.Descriptor instead.
+func (*RequestDeleteImagePullSecret) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RequestDeleteImagePullSecret) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *RequestDeleteImagePullSecret) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ResponseDeleteImagePullSecret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseDeleteImagePullSecret_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseDeleteImagePullSecret_Status" json:"status,omitempty"`
+	Message string                               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseDeleteImagePullSecret) Reset() {
+	*x = ResponseDeleteImagePullSecret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseDeleteImagePullSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseDeleteImagePullSecret) ProtoMessage() {}
+
+func (x *ResponseDeleteImagePullSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseDeleteImagePullSecret.ProtoReflect.Descriptor instead.
+func (*ResponseDeleteImagePullSecret) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ResponseDeleteImagePullSecret) GetStatus() ResponseDeleteImagePullSecret_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseDeleteImagePullSecret_Success
+}
+
+func (x *ResponseDeleteImagePullSecret) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 需要预先拉取到所有节点上的模板镜像
+type PrePullImage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// 拉取私有镜像使用的Secret名称
+	PullSecret string `protobuf:"bytes,2,opt,name=pullSecret,proto3" json:"pullSecret,omitempty"`
+}
+
+func (x *PrePullImage) Reset() {
+	*x = PrePullImage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrePullImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrePullImage) ProtoMessage() {}
+
+func (x *PrePullImage) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrePullImage.ProtoReflect.Descriptor instead.
+func (*PrePullImage) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PrePullImage) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *PrePullImage) GetPullSecret() string {
+	if x != nil {
+		return x.PullSecret
+	}
+	return ""
+}
+
+// 设置需要预先拉取的镜像, 会覆盖之前设置的镜像
+type RequestSetPrePullImages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Images []*PrePullImage `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+}
+
+func (x *RequestSetPrePullImages) Reset() {
+	*x = RequestSetPrePullImages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestSetPrePullImages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestSetPrePullImages) ProtoMessage() {}
+
+func (x *RequestSetPrePullImages) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestSetPrePullImages.ProtoReflect.Descriptor instead.
+func (*RequestSetPrePullImages) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RequestSetPrePullImages) GetImages() []*PrePullImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type ResponseSetPrePullImages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseSetPrePullImages_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseSetPrePullImages_Status" json:"status,omitempty"`
+	Message string                          `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ResponseSetPrePullImages) Reset() {
+	*x = ResponseSetPrePullImages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseSetPrePullImages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseSetPrePullImages) ProtoMessage() {}
+
+func (x *ResponseSetPrePullImages) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseSetPrePullImages.ProtoReflect.Descriptor instead.
+func (*ResponseSetPrePullImages) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ResponseSetPrePullImages) GetStatus() ResponseSetPrePullImages_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseSetPrePullImages_Success
+}
+
+func (x *ResponseSetPrePullImages) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RequestPrePullStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RequestPrePullStatus) Reset() {
+	*x = RequestPrePullStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestPrePullStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPrePullStatus) ProtoMessage() {}
+
+func (x *RequestPrePullStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPrePullStatus.ProtoReflect.Descriptor instead.
+func (*RequestPrePullStatus) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{33}
+}
+
+// 镜像在各个节点上的拉取状态
+type ImagePrePullStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// 已经拉取了镜像的节点
+	ReadyNodes []string `protobuf:"bytes,2,rep,name=readyNodes,proto3" json:"readyNodes,omitempty"`
+	// 正在拉取镜像的节点
+	PendingNodes []string `protobuf:"bytes,3,rep,name=pendingNodes,proto3" json:"pendingNodes,omitempty"`
+	// 拉取镜像失败的节点
+	FailedNodes []string `protobuf:"bytes,4,rep,name=failedNodes,proto3" json:"failedNodes,omitempty"`
+	// 最近一次拉取失败的原因
+	Message string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *ImagePrePullStatus) Reset() {
+	*x = ImagePrePullStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImagePrePullStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImagePrePullStatus) ProtoMessage() {}
+
+func (x *ImagePrePullStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImagePrePullStatus.ProtoReflect.Descriptor instead.
+func (*ImagePrePullStatus) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ImagePrePullStatus) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ImagePrePullStatus) GetReadyNodes() []string {
+	if x != nil {
+		return x.ReadyNodes
+	}
+	return nil
+}
+
+func (x *ImagePrePullStatus) GetPendingNodes() []string {
+	if x != nil {
+		return x.PendingNodes
+	}
+	return nil
+}
+
+func (x *ImagePrePullStatus) GetFailedNodes() []string {
+	if x != nil {
+		return x.FailedNodes
+	}
+	return nil
+}
+
+func (x *ImagePrePullStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ResponsePrePullStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Images []*ImagePrePullStatus `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"`
+	// 运行预拉取Pod的节点数量
+	Nodes int32 `protobuf:"varint,2,opt,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *ResponsePrePullStatus) Reset() {
+	*x = ResponsePrePullStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponsePrePullStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponsePrePullStatus) ProtoMessage() {}
+
+func (x *ResponsePrePullStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponsePrePullStatus.ProtoReflect.Descriptor instead.
+func (*ResponsePrePullStatus) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ResponsePrePullStatus) GetImages() []*ImagePrePullStatus {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+func (x *ResponsePrePullStatus) GetNodes() int32 {
+	if x != nil {
+		return x.Nodes
+	}
+	return 0
+}
+
+type RequestWorkspaceEvents struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Uid string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
+}
+
+func (x *RequestWorkspaceEvents) Reset() {
+	*x = RequestWorkspaceEvents{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestWorkspaceEvents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestWorkspaceEvents) ProtoMessage() {}
+
+func (x *RequestWorkspaceEvents) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestWorkspaceEvents.ProtoReflect.Descriptor instead.
+func (*RequestWorkspaceEvents) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RequestWorkspaceEvents) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *RequestWorkspaceEvents) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+// 工作空间及其Pod和存储卷的Kubernetes事件
+type WorkspaceEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Normal或Warning
+	Type    string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Reason  string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// 事件所属的对象的类型,WorkSpace、Pod或者PersistentVolumeClaim
+	Kind string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	// 相同的事件发生的次数
+	Count int32 `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
+	// 最近一次发生的时间,unix时间戳
+	Timestamp int64 `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (x *WorkspaceEvent) Reset() {
+	*x = WorkspaceEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkspaceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkspaceEvent) ProtoMessage() {}
+
+func (x *WorkspaceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkspaceEvent.ProtoReflect.Descriptor instead.
+func (*WorkspaceEvent) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *WorkspaceEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WorkspaceEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *WorkspaceEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *WorkspaceEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *WorkspaceEvent) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *WorkspaceEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ResponseWorkspaceEvents struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ResponseWorkspaceEvents_Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.ResponseWorkspaceEvents_Status" json:"status,omitempty"`
+	Message string                         `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// 按照发生时间排序
+	Events []*WorkspaceEvent `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *ResponseWorkspaceEvents) Reset() {
+	*x = ResponseWorkspaceEvents{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseWorkspaceEvents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseWorkspaceEvents) ProtoMessage() {}
+
+func (x *ResponseWorkspaceEvents) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseWorkspaceEvents.ProtoReflect.Descriptor instead.
+func (*ResponseWorkspaceEvents) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ResponseWorkspaceEvents) GetStatus() ResponseWorkspaceEvents_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ResponseWorkspaceEvents_Success
+}
+
+func (x *ResponseWorkspaceEvents) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ResponseWorkspaceEvents) GetEvents() []*WorkspaceEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type ResponseRunningWorkspace_WorkspaceBasicInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sid  string `protobuf:"bytes,1,opt,name=sid,proto3" json:"sid,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) Reset() {
+	*x = ResponseRunningWorkspace_WorkspaceBasicInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pb_proto_service_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseRunningWorkspace_WorkspaceBasicInfo) ProtoMessage() {}
+
+func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_proto_service_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseRunningWorkspace_WorkspaceBasicInfo.ProtoReflect.Descriptor instead.
+func (*ResponseRunningWorkspace_WorkspaceBasicInfo) Descriptor() ([]byte, []int) {
+	return file_pb_proto_service_proto_rawDescGZIP(), []int{25, 0}
+}
+
+func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) GetSid() string {
+	if x != nil {
+		return x.Sid
+	}
+	return ""
+}
+
+func (x *ResponseRunningWorkspace_WorkspaceBasicInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+var File_pb_proto_service_proto protoreflect.FileDescriptor
+
+var file_pb_proto_service_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x62, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x22, 0x83, 0x01, 0x0a,
+	0x0d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x70, 0x75,
+	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x74, 0x6f, 0x72,
+	0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x68, 0x65,
+	0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67, 0x52, 0x0a, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x69,
+	0x6e, 0x67, 0x22, 0xaf, 0x02, 0x0a, 0x0a, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x69, 0x6e,
+	0x67, 0x12, 0x44, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x69, 0x6e, 0x67, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x53,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x30, 0x0a, 0x0b, 0x74, 0x6f, 0x6c, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70,
+	0x62, 0x2e, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x6f,
+	0x6c, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x74, 0x79, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3a, 0x0a, 0x0e, 0x74, 0x6f, 0x70, 0x6f, 0x6c,
+	0x6f, 0x67, 0x79, 0x53, 0x70, 0x72, 0x65, 0x61, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x53, 0x70, 0x72,
+	0x65, 0x61, 0x64, 0x52, 0x0e, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x53, 0x70, 0x72,
+	0x65, 0x61, 0x64, 0x1a, 0x3f, 0x0a, 0x11, 0x4e, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x96, 0x01, 0x0a, 0x0a, 0x54, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x12,
+	0x2c, 0x0a, 0x11, 0x74, 0x6f, 0x6c, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x74, 0x6f, 0x6c, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x74, 0x0a,
+	0x0e, 0x54, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x53, 0x70, 0x72, 0x65, 0x61, 0x64, 0x12,
+	0x20, 0x0a, 0x0b, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x4b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x4b, 0x65,
+	0x79, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x6b, 0x65, 0x77, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x6b, 0x65, 0x77, 0x12, 0x26, 0x0a, 0x0e, 0x73,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x41, 0x6e, 0x79, 0x77, 0x61, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x41, 0x6e, 0x79,
+	0x77, 0x61, 0x79, 0x22, 0xd6, 0x05, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70,
+	0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x67, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x67, 0x69, 0x74, 0x52,
+	0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x12, 0x37, 0x0a, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x0d, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x28, 0x0a, 0x0f,
+	0x69, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x2e, 0x45, 0x6e, 0x76, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x69, 0x74, 0x52, 0x65,
+	0x66, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x69, 0x74, 0x52, 0x65, 0x66, 0x12,
+	0x1a, 0x0a, 0x08, 0x67, 0x69, 0x74, 0x44, 0x65, 0x70, 0x74, 0x68, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x67, 0x69, 0x74, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x39, 0x0a, 0x0e, 0x67,
+	0x69, 0x74, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x0c, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x0e, 0x67, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x35, 0x0a, 0x0c, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69,
+	0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70,
+	0x62, 0x2e, 0x47, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x52,
+	0x0c, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x2d, 0x0a,
+	0x08, 0x64, 0x6f, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x08, 0x64, 0x6f, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x05,
+	0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62,
+	0x2e, 0x4c, 0x69, 0x66, 0x65, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x48, 0x6f, 0x6f, 0x6b, 0x73, 0x52,
+	0x05, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x31, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52,
+	0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x65, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x45,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x65, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x1a, 0x37, 0x0a, 0x09, 0x45, 0x6e, 0x76, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x34, 0x0a, 0x0c,
+	0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x24, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x62,
+	0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x22, 0x36, 0x0a, 0x0a, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x63, 0x69, 0x64, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x59, 0x0a, 0x11, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x22, 0x6b, 0x0a, 0x12, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x53, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12,
+	0x31, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x22, 0x97, 0x01, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
+	0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64,
+	0x75, 0x6c, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2e, 0x0a, 0x06,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10,
+	0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x4e, 0x0a, 0x12,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0x97, 0x01, 0x0a,
+	0x13, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2e, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x0c, 0x0a,
+	0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x68, 0x0a, 0x0e, 0x4c, 0x69, 0x66, 0x65, 0x63, 0x79,
+	0x63, 0x6c, 0x65, 0x48, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x6f, 0x73, 0x74,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x6f,
+	0x73, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x6f, 0x73, 0x74,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6f, 0x73,
+	0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x53, 0x74, 0x6f,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x65, 0x53, 0x74, 0x6f, 0x70,
+	0x22, 0x5d, 0x0a, 0x0d, 0x47, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x70,
+	0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x22,
+	0xbf, 0x01, 0x0a, 0x0d, 0x47, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x48, 0x6f,
+	0x73, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x6e, 0x6f, 0x77, 0x6e,
+	0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x1a, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a,
+	0x05, 0x48, 0x74, 0x74, 0x70, 0x73, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x73, 0x68, 0x10,
+	0x01, 0x22, 0x91, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0x32, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x6c, 0x72, 0x65,
+	0x61, 0x64, 0x79, 0x45, 0x78, 0x69, 0x73, 0x74, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0xb4, 0x03, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x37, 0x0a, 0x0d, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x2e, 0x45, 0x6e, 0x76, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x65,
+	0x6e, 0x76, 0x73, 0x12, 0x39, 0x0a, 0x0e, 0x67, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62,
+	0x2e, 0x47, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x52, 0x0e,
+	0x67, 0x69, 0x74, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x2d,
+	0x0a, 0x08, 0x64, 0x6f, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x6f, 0x72, 0x79, 0x52, 0x08, 0x64, 0x6f, 0x74, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x28, 0x0a,
+	0x05, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70,
+	0x62, 0x2e, 0x4c, 0x69, 0x66, 0x65, 0x63, 0x79, 0x63, 0x6c, 0x65, 0x48, 0x6f, 0x6f, 0x6b, 0x73,
+	0x52, 0x05, 0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x28, 0x0a, 0x06, 0x65, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x45, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x65, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x75, 0x73, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x72, 0x65, 0x75, 0x73, 0x65, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x1a, 0x37, 0x0a, 0x09, 0x45, 0x6e, 0x76, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8b, 0x01, 0x0a,
+	0x0d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x30,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2e, 0x0a, 0x06, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10,
+	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01, 0x12,
+	0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x55, 0x0a, 0x0b, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x22, 0x0a,
+	0x0c, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x73, 0x22, 0xb7, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74,
+	0x6f, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x53, 0x74, 0x6f, 0x70, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2c, 0x0a,
+	0x11, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x64, 0x53, 0x74, 0x6f, 0x70, 0x54, 0x69,
+	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x64, 0x53, 0x74, 0x6f, 0x70, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x2e, 0x0a, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01,
+	0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x37, 0x0a, 0x11, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53, 0x74, 0x6f, 0x70,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73,
+	0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x75, 0x69, 0x64, 0x22, 0xa7, 0x01, 0x0a, 0x12, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x35, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x70, 0x62,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53,
+	0x74, 0x6f, 0x70, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x40, 0x0a, 0x06,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10,
+	0x01, 0x12, 0x10, 0x0a, 0x0c, 0x4e, 0x6f, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x64, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x03, 0x22, 0xe3,
+	0x01, 0x0a, 0x0d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73,
+	0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x75, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x2e, 0x52, 0x65, 0x74, 0x65, 0x6e,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x44, 0x61, 0x79, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x44, 0x61, 0x79, 0x73, 0x12,
+	0x20, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x55, 0x6e, 0x74, 0x69,
+	0x6c, 0x22, 0x31, 0x0a, 0x09, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a,
+	0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x65,
+	0x74, 0x61, 0x69, 0x6e, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x10, 0x02, 0x22, 0x7f, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x20, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a,
+	0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x10, 0x01, 0x22, 0x2c, 0x0a, 0x18, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x73, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x22, 0xcc, 0x01, 0x0a, 0x18, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x12, 0x4f, 0x0a, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42, 0x61, 0x73, 0x69,
+	0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x73, 0x1a, 0x3a, 0x0a, 0x12, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42, 0x61,
+	0x73, 0x69, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x23, 0x0a,
+	0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74, 0x46, 0x6f, 0x75, 0x6e, 0x64,
+	0x10, 0x01, 0x22, 0x7e, 0x0a, 0x16, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x1a,
+	0x0a, 0x08, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x22, 0xa5, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x3a,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x22,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x20, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12,
+	0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x01, 0x22, 0x44, 0x0a, 0x1c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x9d, 0x01, 0x0a, 0x1d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x12, 0x40, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x28, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x20,
+	0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x01,
+	0x22, 0x44, 0x0a, 0x0c, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x75, 0x6c, 0x6c, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x75, 0x6c, 0x6c,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x43, 0x0a, 0x17, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x73, 0x12, 0x28, 0x0a, 0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x52, 0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x22, 0x93, 0x01, 0x0a, 0x18,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x50, 0x75,
+	0x6c, 0x6c, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x20, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10,
+	0x01, 0x22, 0x16, 0x0a, 0x14, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x50, 0x72, 0x65, 0x50,
+	0x75, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xaa, 0x01, 0x0a, 0x12, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x61, 0x64, 0x79, 0x4e,
+	0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x61, 0x64,
+	0x79, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x66, 0x61,
+	0x69, 0x6c, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x5d, 0x0a, 0x15, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x2e, 0x0a, 0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c,
+	0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x3c, 0x0a, 0x16, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x73, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x69,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x75, 0x69, 0x64, 0x22, 0x9e, 0x01, 0x0a, 0x0e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6b, 0x69, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x22, 0xcb, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x3a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x22, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x57, 0x6f,
+	0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x22, 0x2e, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07,
+	0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x74,
+	0x46, 0x6f, 0x75, 0x6e, 0x64, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x10, 0x02, 0x32, 0x82, 0x07, 0x0a, 0x0f, 0x43, 0x6c, 0x6f, 0x75, 0x64, 0x49, 0x64, 0x65, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x0a,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x10, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x1a, 0x11, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x34, 0x0a, 0x0b, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x53, 0x70, 0x61, 0x63, 0x65, 0x12, 0x11,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x1a, 0x12, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x70, 0x53, 0x70, 0x61,
+	0x63, 0x65, 0x12, 0x0f, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53,
+	0x74, 0x6f, 0x70, 0x1a, 0x10, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x53, 0x74, 0x6f, 0x70, 0x12, 0x3b, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53,
+	0x74, 0x6f, 0x70, 0x12, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53, 0x74, 0x6f, 0x70, 0x1a, 0x16, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x53, 0x74,
+	0x6f, 0x70, 0x12, 0x3e, 0x0a, 0x0b, 0x73, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c,
+	0x65, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x65,
+	0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x65, 0x74, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75,
+	0x6c, 0x65, 0x12, 0x3e, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x16, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x12, 0x4f, 0x0a, 0x11, 0x72, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72,
+	0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x73, 0x1a, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x12, 0x50, 0x0a, 0x15, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1a, 0x2e, 0x70,
+	0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75,
+	0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x1a, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x5c, 0x0a, 0x15, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x20,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x1a, 0x21, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x12, 0x4d, 0x0a, 0x10, 0x73, 0x65, 0x74, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c,
+	0x6c, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x73, 0x1a, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x53, 0x65, 0x74, 0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x73, 0x12, 0x44, 0x0a, 0x0d, 0x70, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x50, 0x72, 0x65, 0x50, 0x75, 0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x1a, 0x19, 0x2e,
+	0x70, 0x62, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x50, 0x72, 0x65, 0x50, 0x75,
+	0x6c, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x4d, 0x0a, 0x12, 0x67, 0x65, 0x74, 0x57,
+	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1a,
+	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x1b, 0x2e, 0x70, 0x62, 0x2e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x07, 0x5a, 0x05, 0x2e, 0x2f, 0x3b, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pb_proto_service_proto_rawDescOnce sync.Once
+	file_pb_proto_service_proto_rawDescData = file_pb_proto_service_proto_rawDesc
+)
+
+func file_pb_proto_service_proto_rawDescGZIP() []byte {
+	file_pb_proto_service_proto_rawDescOnce.Do(func() {
 		file_pb_proto_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_pb_proto_service_proto_rawDescData)
 	})
 	return file_pb_proto_service_proto_rawDescData
 }
 
-var file_pb_proto_service_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_pb_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pb_proto_service_proto_enumTypes = make([]protoimpl.EnumInfo, 14)
+var file_pb_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_pb_proto_service_proto_goTypes = []interface{}{
-	(ResponseCreate_Status)(0),                          // 0: pb.ResponseCreate.Status
-	(ResponseStart_Status)(0),                           // 1: pb.ResponseStart.Status
-	(ResponseStop_Status)(0),                            // 2: pb.ResponseStop.Status
-	(ResponseDelete_Status)(0),                          // 3: pb.ResponseDelete.Status
-	(ResponseRunningWorkspace_Status)(0),                // 4: pb.ResponseRunningWorkspace.Status
-	(*ResourceLimit)(nil),                               // 5: pb.ResourceLimit
-	(*RequestCreate)(nil),                               // 6: pb.RequestCreate
-	(*ResponseCreate)(nil),                              // 7: pb.ResponseCreate
-	(*RequestStart)(nil),                                // 8: pb.RequestStart
-	(*ResponseStart)(nil),                               // 9: pb.ResponseStart
-	(*RequestStop)(nil),                                 // 10: pb.RequestStop
-	(*ResponseStop)(nil),                                // 11: pb.ResponseStop
-	(*RequestDelete)(nil),                               // 12: pb.RequestDelete
-	(*ResponseDelete)(nil),                              // 13: pb.ResponseDelete
-	(*RequestRunningWorkspaces)(nil),                    // 14: pb.RequestRunningWorkspaces
-	(*ResponseRunningWorkspace)(nil),                    // 15: pb.ResponseRunningWorkspace
-	(*ResponseRunningWorkspace_WorkspaceBasicInfo)(nil), // 16: pb.ResponseRunningWorkspace.WorkspaceBasicInfo
+	(ResponseSetSchedule_Status)(0),                     // 0: pb.ResponseSetSchedule.Status
+	(ResponseUpdateImage_Status)(0),                     // 1: pb.ResponseUpdateImage.Status
+	(GitCredential_Type)(0),                             // 2: pb.GitCredential.Type
+	(ResponseCreate_Status)(0),                          // 3: pb.ResponseCreate.Status
+	(ResponseStart_Status)(0),                           // 4: pb.ResponseStart.Status
+	(ResponseStop_Status)(0),                            // 5: pb.ResponseStop.Status
+	(ResponseCancelStop_Status)(0),                      // 6: pb.ResponseCancelStop.Status
+	(RequestDelete_Retention)(0),                        // 7: pb.RequestDelete.Retention
+	(ResponseDelete_Status)(0),                          // 8: pb.ResponseDelete.Status
+	(ResponseRunningWorkspace_Status)(0),                // 9: pb.ResponseRunningWorkspace.Status
+	(ResponseImagePullSecret_Status)(0),                 // 10: pb.ResponseImagePullSecret.Status
+	(ResponseDeleteImagePullSecret_Status)(0),           // 11: pb.ResponseDeleteImagePullSecret.Status
+	(ResponseSetPrePullImages_Status)(0),                // 12: pb.ResponseSetPrePullImages.Status
+	(ResponseWorkspaceEvents_Status)(0),                 // 13: pb.ResponseWorkspaceEvents.Status
+	(*ResourceLimit)(nil),                               // 14: pb.ResourceLimit
+	(*Scheduling)(nil),                                  // 15: pb.Scheduling
+	(*Toleration)(nil),                                  // 16: pb.Toleration
+	(*TopologySpread)(nil),                              // 17: pb.TopologySpread
+	(*RequestCreate)(nil),                               // 18: pb.RequestCreate
+	(*EgressPolicy)(nil),                                // 19: pb.EgressPolicy
+	(*EgressRule)(nil),                                  // 20: pb.EgressRule
+	(*WorkspaceSchedule)(nil),                           // 21: pb.WorkspaceSchedule
+	(*RequestSetSchedule)(nil),                          // 22: pb.RequestSetSchedule
+	(*ResponseSetSchedule)(nil),                         // 23: pb.ResponseSetSchedule
+	(*RequestUpdateImage)(nil),                          // 24: pb.RequestUpdateImage
+	(*ResponseUpdateImage)(nil),                         // 25: pb.ResponseUpdateImage
+	(*LifecycleHooks)(nil),                              // 26: pb.LifecycleHooks
+	(*GitRepository)(nil),                               // 27: pb.GitRepository
+	(*GitCredential)(nil),                               // 28: pb.GitCredential
+	(*ResponseCreate)(nil),                              // 29: pb.ResponseCreate
+	(*RequestStart)(nil),                                // 30: pb.RequestStart
+	(*ResponseStart)(nil),                               // 31: pb.ResponseStart
+	(*RequestStop)(nil),                                 // 32: pb.RequestStop
+	(*ResponseStop)(nil),                                // 33: pb.ResponseStop
+	(*RequestCancelStop)(nil),                           // 34: pb.RequestCancelStop
+	(*ResponseCancelStop)(nil),                          // 35: pb.ResponseCancelStop
+	(*RequestDelete)(nil),                               // 36: pb.RequestDelete
+	(*ResponseDelete)(nil),                              // 37: pb.ResponseDelete
+	(*RequestRunningWorkspaces)(nil),                    // 38: pb.RequestRunningWorkspaces
+	(*ResponseRunningWorkspace)(nil),                    // 39: pb.ResponseRunningWorkspace
+	(*RequestImagePullSecret)(nil),                      // 40: pb.RequestImagePullSecret
+	(*ResponseImagePullSecret)(nil),                     // 41: pb.ResponseImagePullSecret
+	(*RequestDeleteImagePullSecret)(nil),                // 42: pb.RequestDeleteImagePullSecret
+	(*ResponseDeleteImagePullSecret)(nil),               // 43: pb.ResponseDeleteImagePullSecret
+	(*PrePullImage)(nil),                                // 44: pb.PrePullImage
+	(*RequestSetPrePullImages)(nil),                     // 45: pb.RequestSetPrePullImages
+	(*ResponseSetPrePullImages)(nil),                    // 46: pb.ResponseSetPrePullImages
+	(*RequestPrePullStatus)(nil),                        // 47: pb.RequestPrePullStatus
+	(*ImagePrePullStatus)(nil),                          // 48: pb.ImagePrePullStatus
+	(*ResponsePrePullStatus)(nil),                       // 49: pb.ResponsePrePullStatus
+	(*RequestWorkspaceEvents)(nil),                      // 50: pb.RequestWorkspaceEvents
+	(*WorkspaceEvent)(nil),                              // 51: pb.WorkspaceEvent
+	(*ResponseWorkspaceEvents)(nil),                     // 52: pb.ResponseWorkspaceEvents
+	nil,                                                 // 53: pb.Scheduling.NodeSelectorEntry
+	nil,                                                 // 54: pb.RequestCreate.EnvsEntry
+	nil,                                                 // 55: pb.RequestStart.EnvsEntry
+	(*ResponseRunningWorkspace_WorkspaceBasicInfo)(nil), // 56: pb.ResponseRunningWorkspace.WorkspaceBasicInfo
 }
 var file_pb_proto_service_proto_depIdxs = []int32{
-	5,  // 0: pb.RequestCreate.resourceLimit:type_name -> pb.ResourceLimit
-	0,  // 1: pb.ResponseCreate.status:type_name -> pb.ResponseCreate.Status
-	5,  // 2: pb.RequestStart.resourceLimit:type_name -> pb.ResourceLimit
-	1,  // 3: pb.ResponseStart.status:type_name -> pb.ResponseStart.Status
-	2,  // 4: pb.ResponseStop.status:type_name -> pb.ResponseStop.Status
-	3,  // 5: pb.ResponseDelete.status:type_name -> pb.ResponseDelete.Status
-	16, // 6: pb.ResponseRunningWorkspace.workspaces:type_name -> pb.ResponseRunningWorkspace.WorkspaceBasicInfo
-	6,  // 7: pb.CloudIdeService.createSpace:input_type -> pb.RequestCreate
-	8,  // 8: pb.CloudIdeService.startSpace:input_type -> pb.RequestStart
-	12, // 9: pb.CloudIdeService.deleteSpace:input_type -> pb.RequestDelete
-	10, // 10: pb.CloudIdeService.stopSpace:input_type -> pb.RequestStop
-	14, // 11: pb.CloudIdeService.runningWorkspaces:input_type -> pb.RequestRunningWorkspaces
-	7,  // 12: pb.CloudIdeService.createSpace:output_type -> pb.ResponseCreate
-	9,  // 13: pb.CloudIdeService.startSpace:output_type -> pb.ResponseStart
-	13, // 14: pb.CloudIdeService.deleteSpace:output_type -> pb.ResponseDelete
-	11, // 15: pb.CloudIdeService.stopSpace:output_type -> pb.ResponseStop
-	15, // 16: pb.CloudIdeService.runningWorkspaces:output_type -> pb.ResponseRunningWorkspace
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	15, // 0: pb.ResourceLimit.scheduling:type_name -> pb.Scheduling
+	53, // 1: pb.Scheduling.nodeSelector:type_name -> pb.Scheduling.NodeSelectorEntry
+	16, // 2: pb.Scheduling.tolerations:type_name -> pb.Toleration
+	17, // 3: pb.Scheduling.topologySpread:type_name -> pb.TopologySpread
+	14, // 4: pb.RequestCreate.resourceLimit:type_name -> pb.ResourceLimit
+	54, // 5: pb.RequestCreate.envs:type_name -> pb.RequestCreate.EnvsEntry
+	28, // 6: pb.RequestCreate.gitCredentials:type_name -> pb.GitCredential
+	27, // 7: pb.RequestCreate.repositories:type_name -> pb.GitRepository
+	27, // 8: pb.RequestCreate.dotfiles:type_name -> pb.GitRepository
+	26, // 9: pb.RequestCreate.hooks:type_name -> pb.LifecycleHooks
+	21, // 10: pb.RequestCreate.schedule:type_name -> pb.WorkspaceSchedule
+	19, // 11: pb.RequestCreate.egress:type_name -> pb.EgressPolicy
+	20, // 12: pb.EgressPolicy.rules:type_name -> pb.EgressRule
+	21, // 13: pb.RequestSetSchedule.schedule:type_name -> pb.WorkspaceSchedule
+	0,  // 14: pb.ResponseSetSchedule.status:type_name -> pb.ResponseSetSchedule.Status
+	1,  // 15: pb.ResponseUpdateImage.status:type_name -> pb.ResponseUpdateImage.Status
+	2,  // 16: pb.GitCredential.type:type_name -> pb.GitCredential.Type
+	3,  // 17: pb.ResponseCreate.status:type_name -> pb.ResponseCreate.Status
+	14, // 18: pb.RequestStart.resourceLimit:type_name -> pb.ResourceLimit
+	55, // 19: pb.RequestStart.envs:type_name -> pb.RequestStart.EnvsEntry
+	28, // 20: pb.RequestStart.gitCredentials:type_name -> pb.GitCredential
+	27, // 21: pb.RequestStart.dotfiles:type_name -> pb.GitRepository
+	26, // 22: pb.RequestStart.hooks:type_name -> pb.LifecycleHooks
+	19, // 23: pb.RequestStart.egress:type_name -> pb.EgressPolicy
+	4,  // 24: pb.ResponseStart.status:type_name -> pb.ResponseStart.Status
+	5,  // 25: pb.ResponseStop.status:type_name -> pb.ResponseStop.Status
+	6,  // 26: pb.ResponseCancelStop.status:type_name -> pb.ResponseCancelStop.Status
+	7,  // 27: pb.RequestDelete.retention:type_name -> pb.RequestDelete.Retention
+	8,  // 28: pb.ResponseDelete.status:type_name -> pb.ResponseDelete.Status
+	56, // 29: pb.ResponseRunningWorkspace.workspaces:type_name -> pb.ResponseRunningWorkspace.WorkspaceBasicInfo
+	10, // 30: pb.ResponseImagePullSecret.status:type_name -> pb.ResponseImagePullSecret.Status
+	11, // 31: pb.ResponseDeleteImagePullSecret.status:type_name -> pb.ResponseDeleteImagePullSecret.Status
+	44, // 32: pb.RequestSetPrePullImages.images:type_name -> pb.PrePullImage
+	12, // 33: pb.ResponseSetPrePullImages.status:type_name -> pb.ResponseSetPrePullImages.Status
+	48, // 34: pb.ResponsePrePullStatus.images:type_name -> pb.ImagePrePullStatus
+	13, // 35: pb.ResponseWorkspaceEvents.status:type_name -> pb.ResponseWorkspaceEvents.Status
+	51, // 36: pb.ResponseWorkspaceEvents.events:type_name -> pb.WorkspaceEvent
+	18, // 37: pb.CloudIdeService.createSpace:input_type -> pb.RequestCreate
+	30, // 38: pb.CloudIdeService.startSpace:input_type -> pb.RequestStart
+	36, // 39: pb.CloudIdeService.deleteSpace:input_type -> pb.RequestDelete
+	32, // 40: pb.CloudIdeService.stopSpace:input_type -> pb.RequestStop
+	34, // 41: pb.CloudIdeService.cancelStop:input_type -> pb.RequestCancelStop
+	22, // 42: pb.CloudIdeService.setSchedule:input_type -> pb.RequestSetSchedule
+	24, // 43: pb.CloudIdeService.updateImage:input_type -> pb.RequestUpdateImage
+	38, // 44: pb.CloudIdeService.runningWorkspaces:input_type -> pb.RequestRunningWorkspaces
+	40, // 45: pb.CloudIdeService.createImagePullSecret:input_type -> pb.RequestImagePullSecret
+	42, // 46: pb.CloudIdeService.deleteImagePullSecret:input_type -> pb.RequestDeleteImagePullSecret
+	45, // 47: pb.CloudIdeService.setPrePullImages:input_type -> pb.RequestSetPrePullImages
+	47, // 48: pb.CloudIdeService.prePullStatus:input_type -> pb.RequestPrePullStatus
+	50, // 49: pb.CloudIdeService.getWorkspaceEvents:input_type -> pb.RequestWorkspaceEvents
+	29, // 50: pb.CloudIdeService.createSpace:output_type -> pb.ResponseCreate
+	31, // 51: pb.CloudIdeService.startSpace:output_type -> pb.ResponseStart
+	37, // 52: pb.CloudIdeService.deleteSpace:output_type -> pb.ResponseDelete
+	33, // 53: pb.CloudIdeService.stopSpace:output_type -> pb.ResponseStop
+	35, // 54: pb.CloudIdeService.cancelStop:output_type -> pb.ResponseCancelStop
+	23, // 55: pb.CloudIdeService.setSchedule:output_type -> pb.ResponseSetSchedule
+	25, // 56: pb.CloudIdeService.updateImage:output_type -> pb.ResponseUpdateImage
+	39, // 57: pb.CloudIdeService.runningWorkspaces:output_type -> pb.ResponseRunningWorkspace
+	41, // 58: pb.CloudIdeService.createImagePullSecret:output_type -> pb.ResponseImagePullSecret
+	43, // 59: pb.CloudIdeService.deleteImagePullSecret:output_type -> pb.ResponseDeleteImagePullSecret
+	46, // 60: pb.CloudIdeService.setPrePullImages:output_type -> pb.ResponseSetPrePullImages
+	49, // 61: pb.CloudIdeService.prePullStatus:output_type -> pb.ResponsePrePullStatus
+	52, // 62: pb.CloudIdeService.getWorkspaceEvents:output_type -> pb.ResponseWorkspaceEvents
+	50, // [50:63] is the sub-list for method output_type
+	37, // [37:50] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
 }
 
 func init() { file_pb_proto_service_proto_init() }
@@ -1149,8 +3942,68 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RequestCreate); i {
+		file_pb_proto_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Scheduling); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Toleration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TopologySpread); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestCreate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EgressPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EgressRule); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1161,7 +4014,103 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkspaceSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestSetSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseSetSchedule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestUpdateImage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseUpdateImage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LifecycleHooks); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitRepository); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitCredential); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseCreate); i {
 			case 0:
 				return &v.state
@@ -1173,7 +4122,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RequestStart); i {
 			case 0:
 				return &v.state
@@ -1185,7 +4134,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseStart); i {
 			case 0:
 				return &v.state
@@ -1197,7 +4146,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RequestStop); i {
 			case 0:
 				return &v.state
@@ -1209,7 +4158,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseStop); i {
 			case 0:
 				return &v.state
@@ -1221,7 +4170,31 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestCancelStop); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseCancelStop); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RequestDelete); i {
 			case 0:
 				return &v.state
@@ -1233,7 +4206,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseDelete); i {
 			case 0:
 				return &v.state
@@ -1245,7 +4218,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RequestRunningWorkspaces); i {
 			case 0:
 				return &v.state
@@ -1257,7 +4230,7 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseRunningWorkspace); i {
 			case 0:
 				return &v.state
@@ -1269,7 +4242,163 @@ func file_pb_proto_service_proto_init() {
 				return nil
 			}
 		}
-		file_pb_proto_service_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_pb_proto_service_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestImagePullSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseImagePullSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestDeleteImagePullSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseDeleteImagePullSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrePullImage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestSetPrePullImages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseSetPrePullImages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestPrePullStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImagePrePullStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponsePrePullStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestWorkspaceEvents); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkspaceEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResponseWorkspaceEvents); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pb_proto_service_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ResponseRunningWorkspace_WorkspaceBasicInfo); i {
 			case 0:
 				return &v.state
@@ -1287,8 +4416,8 @@ func file_pb_proto_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pb_proto_service_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   12,
+			NumEnums:      14,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
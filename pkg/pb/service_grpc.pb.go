@@ -19,11 +19,19 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CloudIdeService_CreateSpace_FullMethodName       = "/pb.CloudIdeService/createSpace"
-	CloudIdeService_StartSpace_FullMethodName        = "/pb.CloudIdeService/startSpace"
-	CloudIdeService_DeleteSpace_FullMethodName       = "/pb.CloudIdeService/deleteSpace"
-	CloudIdeService_StopSpace_FullMethodName         = "/pb.CloudIdeService/stopSpace"
-	CloudIdeService_RunningWorkspaces_FullMethodName = "/pb.CloudIdeService/runningWorkspaces"
+	CloudIdeService_CreateSpace_FullMethodName           = "/pb.CloudIdeService/createSpace"
+	CloudIdeService_StartSpace_FullMethodName            = "/pb.CloudIdeService/startSpace"
+	CloudIdeService_DeleteSpace_FullMethodName           = "/pb.CloudIdeService/deleteSpace"
+	CloudIdeService_StopSpace_FullMethodName             = "/pb.CloudIdeService/stopSpace"
+	CloudIdeService_CancelStop_FullMethodName            = "/pb.CloudIdeService/cancelStop"
+	CloudIdeService_SetSchedule_FullMethodName           = "/pb.CloudIdeService/setSchedule"
+	CloudIdeService_UpdateImage_FullMethodName           = "/pb.CloudIdeService/updateImage"
+	CloudIdeService_RunningWorkspaces_FullMethodName     = "/pb.CloudIdeService/runningWorkspaces"
+	CloudIdeService_CreateImagePullSecret_FullMethodName = "/pb.CloudIdeService/createImagePullSecret"
+	CloudIdeService_DeleteImagePullSecret_FullMethodName = "/pb.CloudIdeService/deleteImagePullSecret"
+	CloudIdeService_SetPrePullImages_FullMethodName      = "/pb.CloudIdeService/setPrePullImages"
+	CloudIdeService_PrePullStatus_FullMethodName         = "/pb.CloudIdeService/prePullStatus"
+	CloudIdeService_GetWorkspaceEvents_FullMethodName    = "/pb.CloudIdeService/getWorkspaceEvents"
 )
 
 // CloudIdeServiceClient is the client API for CloudIdeService service.
@@ -38,8 +46,24 @@ type CloudIdeServiceClient interface {
 	DeleteSpace(ctx context.Context, in *RequestDelete, opts ...grpc.CallOption) (*ResponseDelete, error)
 	// 停止(删除)云工作空间,无需删除存储卷
 	StopSpace(ctx context.Context, in *RequestStop, opts ...grpc.CallOption) (*ResponseStop, error)
+	// 取消计划的停止
+	CancelStop(ctx context.Context, in *RequestCancelStop, opts ...grpc.CallOption) (*ResponseCancelStop, error)
+	// 设置定时启动和停止
+	SetSchedule(ctx context.Context, in *RequestSetSchedule, opts ...grpc.CallOption) (*ResponseSetSchedule, error)
+	// 修改工作空间的镜像
+	UpdateImage(ctx context.Context, in *RequestUpdateImage, opts ...grpc.CallOption) (*ResponseUpdateImage, error)
 	// 获取运行中的Workspace
 	RunningWorkspaces(ctx context.Context, in *RequestRunningWorkspaces, opts ...grpc.CallOption) (*ResponseRunningWorkspace, error)
+	// 创建或更新用户拉取私有镜像使用的Secret
+	CreateImagePullSecret(ctx context.Context, in *RequestImagePullSecret, opts ...grpc.CallOption) (*ResponseImagePullSecret, error)
+	// 删除用户拉取私有镜像使用的Secret
+	DeleteImagePullSecret(ctx context.Context, in *RequestDeleteImagePullSecret, opts ...grpc.CallOption) (*ResponseDeleteImagePullSecret, error)
+	// 设置需要预先拉取到所有节点上的镜像
+	SetPrePullImages(ctx context.Context, in *RequestSetPrePullImages, opts ...grpc.CallOption) (*ResponseSetPrePullImages, error)
+	// 获取镜像在各个节点上的拉取状态
+	PrePullStatus(ctx context.Context, in *RequestPrePullStatus, opts ...grpc.CallOption) (*ResponsePrePullStatus, error)
+	// 获取工作空间的事件,用于在页面上展示启动过程和失败原因
+	GetWorkspaceEvents(ctx context.Context, in *RequestWorkspaceEvents, opts ...grpc.CallOption) (*ResponseWorkspaceEvents, error)
 }
 
 type cloudIdeServiceClient struct {
@@ -86,6 +110,33 @@ func (c *cloudIdeServiceClient) StopSpace(ctx context.Context, in *RequestStop,
 	return out, nil
 }
 
+func (c *cloudIdeServiceClient) CancelStop(ctx context.Context, in *RequestCancelStop, opts ...grpc.CallOption) (*ResponseCancelStop, error) {
+	out := new(ResponseCancelStop)
+	err := c.cc.Invoke(ctx, CloudIdeService_CancelStop_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudIdeServiceClient) SetSchedule(ctx context.Context, in *RequestSetSchedule, opts ...grpc.CallOption) (*ResponseSetSchedule, error) {
+	out := new(ResponseSetSchedule)
+	err := c.cc.Invoke(ctx, CloudIdeService_SetSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudIdeServiceClient) UpdateImage(ctx context.Context, in *RequestUpdateImage, opts ...grpc.CallOption) (*ResponseUpdateImage, error) {
+	out := new(ResponseUpdateImage)
+	err := c.cc.Invoke(ctx, CloudIdeService_UpdateImage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cloudIdeServiceClient) RunningWorkspaces(ctx context.Context, in *RequestRunningWorkspaces, opts ...grpc.CallOption) (*ResponseRunningWorkspace, error) {
 	out := new(ResponseRunningWorkspace)
 	err := c.cc.Invoke(ctx, CloudIdeService_RunningWorkspaces_FullMethodName, in, out, opts...)
@@ -95,6 +146,51 @@ func (c *cloudIdeServiceClient) RunningWorkspaces(ctx context.Context, in *Reque
 	return out, nil
 }
 
+func (c *cloudIdeServiceClient) CreateImagePullSecret(ctx context.Context, in *RequestImagePullSecret, opts ...grpc.CallOption) (*ResponseImagePullSecret, error) {
+	out := new(ResponseImagePullSecret)
+	err := c.cc.Invoke(ctx, CloudIdeService_CreateImagePullSecret_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudIdeServiceClient) DeleteImagePullSecret(ctx context.Context, in *RequestDeleteImagePullSecret, opts ...grpc.CallOption) (*ResponseDeleteImagePullSecret, error) {
+	out := new(ResponseDeleteImagePullSecret)
+	err := c.cc.Invoke(ctx, CloudIdeService_DeleteImagePullSecret_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudIdeServiceClient) SetPrePullImages(ctx context.Context, in *RequestSetPrePullImages, opts ...grpc.CallOption) (*ResponseSetPrePullImages, error) {
+	out := new(ResponseSetPrePullImages)
+	err := c.cc.Invoke(ctx, CloudIdeService_SetPrePullImages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudIdeServiceClient) PrePullStatus(ctx context.Context, in *RequestPrePullStatus, opts ...grpc.CallOption) (*ResponsePrePullStatus, error) {
+	out := new(ResponsePrePullStatus)
+	err := c.cc.Invoke(ctx, CloudIdeService_PrePullStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cloudIdeServiceClient) GetWorkspaceEvents(ctx context.Context, in *RequestWorkspaceEvents, opts ...grpc.CallOption) (*ResponseWorkspaceEvents, error) {
+	out := new(ResponseWorkspaceEvents)
+	err := c.cc.Invoke(ctx, CloudIdeService_GetWorkspaceEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CloudIdeServiceServer is the server API for CloudIdeService service.
 // All implementations must embed UnimplementedCloudIdeServiceServer
 // for forward compatibility
@@ -107,8 +203,24 @@ type CloudIdeServiceServer interface {
 	DeleteSpace(context.Context, *RequestDelete) (*ResponseDelete, error)
 	// 停止(删除)云工作空间,无需删除存储卷
 	StopSpace(context.Context, *RequestStop) (*ResponseStop, error)
+	// 取消计划的停止
+	CancelStop(context.Context, *RequestCancelStop) (*ResponseCancelStop, error)
+	// 设置定时启动和停止
+	SetSchedule(context.Context, *RequestSetSchedule) (*ResponseSetSchedule, error)
+	// 修改工作空间的镜像
+	UpdateImage(context.Context, *RequestUpdateImage) (*ResponseUpdateImage, error)
 	// 获取运行中的Workspace
 	RunningWorkspaces(context.Context, *RequestRunningWorkspaces) (*ResponseRunningWorkspace, error)
+	// 创建或更新用户拉取私有镜像使用的Secret
+	CreateImagePullSecret(context.Context, *RequestImagePullSecret) (*ResponseImagePullSecret, error)
+	// 删除用户拉取私有镜像使用的Secret
+	DeleteImagePullSecret(context.Context, *RequestDeleteImagePullSecret) (*ResponseDeleteImagePullSecret, error)
+	// 设置需要预先拉取到所有节点上的镜像
+	SetPrePullImages(context.Context, *RequestSetPrePullImages) (*ResponseSetPrePullImages, error)
+	// 获取镜像在各个节点上的拉取状态
+	PrePullStatus(context.Context, *RequestPrePullStatus) (*ResponsePrePullStatus, error)
+	// 获取工作空间的事件,用于在页面上展示启动过程和失败原因
+	GetWorkspaceEvents(context.Context, *RequestWorkspaceEvents) (*ResponseWorkspaceEvents, error)
 	mustEmbedUnimplementedCloudIdeServiceServer()
 }
 
@@ -128,9 +240,33 @@ func (UnimplementedCloudIdeServiceServer) DeleteSpace(context.Context, *RequestD
 func (UnimplementedCloudIdeServiceServer) StopSpace(context.Context, *RequestStop) (*ResponseStop, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopSpace not implemented")
 }
+func (UnimplementedCloudIdeServiceServer) CancelStop(context.Context, *RequestCancelStop) (*ResponseCancelStop, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelStop not implemented")
+}
+func (UnimplementedCloudIdeServiceServer) SetSchedule(context.Context, *RequestSetSchedule) (*ResponseSetSchedule, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSchedule not implemented")
+}
+func (UnimplementedCloudIdeServiceServer) UpdateImage(context.Context, *RequestUpdateImage) (*ResponseUpdateImage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateImage not implemented")
+}
 func (UnimplementedCloudIdeServiceServer) RunningWorkspaces(context.Context, *RequestRunningWorkspaces) (*ResponseRunningWorkspace, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RunningWorkspaces not implemented")
 }
+func (UnimplementedCloudIdeServiceServer) CreateImagePullSecret(context.Context, *RequestImagePullSecret) (*ResponseImagePullSecret, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateImagePullSecret not implemented")
+}
+func (UnimplementedCloudIdeServiceServer) DeleteImagePullSecret(context.Context, *RequestDeleteImagePullSecret) (*ResponseDeleteImagePullSecret, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteImagePullSecret not implemented")
+}
+func (UnimplementedCloudIdeServiceServer) SetPrePullImages(context.Context, *RequestSetPrePullImages) (*ResponseSetPrePullImages, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPrePullImages not implemented")
+}
+func (UnimplementedCloudIdeServiceServer) PrePullStatus(context.Context, *RequestPrePullStatus) (*ResponsePrePullStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrePullStatus not implemented")
+}
+func (UnimplementedCloudIdeServiceServer) GetWorkspaceEvents(context.Context, *RequestWorkspaceEvents) (*ResponseWorkspaceEvents, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspaceEvents not implemented")
+}
 func (UnimplementedCloudIdeServiceServer) mustEmbedUnimplementedCloudIdeServiceServer() {}
 
 // UnsafeCloudIdeServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -216,6 +352,60 @@ func _CloudIdeService_StopSpace_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CloudIdeService_CancelStop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestCancelStop)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).CancelStop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_CancelStop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).CancelStop(ctx, req.(*RequestCancelStop))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudIdeService_SetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestSetSchedule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).SetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_SetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).SetSchedule(ctx, req.(*RequestSetSchedule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudIdeService_UpdateImage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestUpdateImage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).UpdateImage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_UpdateImage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).UpdateImage(ctx, req.(*RequestUpdateImage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CloudIdeService_RunningWorkspaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RequestRunningWorkspaces)
 	if err := dec(in); err != nil {
@@ -234,6 +424,96 @@ func _CloudIdeService_RunningWorkspaces_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CloudIdeService_CreateImagePullSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestImagePullSecret)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).CreateImagePullSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_CreateImagePullSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).CreateImagePullSecret(ctx, req.(*RequestImagePullSecret))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudIdeService_DeleteImagePullSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestDeleteImagePullSecret)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).DeleteImagePullSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_DeleteImagePullSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).DeleteImagePullSecret(ctx, req.(*RequestDeleteImagePullSecret))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudIdeService_SetPrePullImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestSetPrePullImages)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).SetPrePullImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_SetPrePullImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).SetPrePullImages(ctx, req.(*RequestSetPrePullImages))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudIdeService_PrePullStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPrePullStatus)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).PrePullStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_PrePullStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).PrePullStatus(ctx, req.(*RequestPrePullStatus))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CloudIdeService_GetWorkspaceEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestWorkspaceEvents)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CloudIdeServiceServer).GetWorkspaceEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CloudIdeService_GetWorkspaceEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CloudIdeServiceServer).GetWorkspaceEvents(ctx, req.(*RequestWorkspaceEvents))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CloudIdeService_ServiceDesc is the grpc.ServiceDesc for CloudIdeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -257,10 +537,42 @@ var CloudIdeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "stopSpace",
 			Handler:    _CloudIdeService_StopSpace_Handler,
 		},
+		{
+			MethodName: "cancelStop",
+			Handler:    _CloudIdeService_CancelStop_Handler,
+		},
+		{
+			MethodName: "setSchedule",
+			Handler:    _CloudIdeService_SetSchedule_Handler,
+		},
+		{
+			MethodName: "updateImage",
+			Handler:    _CloudIdeService_UpdateImage_Handler,
+		},
 		{
 			MethodName: "runningWorkspaces",
 			Handler:    _CloudIdeService_RunningWorkspaces_Handler,
 		},
+		{
+			MethodName: "createImagePullSecret",
+			Handler:    _CloudIdeService_CreateImagePullSecret_Handler,
+		},
+		{
+			MethodName: "deleteImagePullSecret",
+			Handler:    _CloudIdeService_DeleteImagePullSecret_Handler,
+		},
+		{
+			MethodName: "setPrePullImages",
+			Handler:    _CloudIdeService_SetPrePullImages_Handler,
+		},
+		{
+			MethodName: "prePullStatus",
+			Handler:    _CloudIdeService_PrePullStatus_Handler,
+		},
+		{
+			MethodName: "getWorkspaceEvents",
+			Handler:    _CloudIdeService_GetWorkspaceEvents_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pb/proto/service.proto",
@@ -0,0 +1,369 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+	// DockerHubAuthKey 在dockerconfigjson中Docker Hub使用的key
+	DockerHubAuthKey = "https://index.docker.io/v1/"
+)
+
+// realmHosts 镜像仓库与其认证服务不在同一个域名下时, 允许接收用户凭证的认证服务域名
+var realmHosts = map[string][]string{
+	dockerHubRegistry: {"auth.docker.io"},
+}
+
+var (
+	ErrImageNotFound     = errors.New("image not found")
+	ErrUnauthorized      = errors.New("registry unauthorized")
+	ErrAddressNotAllowed = errors.New("registry address not allowed")
+)
+
+// Auth 镜像仓库的认证信息
+type Auth struct {
+	Username string
+	Password string
+}
+
+// Reference 解析后的镜像引用
+type Reference struct {
+	Domain     string // 镜像仓库域名, eg. docker.io
+	Repository string // 仓库路径, eg. library/golang
+	Reference  string // tag或digest, eg. latest
+}
+
+// ParseReference 解析镜像引用, 规则和docker保持一致:
+// 第一部分包含'.'或':'或为localhost时才被认为是域名, 否则为Docker Hub中的镜像
+func ParseReference(image string) Reference {
+	ref := Reference{Domain: DockerHubDomain, Reference: "latest"}
+
+	name := image
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	} else if i := strings.LastIndexByte(name, ':'); i >= 0 && !strings.ContainsRune(name[i+1:], '/') {
+		ref.Reference = name[i+1:]
+		name = name[:i]
+	}
+
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Domain = first
+			name = name[i+1:]
+		}
+	}
+
+	if ref.Domain == DockerHubDomain && !strings.ContainsRune(name, '/') {
+		name = "library/" + name
+	}
+	ref.Repository = name
+
+	return ref
+}
+
+// AuthKey 返回该镜像仓库在dockerconfigjson中使用的key
+func (r Reference) AuthKey() string {
+	if r.Domain == DockerHubDomain {
+		return DockerHubAuthKey
+	}
+
+	return r.Domain
+}
+
+func (r Reference) registryHost() string {
+	if r.Domain == DockerHubDomain {
+		return dockerHubRegistry
+	}
+
+	return r.Domain
+}
+
+// Checker 通过镜像仓库的v2 API检查镜像是否可以被拉取, 只获取manifest而不拉取镜像层
+// 镜像仓库的地址以及认证时WWW-Authenticate中的realm都由用户控制, 为了避免被用来访问集群内部的服务,
+// 建立连接前会解析域名, 拒绝回环地址、链路本地地址以及私有地址, 包括重定向之后的地址
+type Checker struct {
+	client *http.Client
+	// 访问镜像仓库使用的协议, 默认为https
+	scheme string
+	// 允许访问内网地址, 仅用于测试
+	allowPrivate bool
+	// 额外信任的认证服务域名
+	trustedRealms map[string]bool
+}
+
+// NewChecker realmHosts为额外信任的认证服务域名, 只有realm的域名为镜像仓库本身或受信任的域名时才会发送用户凭证
+func NewChecker(timeout time.Duration, realmHosts ...string) *Checker {
+	c := &Checker{scheme: "https", trustedRealms: make(map[string]bool, len(realmHosts))}
+	for _, host := range realmHosts {
+		c.trustedRealms[strings.ToLower(host)] = true
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	c.client = &http.Client{
+		Timeout: timeout,
+		// 不使用环境变量中的代理, 否则只能检查代理的地址
+		Transport: &http.Transport{
+			DialContext:         c.dialContext(dialer),
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+
+	return c
+}
+
+// 解析域名并检查所有地址, 然后直接连接检查过的地址, 避免检查之后域名被重新解析到内网地址
+func (c *Checker) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("no address for %s", host)
+		}
+		for _, ip := range ips {
+			if !c.allowPrivate && !IsPublicIP(ip.IP) {
+				return nil, fmt.Errorf("%w: %s resolves to %s", ErrAddressNotAllowed, host, ip.IP)
+			}
+		}
+
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ips[0].IP.String(), port))
+	}
+}
+
+// cgnat 运营商级NAT使用的地址段, 云厂商常用于元数据服务等内部服务
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP 判断是否为公网地址
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	return !cgnat.Contains(ip)
+}
+
+var manifestAccepts = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Check 检查镜像是否存在并且使用auth可以拉取, auth为nil时匿名访问
+func (c *Checker) Check(ctx context.Context, image string, auth *Auth) error {
+	ref := ParseReference(image)
+	u := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", c.scheme, ref.registryHost(), ref.Repository, ref.Reference)
+
+	resp, err := c.headManifest(ctx, u, "")
+	if err != nil {
+		return unwrapAddressError(err)
+	}
+
+	// 需要认证, 根据WWW-Authenticate获取token后重试
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := c.authorize(ctx, resp.Header.Get("WWW-Authenticate"), ref, auth)
+		if err != nil {
+			return unwrapAddressError(err)
+		}
+		resp, err = c.headManifest(ctx, u, authorization)
+		if err != nil {
+			return unwrapAddressError(err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrImageNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	}
+
+	return fmt.Errorf("check image %s: unexpected status %d", image, resp.StatusCode)
+}
+
+// 地址被拒绝时返回ErrAddressNotAllowed, 而不是http.Client包装之后的错误
+func unwrapAddressError(err error) error {
+	if errors.Is(err, ErrAddressNotAllowed) {
+		return ErrAddressNotAllowed
+	}
+
+	return err
+}
+
+func (c *Checker) headManifest(ctx context.Context, u, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestAccepts, ","))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return resp, nil
+}
+
+// authorize 根据仓库返回的认证方式生成Authorization请求头
+func (c *Checker) authorize(ctx context.Context, challenge string, ref Reference, auth *Auth) (string, error) {
+	scheme, params := ParseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if auth == nil {
+			return "", ErrUnauthorized
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(auth.Username, auth.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, ref, auth)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	}
+
+	return "", fmt.Errorf("unsupported auth scheme %q", scheme)
+}
+
+func (c *Checker) fetchToken(ctx context.Context, params map[string]string, ref Reference, auth *Auth) (string, error) {
+	realm, ok := params["realm"]
+	if !ok {
+		return "", errors.New("bearer challenge without realm")
+	}
+
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "https" && u.Scheme != c.scheme {
+		return "", fmt.Errorf("unsupported realm %q", realm)
+	}
+	q := u.Query()
+	if service, ok := params["service"]; ok {
+		q.Set("service", service)
+	}
+	scope, ok := params["scope"]
+	if !ok {
+		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	q.Set("scope", scope)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	// realm由镜像仓库返回, 不受信任时匿名获取token, 避免把用户凭证发送给第三方
+	if auth != nil && c.trustedRealm(u.Host, ref) {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch token: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
+
+// trustedRealm 判断是否可以向realm发送用户凭证
+func (c *Checker) trustedRealm(host string, ref Reference) bool {
+	host = strings.ToLower(host)
+	registry := strings.ToLower(ref.registryHost())
+	if host == registry || c.trustedRealms[host] {
+		return true
+	}
+	for _, h := range realmHosts[registry] {
+		if host == h {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseChallenge 解析WWW-Authenticate请求头
+// eg. Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func ParseChallenge(header string) (string, map[string]string) {
+	params := make(map[string]string)
+	header = strings.TrimSpace(header)
+	i := strings.IndexByte(header, ' ')
+	if i < 0 {
+		return header, params
+	}
+
+	scheme, rest := header[:i], header[i+1:]
+	for len(rest) > 0 {
+		rest = strings.TrimLeft(rest, " ,")
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = rest[eq+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				value, rest = rest, ""
+			} else {
+				value, rest = rest[:end], rest[end:]
+			}
+		}
+		params[key] = value
+	}
+
+	return scheme, params
+}
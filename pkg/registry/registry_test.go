@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		image string
+		want  Reference
+	}{
+		{"golang", Reference{"docker.io", "library/golang", "latest"}},
+		{"golang:1.21", Reference{"docker.io", "library/golang", "1.21"}},
+		{"mangohow/code-server-go:v1.21", Reference{"docker.io", "mangohow/code-server-go", "v1.21"}},
+		{"registry.cn-hangzhou.aliyuncs.com/k8s-cloud-ide/code-server-go:v1.21",
+			Reference{"registry.cn-hangzhou.aliyuncs.com", "k8s-cloud-ide/code-server-go", "v1.21"}},
+		{"localhost:5000/ide", Reference{"localhost:5000", "ide", "latest"}},
+		{"localhost/ide@sha256:abc", Reference{"localhost", "ide", "sha256:abc"}},
+	}
+
+	for _, c := range cases {
+		if got := ParseReference(c.image); got != c.want {
+			t.Errorf("ParseReference(%q) = %+v, want %+v", c.image, got, c.want)
+		}
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := ParseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/golang:pull"`)
+	if scheme != "Bearer" {
+		t.Fatalf("scheme = %s", scheme)
+	}
+	if params["realm"] != "https://auth.docker.io/token" || params["service"] != "registry.docker.io" ||
+		params["scope"] != "repository:library/golang:pull" {
+		t.Fatalf("params = %v", params)
+	}
+}
+
+func TestCheckerBearer(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token":"abc"}`)
+		case strings.HasPrefix(r.URL.Path, "/v2/private/ide/manifests/"):
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if strings.HasSuffix(r.URL.Path, "/missing") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewChecker(time.Second * 5)
+	c.scheme = "http"
+	c.allowPrivate = true
+	host := strings.TrimPrefix(srv.URL, "http://")
+	ctx := context.Background()
+
+	if err := c.Check(ctx, host+"/private/ide:v1", &Auth{Username: "user", Password: "pass"}); err != nil {
+		t.Errorf("check with auth: %v", err)
+	}
+	if err := c.Check(ctx, host+"/private/ide:v1", &Auth{Username: "user", Password: "wrong"}); err != ErrUnauthorized {
+		t.Errorf("check with wrong auth: got %v, want %v", err, ErrUnauthorized)
+	}
+	if err := c.Check(ctx, host+"/private/ide:missing", &Auth{Username: "user", Password: "pass"}); err != ErrImageNotFound {
+		t.Errorf("check missing tag: got %v, want %v", err, ErrImageNotFound)
+	}
+}
+
+func TestCheckerUntrustedRealm(t *testing.T) {
+	var sent bool
+	realm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, sent = r.BasicAuth()
+		fmt.Fprint(w, `{"token":"abc"}`)
+	}))
+	defer realm.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, realm.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	realmHost := strings.TrimPrefix(realm.URL, "http://")
+	auth := &Auth{Username: "user", Password: "pass"}
+	tests := []struct {
+		name       string
+		realmHosts []string
+		wantSent   bool
+	}{
+		{name: "untrusted realm", wantSent: false},
+		{name: "trusted realm", realmHosts: []string{realmHost}, wantSent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = false
+			c := NewChecker(time.Second*5, tt.realmHosts...)
+			c.scheme = "http"
+			c.allowPrivate = true
+			if err := c.Check(context.Background(), host+"/private/ide:v1", auth); err != nil {
+				t.Fatalf("check: %v", err)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("credentials sent to realm: got %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestCheckerRejectsPrivateAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	}))
+	defer srv.Close()
+
+	c := NewChecker(time.Second * 5)
+	c.scheme = "http"
+	host := strings.TrimPrefix(srv.URL, "http://")
+	if err := c.Check(context.Background(), host+"/private/ide:v1", nil); err != ErrAddressNotAllowed {
+		t.Errorf("check loopback registry: got %v, want %v", err, ErrAddressNotAllowed)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":          true,
+		"2001:4860::8888":  true,
+		"127.0.0.1":        false,
+		"10.96.0.1":        false,
+		"172.16.3.4":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.100.100.200":  false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"::ffff:127.0.0.1": false,
+	}
+
+	for addr, want := range cases {
+		if got := IsPublicIP(net.ParseIP(addr)); got != want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
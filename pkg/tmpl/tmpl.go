@@ -19,6 +19,8 @@ type Config struct {
 	ServerKey         string
 	WebServiceName    string
 	WebPort           int
+	// 为没有链路追踪上下文的请求生成trace时的采样比例
+	TraceSampleRatio float64
 }
 
 func ApplyNginxConf(cfg *Config, ngxPath string) error {
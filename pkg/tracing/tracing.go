@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// Options 链路追踪的配置
+type Options struct {
+	// 服务名称, 例如 webserver、control-plane
+	ServiceName string
+	// OTLP/HTTP collector的地址, 例如 http://otel-collector:4318, 为空时不导出span, 但仍然传递链路追踪的上下文
+	Endpoint string
+	// 采样比例, 小于等于0或者大于等于1时全部采样, 上游已经采样的请求总是采样
+	SampleRatio float64
+}
+
+// Propagator webserver、control-plane之间以及在资源注解中传递链路追踪上下文使用的格式
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Init 设置全局的TracerProvider和Propagator, 返回的函数用于在退出前导出剩余的span
+func Init(opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(Propagator)
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporterOpts, err := exporterOptions(opts.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := otlptracehttp.New(context.Background(), exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+	provider := NewProvider(opts, exporter)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// OTLPTracesPath OTLP/HTTP接收span的默认路径
+const OTLPTracesPath = "/v1/traces"
+
+// 将collector的地址转换为exporter的配置, 没有指定路径时使用/v1/traces, http协议的地址不使用TLS
+func exporterOptions(endpoint string) ([]otlptracehttp.Option, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid otlp endpoint %q, expected http(s)://host:port", endpoint)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "" {
+		path = OTLPTracesPath
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	return opts, nil
+}
+
+// NewProvider 使用exporter创建TracerProvider, 测试时可以使用tracetest.InMemoryExporter
+func NewProvider(opts Options, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(opts.ServiceName))),
+	)
+}
+
+// AnnotationPrefix 链路追踪上下文保存在资源注解中时的前缀, 例如 cloud-ide.mangohow.com/traceparent
+// 控制器异步处理工作空间时, 通过注解找到发起启动请求的trace
+const AnnotationPrefix = "cloud-ide.mangohow.com/"
+
+// AnnotationCarrier 将资源的注解作为链路追踪上下文的载体
+type AnnotationCarrier map[string]string
+
+func (a AnnotationCarrier) Get(key string) string {
+	return a[AnnotationPrefix+key]
+}
+
+func (a AnnotationCarrier) Set(key, value string) {
+	a[AnnotationPrefix+key] = value
+}
+
+func (a AnnotationCarrier) Keys() []string {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		if strings.HasPrefix(k, AnnotationPrefix) {
+			keys = append(keys, strings.TrimPrefix(k, AnnotationPrefix))
+		}
+	}
+
+	return keys
+}
+
+// InjectAnnotations 将ctx中的链路追踪上下文写入注解, annotations为nil时创建新的map
+// 之前的请求写入的上下文会被移除, 避免控制器的span关联到旧的trace
+func InjectAnnotations(ctx context.Context, annotations map[string]string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for _, field := range Propagator.Fields() {
+		delete(annotations, AnnotationPrefix+field)
+	}
+	Propagator.Inject(ctx, AnnotationCarrier(annotations))
+
+	return annotations
+}
+
+// ExtractAnnotations 从注解中恢复链路追踪上下文
+func ExtractAnnotations(ctx context.Context, annotations map[string]string) context.Context {
+	if len(annotations) == 0 {
+		return ctx
+	}
+
+	return Propagator.Extract(ctx, AnnotationCarrier(annotations))
+}
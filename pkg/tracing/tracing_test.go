@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExporter(t *testing.T) {
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("content type = %s", r.Header.Get("Content-Type"))
+		}
+		paths <- r.URL.Path
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		path     string
+	}{
+		{name: "default path", endpoint: server.URL, path: OTLPTracesPath},
+		{name: "custom path", endpoint: server.URL + "/otlp/v1/traces/", path: "/otlp/v1/traces"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := exporterOptions(tt.endpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exporter, err := otlptracehttp.New(context.Background(), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			_, span := provider.Tracer("cloud-ide").Start(context.Background(), "createPod")
+			span.End()
+			if path := <-paths; path != tt.path {
+				t.Errorf("path = %s, want %s", path, tt.path)
+			}
+		})
+	}
+
+	for _, endpoint := range []string{"otel-collector:4318", "grpc://otel-collector:4317", "http://"} {
+		if _, err := exporterOptions(endpoint); err == nil {
+			t.Errorf("endpoint %q should be invalid", endpoint)
+		}
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, span := provider.Tracer("cloud-ide").Start(context.Background(), "StartSpace")
+	span.End()
+
+	annotations := InjectAnnotations(ctx, map[string]string{"sid": "space01"})
+	if annotations[AnnotationPrefix+"traceparent"] == "" || annotations["sid"] != "space01" {
+		t.Fatalf("annotations = %v", annotations)
+	}
+
+	// 从注解中恢复上下文后创建的span属于同一个trace
+	_, child := provider.Tracer("cloud-ide").Start(ExtractAnnotations(context.Background(), annotations), "createPod")
+	child.End()
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[1].Parent.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("child span is not linked to the request, spans: %v", spans)
+	}
+
+	// 没有链路追踪上下文时移除之前的请求写入的注解
+	annotations = InjectAnnotations(context.Background(), annotations)
+	if _, ok := annotations[AnnotationPrefix+"traceparent"]; ok {
+		t.Errorf("stale traceparent remains: %v", annotations)
+	}
+}
@@ -0,0 +1,66 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+var ErrCipherTextInvalid = errors.New("cipher text invalid")
+
+// AesGcm 使用AES-256-GCM对数据进行加密和解密, 密钥由sha256(key)生成
+type AesGcm struct {
+	aead cipher.AEAD
+}
+
+func NewAesGcm(key string) (*AesGcm, error) {
+	if key == "" {
+		return nil, errors.New("encrypt key is empty")
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AesGcm{aead: aead}, nil
+}
+
+// Encrypt 加密, 返回base64编码的nonce+密文
+func (a *AesGcm) Encrypt(plain string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := a.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密Encrypt生成的密文
+func (a *AesGcm) Decrypt(encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrCipherTextInvalid
+	}
+	size := a.aead.NonceSize()
+	if len(data) < size {
+		return "", ErrCipherTextInvalid
+	}
+
+	plain, err := a.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return "", ErrCipherTextInvalid
+	}
+
+	return string(plain), nil
+}
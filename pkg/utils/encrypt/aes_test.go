@@ -0,0 +1,27 @@
+package encrypt
+
+import "testing"
+
+func TestAesGcm(t *testing.T) {
+	a, err := NewAesGcm("cloud-ide")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := a.Encrypt("ghp_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(encrypted)
+
+	plain, err := a.Decrypt(encrypted)
+	if err != nil || plain != "ghp_token" {
+		t.Fatalf("decrypt: %s, %v", plain, err)
+	}
+
+	// 使用不同的密钥无法解密
+	b, _ := NewAesGcm("other")
+	if _, err := b.Decrypt(encrypted); err != ErrCipherTextInvalid {
+		t.Fatalf("decrypt with other key: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// https://host[:port]/path/repo[.git]
+	gitHttpsRegexp = regexp.MustCompile(`^https://([\w.-]+(?::[0-9]+)?)/[\w.-]+(?:/[\w.-]+)*$`)
+	// git@host:org/repo.git
+	gitScpRegexp = regexp.MustCompile(`^[\w.-]+@([\w.-]+):[\w.-]+(?:/[\w.-]+)*\.git$`)
+	// ssh://git@host[:port]/org/repo.git
+	gitSshRegexp = regexp.MustCompile(`^ssh://[\w.-]+@([\w.-]+)(?::[0-9]+)?/[\w.-]+(?:/[\w.-]+)*\.git$`)
+	// 分支、标签或者commit
+	gitRefRegexp = regexp.MustCompile(`^[\w][\w./-]{0,254}$`)
+	// 仓库在工作目录下的克隆路径
+	repositoryPathRegexp = regexp.MustCompile(`^[\w.-]+(/[\w.-]+)*$`)
+)
+
+// VerifyGitRepository 校验git仓库地址, 支持https和ssh两种形式
+func VerifyGitRepository(url string) bool {
+	if len(url) > 255 || strings.Contains(url, "..") {
+		return false
+	}
+
+	return GitRepositoryHost(url) != ""
+}
+
+// IsSshGitRepository 是否为通过ssh访问的git仓库
+func IsSshGitRepository(url string) bool {
+	return gitScpRegexp.MatchString(url) || gitSshRegexp.MatchString(url)
+}
+
+// GitRepositoryHost 获取git仓库的主机名, 地址不合法时返回空字符串
+func GitRepositoryHost(url string) string {
+	for _, r := range []*regexp.Regexp{gitHttpsRegexp, gitScpRegexp, gitSshRegexp} {
+		if m := r.FindStringSubmatch(url); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
+
+// GitRepositoryName 获取git仓库的名称, 例如git@github.com:org/repo.git -> repo
+func GitRepositoryName(url string) string {
+	idx := strings.LastIndexAny(url, "/:") + 1
+	return strings.TrimSuffix(url[idx:], ".git")
+}
+
+// VerifyGitRef 校验分支、标签或者commit的格式
+func VerifyGitRef(ref string) bool {
+	if !gitRefRegexp.MatchString(ref) {
+		return false
+	}
+
+	return !strings.Contains(ref, "..") && !strings.HasSuffix(ref, "/") &&
+		!strings.HasSuffix(ref, ".lock") && !strings.Contains(ref, "//")
+}
+
+// VerifyRepositoryPath 校验仓库的克隆路径, 只能是工作目录下的相对路径, 不能包含.和..
+func VerifyRepositoryPath(p string) bool {
+	if len(p) > 255 || !repositoryPathRegexp.MatchString(p) {
+		return false
+	}
+	for _, elem := range strings.Split(p, "/") {
+		if elem == "." || elem == ".." {
+			return false
+		}
+	}
+
+	return true
+}
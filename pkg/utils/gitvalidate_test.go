@@ -0,0 +1,72 @@
+package utils
+
+import "testing"
+
+func TestGitRepository(t *testing.T) {
+	tests := []struct {
+		url  string
+		host string
+		name string
+		ssh  bool
+	}{
+		{"https://github.com/mangohow/cloud-ide.git", "github.com", "cloud-ide", false},
+		{"https://gitlab.example.com:8443/group/sub/repo", "gitlab.example.com:8443", "repo", false},
+		{"git@github.com:mangohow/cloud-ide.git", "github.com", "cloud-ide", true},
+		{"git@gitee.com:repo.git", "gitee.com", "repo", true},
+		{"ssh://git@gitlab.example.com:2222/group/repo.git", "gitlab.example.com", "repo", true},
+	}
+	for _, test := range tests {
+		if !VerifyGitRepository(test.url) {
+			t.Errorf("%s should be valid", test.url)
+		}
+		if host := GitRepositoryHost(test.url); host != test.host {
+			t.Errorf("%s host: %s", test.url, host)
+		}
+		if name := GitRepositoryName(test.url); name != test.name {
+			t.Errorf("%s name: %s", test.url, name)
+		}
+		if IsSshGitRepository(test.url) != test.ssh {
+			t.Errorf("%s ssh: %v", test.url, !test.ssh)
+		}
+	}
+
+	invalid := []string{
+		"http://github.com/org/repo.git",
+		"https://github.com/org/repo.git; rm -rf /",
+		"git@github.com:org/repo",
+		"file:///etc/passwd",
+		"https://github.com/../repo.git",
+		"--upload-pack=touch /tmp/x",
+	}
+	for _, url := range invalid {
+		if VerifyGitRepository(url) {
+			t.Errorf("%s should be invalid", url)
+		}
+	}
+}
+
+func TestVerifyGitRef(t *testing.T) {
+	for _, ref := range []string{"main", "release/v1.0", "v1.2.3", "3f786850e387550fdab836ed7e6dc881de23001b"} {
+		if !VerifyGitRef(ref) {
+			t.Errorf("%s should be valid", ref)
+		}
+	}
+	for _, ref := range []string{"", "-b", "a..b", "feature/", "main.lock", "a b", "--upload-pack=x"} {
+		if VerifyGitRef(ref) {
+			t.Errorf("%s should be invalid", ref)
+		}
+	}
+}
+
+func TestVerifyRepositoryPath(t *testing.T) {
+	for _, p := range []string{"repo", "src/cloud-ide", "a.b/c-d", "..repo"} {
+		if !VerifyRepositoryPath(p) {
+			t.Errorf("%s should be valid", p)
+		}
+	}
+	for _, p := range []string{"", "..", "../x", "a/../../x", "/etc", "./repo", "a/./b", "a//b", "a/", "a b"} {
+		if VerifyRepositoryPath(p) {
+			t.Errorf("%s should be invalid", p)
+		}
+	}
+}
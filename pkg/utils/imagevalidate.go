@@ -0,0 +1,20 @@
+package utils
+
+import "regexp"
+
+// 镜像引用格式: [domain[:port]/]path[:tag][@digest]
+// 参考 https://github.com/distribution/reference/blob/main/reference.go
+var imageReferenceRegexp = regexp.MustCompile(
+	`^(?:(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?))*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*)*` +
+		`(?::[\w][\w.-]{0,127})?` +
+		`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+
+// VerifyImageReference 校验镜像引用格式是否合法
+func VerifyImageReference(image string) bool {
+	if len(image) == 0 || len(image) > 255 {
+		return false
+	}
+
+	return imageReferenceRegexp.MatchString(image)
+}
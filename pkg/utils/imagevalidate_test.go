@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		valid bool
+	}{
+		{"code-server", true},
+		{"code-server:go1.20", true},
+		{"mangohow/code-server:v1.0", true},
+		{"registry.example.com:5000/ide/code-server:go", true},
+		{"code-server@sha256:" + strings.Repeat("a", 64), true},
+		{"", false},
+		{"Code-Server", false},
+		{"code server", false},
+		{"code-server:", false},
+		{"code-server:go:1.20", false},
+		{"-code-server", false},
+		{"registry.example.com/" + strings.Repeat("a", 255), false},
+	}
+
+	for _, test := range tests {
+		if got := VerifyImageReference(test.image); got != test.valid {
+			t.Errorf("VerifyImageReference(%q) = %v, want %v", test.image, got, test.valid)
+		}
+	}
+}